@@ -0,0 +1,158 @@
+// Package a11y checks rendered HTML for common accessibility problems:
+// form controls without labels, toggles without state, unnamed buttons,
+// duplicate IDs, dangling ARIA references and skipped heading levels.
+//
+// It works on anything that renders HTML, so it fits in ordinary tests:
+//
+//	func TestDialogAccessible(t *testing.T) {
+//	    a11y.AssertComponent(t, modal.Modal(modal.Props{ID: "confirm"}))
+//	}
+//
+// Reports can be written as JSON or JUnit XML for CI.
+package a11y
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/a-h/templ"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Severity says whether an issue fails a check.
+type Severity string
+
+const (
+	// SeverityError marks issues that make content unusable with assistive
+	// technology. They fail Assert.
+	SeverityError Severity = "error"
+	// SeverityWarning marks issues that are likely but not certainly wrong,
+	// such as references to IDs outside a rendered fragment.
+	SeverityWarning Severity = "warning"
+)
+
+// Issue is one rule violation.
+type Issue struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	// Path locates the element, such as "div#menu > ul > li > a".
+	Path string `json:"path"`
+	// Element is the element's opening tag.
+	Element string `json:"element"`
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s [%s] %s: %s\n\t%s", i.Severity, i.Rule, i.Path, i.Message, i.Element)
+}
+
+// Options configures a check.
+type Options struct {
+	// Rules replaces the rule set. Defaults to Rules.
+	Rules []Rule
+	// Disable turns off rules by name, such as "heading-order".
+	Disable []string
+	// Source names what was checked in the report, such as a component or
+	// URL.
+	Source string
+}
+
+func resolveOptions(opts []Options) Options {
+	var o Options
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if o.Rules == nil {
+		o.Rules = Rules
+	}
+	return o
+}
+
+// Check parses markup and runs the rules against it. Markup starting with
+// a doctype or <html> is parsed as a document, anything else as a fragment
+// of <body>.
+func Check(markup string, opts ...Options) (Report, error) {
+	o := resolveOptions(opts)
+	root, err := parse(markup)
+	if err != nil {
+		return Report{}, fmt.Errorf("a11y: parsing HTML: %w", err)
+	}
+	doc := newDocument(root)
+	report := Report{Source: o.Source, Issues: []Issue{}}
+	for _, rule := range o.Rules {
+		if slices.Contains(o.Disable, rule.Name) {
+			continue
+		}
+		report.Rules = append(report.Rules, rule.Name)
+		rule.Check(doc, func(n *html.Node, severity Severity, format string, args ...any) {
+			report.Issues = append(report.Issues, Issue{
+				Rule:     rule.Name,
+				Severity: severity,
+				Message:  fmt.Sprintf(format, args...),
+				Path:     path(n),
+				Element:  openingTag(n),
+			})
+		})
+	}
+	return report, nil
+}
+
+// CheckComponent renders c with ctx and checks the result.
+func CheckComponent(ctx context.Context, c templ.Component, opts ...Options) (Report, error) {
+	var buf bytes.Buffer
+	if err := c.Render(ctx, &buf); err != nil {
+		return Report{}, fmt.Errorf("a11y: rendering component: %w", err)
+	}
+	return Check(buf.String(), opts...)
+}
+
+func parse(markup string) (*html.Node, error) {
+	head := strings.ToLower(strings.TrimSpace(markup))
+	if strings.HasPrefix(head, "<!doctype") || strings.HasPrefix(head, "<html") {
+		return html.Parse(strings.NewReader(markup))
+	}
+	body := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(strings.NewReader(markup), body)
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range nodes {
+		body.AppendChild(n)
+	}
+	return body, nil
+}
+
+// path describes where n is, using tag names, IDs and the first class.
+func path(n *html.Node) string {
+	var parts []string
+	for ; n != nil && n.Type == html.ElementNode; n = n.Parent {
+		part := n.Data
+		if id := attr(n, "id"); id != "" {
+			part += "#" + id
+		} else if class := strings.Fields(attr(n, "class")); len(class) > 0 {
+			part += "." + class[0]
+		}
+		parts = append(parts, part)
+	}
+	slices.Reverse(parts)
+	return strings.Join(parts, " > ")
+}
+
+// openingTag renders n's start tag, shortened for reports.
+func openingTag(n *html.Node) string {
+	var b strings.Builder
+	b.WriteString("<" + n.Data)
+	for _, a := range n.Attr {
+		fmt.Fprintf(&b, " %s=%q", a.Key, a.Val)
+	}
+	b.WriteString(">")
+	s := b.String()
+	if len(s) > 160 {
+		s = s[:157] + "..."
+	}
+	return s
+}
@@ -0,0 +1,188 @@
+package a11y_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/plaenen/webx/a11y"
+)
+
+func rules(r a11y.Report) []string {
+	var out []string
+	for _, i := range r.Issues {
+		out = append(out, string(i.Severity)+":"+i.Rule)
+	}
+	return out
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		markup string
+		want   []string
+	}{
+		{"labelled input", `<label for="e">Email</label><input id="e" type="email">`, nil},
+		{"wrapped input", `<label>Email <input type="email"></label>`, nil},
+		{"aria-label input", `<input aria-label="Search" type="search">`, nil},
+		{"unlabelled input", `<input type="text" name="q">`, []string{"error:control-label"}},
+		{"unlabelled select", `<select name="c"><option>A</option></select>`, []string{"error:control-label"}},
+		{"hidden input", `<input type="hidden" name="csrf">`, nil},
+		{"aria-hidden input", `<input type="checkbox" aria-hidden="true" tabindex="-1">`, nil},
+		{"labelledby input", `<span id="l">Name</span><input aria-labelledby="l">`, nil},
+
+		{"named button", `<button>Save</button>`, nil},
+		{"icon button", `<button><svg></svg></button>`, []string{"error:button-name"}},
+		{"icon button with label", `<button aria-label="Close"><svg></svg></button>`, nil},
+		{"submit input", `<input type="submit">`, nil},
+		{"empty button input", `<input type="button">`, []string{"error:button-name"}},
+
+		{"duplicate id", `<div id="a"></div><span id="a"></span>`, []string{"error:duplicate-id"}},
+
+		{"popup without state", `<button aria-haspopup="menu">Menu</button>`, []string{"error:toggle-state"}},
+		{"popup with state", `<button aria-haspopup="menu" aria-expanded="false">Menu</button>`, nil},
+		{"signal toggle", `<button data-on:click="$nav.open = !$nav.open">Menu</button>`, []string{"error:toggle-state"}},
+		{"signal toggle with bound state", `<button data-on:click="$nav.open = !$nav.open" data-attr:aria-expanded="$nav.open ? 'true' : 'false'">Menu</button>`, nil},
+		{"signal toggle with boolean state", `<button data-on:click="$nav.open = !$nav.open" data-attr:aria-expanded="$nav.open">Menu</button>`, []string{"error:toggle-state"}},
+		{"disclosure with boolean state", `<div role="button" tabindex="0" data-on:click="$a.active = 'x'" data-attr:aria-expanded="$a.active === 'x'">Item</div>`, []string{"error:toggle-state"}},
+		{"disclosure with string state", `<div role="button" tabindex="0" data-on:click="$a.active = 'x'" data-attr:aria-expanded="String($a.active === 'x')">Item</div>`, nil},
+		{"signal toggle with pressed", `<button data-on:click="$bold = !$bold" aria-pressed="false">B</button>`, nil},
+		{"summary toggle", `<details><summary data-on:click__prevent="$m.open = !$m.open">Menu</summary></details>`, nil},
+		{"tab without selected", `<div role="tablist"><a role="tab" href="#a">A</a></div>`, []string{"error:toggle-state"}},
+		{"tab with selected", `<div role="tablist"><a role="tab" href="#a" aria-selected="true">A</a></div>`, nil},
+		{"switch without checked", `<div role="switch" tabindex="0">Dark</div>`, []string{"error:toggle-state"}},
+
+		{"clickable div", `<div data-on:click="$x = 1">Go</div>`, []string{"error:interactive-role"}},
+		{"clickable div with role only", `<div role="button" data-on:click="$x = 1">Go</div>`, []string{"error:interactive-role"}},
+		{"clickable div with role and tabindex", `<div role="button" tabindex="0" data-on:click="$x = 1">Go</div>`, nil},
+		{"click delegation", `<ul data-on:click="$m.open = false"><li><a href="/a">A</a></li></ul>`, nil},
+		{"hidden backdrop", `<div aria-hidden="true" data-on:click="$m.open = false"></div>`, nil},
+		{"outside click", `<div data-on:click__outside="$m.open = false">Menu</div>`, nil},
+
+		{"dangling reference", `<button aria-controls="panel" aria-expanded="false">More</button>`, []string{"warning:aria-reference"}},
+		{"image without alt", `<img src="a.png">`, []string{"error:image-alt"}},
+		{"decorative image", `<img src="a.png" alt="">`, nil},
+		{"heading order", `<h2>A</h2><h3>B</h3><h2>C</h2>`, nil},
+		{"skipped heading", `<h1>A</h1><h3>B</h3>`, []string{"warning:heading-order"}},
+		{"document", `<!DOCTYPE html><html lang="en"><head><title>T</title></head><body><button>Ok</button></body></html>`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := a11y.Check(tt.markup)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := rules(r); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v\n%s", got, tt.want, r)
+			}
+		})
+	}
+}
+
+func TestCheckDisable(t *testing.T) {
+	r, err := a11y.Check(`<img src="a.png"><h1>A</h1><h3>B</h3>`, a11y.Options{Disable: []string{"heading-order"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := rules(r); !slices.Equal(got, []string{"error:image-alt"}) {
+		t.Errorf("got %v", got)
+	}
+	if slices.Contains(r.Rules, "heading-order") {
+		t.Errorf("disabled rule listed as run: %v", r.Rules)
+	}
+}
+
+func TestIssueLocation(t *testing.T) {
+	r, err := a11y.Check(`<form id="login"><div class="row"><input name="user"></div></form>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Issues) != 1 {
+		t.Fatalf("issues = %v", r.Issues)
+	}
+	i := r.Issues[0]
+	if i.Path != "body > form#login > div.row > input" {
+		t.Errorf("Path = %q", i.Path)
+	}
+	if i.Element != `<input name="user">` {
+		t.Errorf("Element = %q", i.Element)
+	}
+}
+
+func TestCheckSSE(t *testing.T) {
+	stream := "event: datastar-patch-elements\n" +
+		"data: selector #result\n" +
+		"data: elements <div id=\"result\"><button><svg></svg></button></div>\n\n" +
+		"event: datastar-patch-signals\n" +
+		"data: signals {\"x\":1}\n\n"
+	r, err := a11y.CheckSSE(stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := rules(r); !slices.Equal(got, []string{"error:button-name"}) {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	r, _ := a11y.Check(`<img src="a.png">`, a11y.Options{Source: "gallery"})
+	var buf bytes.Buffer
+	if err := a11y.WriteJSON(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+	var got []a11y.Report
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Source != "gallery" || len(got[0].Issues) != 1 || got[0].Issues[0].Rule != "image-alt" {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	bad, _ := a11y.Check(`<img src="a.png"><h1>A</h1><h3>B</h3>`, a11y.Options{Source: "bad"})
+	good, _ := a11y.Check(`<button>Ok</button>`, a11y.Options{Source: "good"})
+	var buf bytes.Buffer
+	if err := a11y.WriteJUnit(&buf, bad, good); err != nil {
+		t.Fatal(err)
+	}
+
+	var suites struct {
+		Tests    int `xml:"tests,attr"`
+		Failures int `xml:"failures,attr"`
+		Suites   []struct {
+			Name  string `xml:"name,attr"`
+			Cases []struct {
+				Name      string  `xml:"name,attr"`
+				Failure   *string `xml:"failure"`
+				SystemOut string  `xml:"system-out"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &suites); err != nil {
+		t.Fatalf("%v\n%s", err, buf.String())
+	}
+	n := len(a11y.Rules)
+	if suites.Tests != 2*n || suites.Failures != 1 || len(suites.Suites) != 2 {
+		t.Fatalf("tests=%d failures=%d suites=%d\n%s", suites.Tests, suites.Failures, len(suites.Suites), buf.String())
+	}
+	for _, c := range suites.Suites[0].Cases {
+		switch c.Name {
+		case "image-alt":
+			if c.Failure == nil {
+				t.Error("image-alt did not fail")
+			}
+		case "heading-order":
+			if c.Failure != nil || !strings.Contains(c.SystemOut, "h3 follows h1") {
+				t.Errorf("heading-order: failure=%v out=%q", c.Failure, c.SystemOut)
+			}
+		default:
+			if c.Failure != nil {
+				t.Errorf("%s failed: %s", c.Name, *c.Failure)
+			}
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package a11y
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/plaenen/webx/webxtest"
+)
+
+// CheckSSE checks the HTML of every element patch in a Datastar SSE
+// stream, such as the body of a webxtest.Response.
+func CheckSSE(stream string, opts ...Options) (Report, error) {
+	events, err := webxtest.ParseEvents(stream)
+	if err != nil {
+		return Report{}, fmt.Errorf("a11y: %w", err)
+	}
+	var parts []string
+	for _, ev := range events {
+		if p, ok := ev.(webxtest.PatchElements); ok {
+			parts = append(parts, p.Elements)
+		}
+	}
+	return Check(strings.Join(parts, "\n"), opts...)
+}
+
+// Assert checks markup and fails t for every error. Warnings are logged.
+// It returns the report so tests can collect reports for WriteJUnit.
+func Assert(t testing.TB, markup string, opts ...Options) Report {
+	t.Helper()
+	r, err := Check(markup, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fail(t, r)
+	return r
+}
+
+// AssertComponent renders c and asserts the result like Assert.
+func AssertComponent(t testing.TB, c templ.Component, opts ...Options) Report {
+	t.Helper()
+	r, err := CheckComponent(context.Background(), c, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fail(t, r)
+	return r
+}
+
+// AssertSSE checks an SSE stream like CheckSSE and asserts the result
+// like Assert.
+func AssertSSE(t testing.TB, stream string, opts ...Options) Report {
+	t.Helper()
+	r, err := CheckSSE(stream, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fail(t, r)
+	return r
+}
+
+func fail(t testing.TB, r Report) {
+	t.Helper()
+	for _, i := range r.Issues {
+		if i.Severity == SeverityError {
+			t.Error(i)
+		} else {
+			t.Log(i)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package a11y
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Report is the result of one check.
+type Report struct {
+	Source string `json:"source,omitempty"`
+	// Rules names the rules that ran.
+	Rules  []string `json:"rules"`
+	Issues []Issue  `json:"issues"`
+}
+
+// Errors returns the issues with SeverityError.
+func (r Report) Errors() []Issue { return r.filter(SeverityError) }
+
+// Warnings returns the issues with SeverityWarning.
+func (r Report) Warnings() []Issue { return r.filter(SeverityWarning) }
+
+// Failed reports whether the check found errors.
+func (r Report) Failed() bool { return len(r.Errors()) > 0 }
+
+func (r Report) filter(s Severity) []Issue {
+	var out []Issue
+	for _, i := range r.Issues {
+		if i.Severity == s {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+func (r Report) String() string {
+	var b strings.Builder
+	if r.Source != "" {
+		fmt.Fprintf(&b, "%s: ", r.Source)
+	}
+	fmt.Fprintf(&b, "%d errors, %d warnings", len(r.Errors()), len(r.Warnings()))
+	for _, i := range r.Issues {
+		b.WriteString("\n" + i.String())
+	}
+	return b.String()
+}
+
+// WriteJSON writes reports as a JSON array.
+func WriteJSON(w io.Writer, reports ...Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if reports == nil {
+		reports = []Report{}
+	}
+	if err := enc.Encode(reports); err != nil {
+		return fmt.Errorf("a11y: writing JSON report: %w", err)
+	}
+	return nil
+}
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes reports as JUnit XML, which most CI systems display
+// natively. Each report is a test suite and each rule a test case that
+// fails on errors; warnings are attached as output.
+func WriteJUnit(w io.Writer, reports ...Report) error {
+	out := junitSuites{Name: "a11y"}
+	for i, r := range reports {
+		name := r.Source
+		if name == "" {
+			name = fmt.Sprintf("report %d", i+1)
+		}
+		suite := junitSuite{Name: name}
+		for _, rule := range ruleNames(r) {
+			c := junitCase{Name: rule, Classname: "a11y." + name}
+			var errs, warns []string
+			for _, issue := range r.Issues {
+				if issue.Rule != rule {
+					continue
+				}
+				if issue.Severity == SeverityError {
+					errs = append(errs, issue.String())
+				} else {
+					warns = append(warns, issue.String())
+				}
+			}
+			if len(errs) > 0 {
+				c.Failure = &junitFailure{
+					Message: fmt.Sprintf("%d accessibility errors", len(errs)),
+					Type:    rule,
+					Text:    strings.Join(errs, "\n"),
+				}
+				suite.Failures++
+			}
+			c.SystemOut = strings.Join(warns, "\n")
+			suite.Cases = append(suite.Cases, c)
+		}
+		suite.Tests = len(suite.Cases)
+		out.Tests += suite.Tests
+		out.Failures += suite.Failures
+		out.Suites = append(out.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("a11y: writing JUnit report: %w", err)
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		return fmt.Errorf("a11y: writing JUnit report: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// ruleNames lists the rules that ran, so passing rules show up as passing
+// test cases, followed by any other rule with issues.
+func ruleNames(r Report) []string {
+	var names []string
+	seen := map[string]bool{}
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, rule := range r.Rules {
+		add(rule)
+	}
+	for _, i := range r.Issues {
+		add(i.Rule)
+	}
+	return names
+}
@@ -0,0 +1,457 @@
+package a11y
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Reporter records a violation on element n.
+type Reporter func(n *html.Node, severity Severity, format string, args ...any)
+
+// Rule is one accessibility check.
+type Rule struct {
+	// Name identifies the rule in reports and Options.Disable.
+	Name string
+	// Check walks doc and reports violations.
+	Check func(doc *Document, report Reporter)
+}
+
+// Rules is the default rule set.
+var Rules = []Rule{
+	{Name: "duplicate-id", Check: checkDuplicateIDs},
+	{Name: "control-label", Check: checkControlLabels},
+	{Name: "button-name", Check: checkButtonNames},
+	{Name: "toggle-state", Check: checkToggleState},
+	{Name: "interactive-role", Check: checkInteractiveRoles},
+	{Name: "aria-reference", Check: checkARIAReferences},
+	{Name: "image-alt", Check: checkImageAlt},
+	{Name: "heading-order", Check: checkHeadingOrder},
+}
+
+// Document is parsed markup with the indexes rules need.
+type Document struct {
+	// Root is the document or, for fragments, a synthetic <body>.
+	Root *html.Node
+	// Elements lists every element in document order.
+	Elements []*html.Node
+
+	ids       map[string][]*html.Node
+	labelsFor map[string]bool
+}
+
+func newDocument(root *html.Node) *Document {
+	d := &Document{Root: root, ids: map[string][]*html.Node{}, labelsFor: map[string]bool{}}
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			d.Elements = append(d.Elements, n)
+			if id := attr(n, "id"); id != "" {
+				d.ids[id] = append(d.ids[id], n)
+			}
+			if n.Data == "label" && hasAttr(n, "for") {
+				d.labelsFor[attr(n, "for")] = true
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(root)
+	return d
+}
+
+// ByID returns the element with the given ID, or nil.
+func (d *Document) ByID(id string) *html.Node {
+	if ns := d.ids[id]; len(ns) > 0 {
+		return ns[0]
+	}
+	return nil
+}
+
+// AccessibleName approximates the name assistive technology announces for
+// n: aria-labelledby, aria-label, associated labels, then text content,
+// alt text and title.
+func (d *Document) AccessibleName(n *html.Node) string {
+	if ids := strings.Fields(attr(n, "aria-labelledby")); len(ids) > 0 {
+		var parts []string
+		for _, id := range ids {
+			if ref := d.ByID(id); ref != nil {
+				parts = append(parts, textContent(ref))
+			}
+		}
+		if name := strings.TrimSpace(strings.Join(parts, " ")); name != "" {
+			return name
+		}
+	}
+	if name := strings.TrimSpace(attr(n, "aria-label")); name != "" {
+		return name
+	}
+	if isFormControl(n) {
+		if id := attr(n, "id"); id != "" && d.labelsFor[id] {
+			return "label[for=" + id + "]"
+		}
+		for p := n.Parent; p != nil; p = p.Parent {
+			if p.Type == html.ElementNode && p.Data == "label" {
+				return strings.TrimSpace(textContent(p))
+			}
+		}
+		if n.Data == "input" {
+			switch strings.ToLower(attr(n, "type")) {
+			case "submit", "reset", "button":
+				if v := attr(n, "value"); v != "" {
+					return v
+				}
+				if t := strings.ToLower(attr(n, "type")); t != "button" {
+					return t // browsers supply "Submit" or "Reset"
+				}
+			case "image":
+				return attr(n, "alt")
+			}
+		}
+	} else if name := strings.TrimSpace(textContent(n)); name != "" {
+		return name
+	}
+	return strings.TrimSpace(attr(n, "title"))
+}
+
+func checkDuplicateIDs(doc *Document, report Reporter) {
+	for _, n := range doc.Elements {
+		id := attr(n, "id")
+		if id == "" {
+			continue
+		}
+		if same := doc.ids[id]; len(same) > 1 && same[0] != n {
+			report(n, SeverityError, "id %q is already used by %s", id, path(same[0]))
+		}
+	}
+}
+
+func checkControlLabels(doc *Document, report Reporter) {
+	for _, n := range doc.Elements {
+		if !isFormControl(n) || isHiddenFromAT(n) {
+			continue
+		}
+		switch strings.ToLower(attr(n, "type")) {
+		case "hidden", "submit", "reset", "button", "image":
+			continue // hidden, or named by button-name
+		}
+		if doc.AccessibleName(n) == "" && attr(n, "placeholder") == "" {
+			report(n, SeverityError, "%s has no label: add a <label for>, wrap it in a <label>, or set aria-label", describe(n))
+		}
+	}
+}
+
+func checkButtonNames(doc *Document, report Reporter) {
+	for _, n := range doc.Elements {
+		if !isButton(n) || isHiddenFromAT(n) {
+			continue
+		}
+		if doc.AccessibleName(n) == "" {
+			report(n, SeverityError, "%s has no accessible name: give it text, aria-label or title", describe(n))
+		}
+	}
+}
+
+// checkToggleState requires state on controls that show, hide or select
+// content, so screen readers can tell whether it is open or chosen:
+// popup triggers and buttons whose click handler flips a signal
+// ($open = !$open) need aria-expanded or aria-pressed, tabs need
+// aria-selected, and switches and checkboxes aria-checked. A state bound
+// with data-attr counts when the expression yields a string: Datastar
+// removes the attribute while the value is false, so a boolean binding
+// leaves a closed disclosure without aria-expanded.
+func checkToggleState(doc *Document, report Reporter) {
+	for _, n := range doc.Elements {
+		if isHiddenFromAT(n) || n.Data == "summary" || n.Data == "input" {
+			continue
+		}
+		for _, state := range []string{"aria-expanded", "aria-pressed", "aria-selected", "aria-checked"} {
+			if key := "data-attr:" + state; hasAttr(n, key) && !bindsString(attr(n, key)) {
+				report(n, SeverityError, "%s binds %s to a boolean, which removes it while false; bind 'true' or 'false' instead", describe(n), state)
+			}
+		}
+		switch attr(n, "role") {
+		case "tab":
+			if !hasState(n, "aria-selected") {
+				report(n, SeverityError, "%s has no aria-selected state", describe(n))
+			}
+			continue
+		case "switch", "checkbox", "menuitemcheckbox", "menuitemradio", "radio":
+			if !hasState(n, "aria-checked") {
+				report(n, SeverityError, "%s has no aria-checked state", describe(n))
+			}
+			continue
+		}
+		popup := attr(n, "aria-haspopup")
+		isPopup := popup != "" && popup != "false"
+		if !isPopup && !(isButton(n) && hasAttr(n, "aria-controls")) && !togglesSignal(n) {
+			continue
+		}
+		if !hasState(n, "aria-expanded") && !hasState(n, "aria-pressed") {
+			report(n, SeverityError, "%s toggles content but has no aria-expanded or aria-pressed state", describe(n))
+		}
+	}
+}
+
+// togglesSignal reports whether a click handler negates a signal, as in
+// "$nav.open = !$nav.open".
+func togglesSignal(n *html.Node) bool {
+	for _, a := range n.Attr {
+		if isClickAttr(a.Key) && strings.Contains(strings.ReplaceAll(a.Val, " ", ""), "=!$") {
+			return true
+		}
+	}
+	return false
+}
+
+// bindsString reports whether a data-attr expression yields a string, as
+// "$open ? 'true' : 'false'" or "String($open)" do.
+func bindsString(expr string) bool {
+	for _, s := range []string{"'true'", `"true"`, "`true`", "String(", ".toString("} {
+		if strings.Contains(expr, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasState reports whether n sets the ARIA state name statically or binds
+// it with data-attr.
+func hasState(n *html.Node, name string) bool {
+	return hasAttr(n, name) || hasAttr(n, "data-attr:"+name) || strings.Contains(attr(n, "data-attr"), name)
+}
+
+// checkInteractiveRoles finds elements that react to clicks but are not
+// keyboard reachable or not announced as controls. Containers with
+// interactive descendants are skipped: their handler catches clicks
+// bubbling up from those, as a dropdown menu closing after a choice does.
+func checkInteractiveRoles(doc *Document, report Reporter) {
+	for _, n := range doc.Elements {
+		if isNativelyInteractive(n) || isHiddenFromAT(n) || !hasClickHandler(n) || hasInteractiveDescendant(n) {
+			continue
+		}
+		role := attr(n, "role")
+		if role == "" || role == "presentation" || role == "none" {
+			report(n, SeverityError, "%s handles clicks but has no interactive role; use a <button> or add role and tabindex", describe(n))
+			continue
+		}
+		if !hasAttr(n, "tabindex") {
+			report(n, SeverityError, "%s has role %q but is not focusable; add tabindex=\"0\"", describe(n), role)
+		}
+	}
+}
+
+// checkARIAReferences reports ID references that point nowhere. In a
+// fragment the target may live elsewhere on the page, so it only warns.
+func checkARIAReferences(doc *Document, report Reporter) {
+	for _, n := range doc.Elements {
+		for _, key := range []string{"aria-labelledby", "aria-describedby", "aria-controls", "aria-owns"} {
+			for _, id := range strings.Fields(attr(n, key)) {
+				if doc.ByID(id) == nil {
+					report(n, SeverityWarning, "%s references missing id %q", key, id)
+				}
+			}
+		}
+		if n.Data == "label" && hasAttr(n, "for") && doc.ByID(attr(n, "for")) == nil {
+			report(n, SeverityWarning, "label for=%q references a missing id", attr(n, "for"))
+		}
+	}
+}
+
+func checkImageAlt(doc *Document, report Reporter) {
+	for _, n := range doc.Elements {
+		if n.Data != "img" || hasAttr(n, "alt") || isHiddenFromAT(n) {
+			continue
+		}
+		if role := attr(n, "role"); role == "presentation" || role == "none" {
+			continue
+		}
+		report(n, SeverityError, "img has no alt attribute; use alt=\"\" for decorative images")
+	}
+}
+
+// checkHeadingOrder warns when a heading skips levels, such as an h2
+// followed by an h4. The first heading sets the starting level, since a
+// component may be rendered below any heading.
+func checkHeadingOrder(doc *Document, report Reporter) {
+	prev := 0
+	for _, n := range doc.Elements {
+		level := headingLevel(n)
+		if level == 0 {
+			continue
+		}
+		if prev != 0 && level > prev+1 {
+			report(n, SeverityWarning, "h%d follows h%d, skipping a level", level, prev)
+		}
+		prev = level
+	}
+}
+
+func headingLevel(n *html.Node) int {
+	if len(n.Data) == 2 && n.Data[0] == 'h' && n.Data[1] >= '1' && n.Data[1] <= '6' {
+		return int(n.Data[1] - '0')
+	}
+	if attr(n, "role") == "heading" {
+		var level int
+		fmt.Sscanf(attr(n, "aria-level"), "%d", &level)
+		return level
+	}
+	return 0
+}
+
+func isFormControl(n *html.Node) bool {
+	switch n.Data {
+	case "input", "select", "textarea":
+		return true
+	}
+	return false
+}
+
+func isButton(n *html.Node) bool {
+	if n.Data == "button" || attr(n, "role") == "button" {
+		return true
+	}
+	if n.Data == "input" {
+		switch strings.ToLower(attr(n, "type")) {
+		case "button", "submit", "reset", "image":
+			return true
+		}
+	}
+	return false
+}
+
+func isNativelyInteractive(n *html.Node) bool {
+	switch n.Data {
+	case "button", "input", "select", "textarea", "summary", "option", "label", "details", "dialog", "form":
+		return true
+	case "a":
+		return hasAttr(n, "href")
+	}
+	return false
+}
+
+// hasClickHandler reports Datastar or inline click handlers.
+func hasClickHandler(n *html.Node) bool {
+	for _, a := range n.Attr {
+		if isClickAttr(a.Key) {
+			return true
+		}
+	}
+	return false
+}
+
+// isClickAttr matches onclick and Datastar's data-on:click, with or without
+// modifiers. Outside-click handlers are not activations of the element.
+func isClickAttr(key string) bool {
+	if key == "onclick" || key == "data-on:click" || key == "data-on-click" {
+		return true
+	}
+	mods, ok := strings.CutPrefix(key, "data-on:click__")
+	return ok && !strings.HasPrefix(mods, "outside")
+}
+
+// isHiddenFromAT reports whether n or an ancestor is removed from the
+// accessibility tree.
+func isHiddenFromAT(n *html.Node) bool {
+	for ; n != nil; n = n.Parent {
+		if n.Type != html.ElementNode {
+			continue
+		}
+		if attr(n, "aria-hidden") == "true" || hasAttr(n, "hidden") || hasAttr(n, "inert") {
+			return true
+		}
+		if n.Data == "template" {
+			return true
+		}
+	}
+	return false
+}
+
+func describe(n *html.Node) string {
+	if n.Data == "input" {
+		if t := attr(n, "type"); t != "" {
+			return fmt.Sprintf("<input type=%q>", t)
+		}
+	}
+	if role := attr(n, "role"); role != "" {
+		return fmt.Sprintf("<%s role=%q>", n.Data, role)
+	}
+	return "<" + n.Data + ">"
+}
+
+// textContent returns the text announced for n's subtree, skipping hidden
+// parts and using alt text and aria-labels of descendants.
+func textContent(n *html.Node) string {
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			b.WriteString(n.Data)
+			return
+		case html.ElementNode:
+			if attr(n, "aria-hidden") == "true" || hasAttr(n, "hidden") {
+				return
+			}
+			if label := attr(n, "aria-label"); label != "" {
+				b.WriteString(" " + label + " ")
+				return
+			}
+			switch n.Data {
+			case "img":
+				b.WriteString(" " + attr(n, "alt") + " ")
+				return
+			case "script", "style", "template":
+				return
+			}
+			if n.Data == "title" && n.Parent != nil && n.Parent.Data == "svg" {
+				b.WriteString(" " + textOf(n) + " ")
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+func textOf(n *html.Node) string {
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.TextNode {
+			b.WriteString(c.Data)
+		}
+	}
+	return b.String()
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func hasAttr(n *html.Node, key string) bool {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func hasInteractiveDescendant(n *html.Node) bool {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && (isNativelyInteractive(c) || hasInteractiveDescendant(c)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,331 @@
+// Package assets serves static files under content-hashed names so browsers
+// can cache them forever, with gzip and brotli variants prepared at startup.
+//
+//	static, err := assets.New(staticFS)
+//	r.Handle("/assets/*", static)
+//	r.Use(static.Middleware())
+//
+// With the middleware installed, layouts.Base rewrites
+// WebXContext.Stylesheets and Scripts such as "/assets/css/output.css" to
+// their fingerprinted URL ("/assets/css/output.3f9a1c0b7d2e.css").
+package assets
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"maps"
+	"mime"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"github.com/plaenen/webx"
+)
+
+// DefaultMinCompressSize is the smallest file that gets compressed variants
+// when Options.MinCompressSize is zero.
+const DefaultMinCompressSize = 1024
+
+// brotliLevel trades a little size for startup time: level 11 is about 25
+// times slower on a large stylesheet. Ship ".br" files for maximum
+// compression.
+const brotliLevel = 9
+
+// hashLen is the number of hex characters of the content hash kept in
+// fingerprinted names.
+const hashLen = 12
+
+// Options configures a Handler.
+type Options struct {
+	// Prefix is the URL path the handler is mounted at. Defaults to
+	// "/assets/".
+	Prefix string
+	// MinCompressSize skips compression for smaller files. Defaults to
+	// DefaultMinCompressSize.
+	MinCompressSize int
+	// DisableCompression serves every file uncompressed.
+	DisableCompression bool
+}
+
+// Handler serves the files of an fs.FS under fingerprinted names. Requests
+// for a fingerprinted name get a one-year immutable Cache-Control header;
+// requests for the plain name still work but must revalidate, so URLs that
+// bypass the manifest (markdown, hand-written HTML) keep working.
+type Handler struct {
+	prefix   string
+	manifest map[string]string // logical name -> fingerprinted name
+	files    map[string]*asset // fingerprinted and logical name -> asset
+}
+
+// Compile-time check that Handler can resolve asset URLs for WebXContext.
+var _ webx.AssetResolver = (*Handler)(nil)
+
+// asset is one file with its precomputed variants.
+type asset struct {
+	name        string
+	hash        string
+	contentType string
+	plain       []byte
+	gzip        []byte
+	brotli      []byte
+}
+
+// New reads every file in fsys, hashes its content and prepares compressed
+// variants. Files named "x.gz" or "x.br" next to "x" are used as its
+// precompressed variants instead of being compressed again.
+func New(fsys fs.FS, opts ...Options) (*Handler, error) {
+	var o Options
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if o.Prefix == "" {
+		o.Prefix = "/assets/"
+	}
+	if !strings.HasSuffix(o.Prefix, "/") {
+		o.Prefix += "/"
+	}
+	if o.MinCompressSize == 0 {
+		o.MinCompressSize = DefaultMinCompressSize
+	}
+
+	raw := map[string][]byte{}
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		raw[p] = data
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("assets: reading files: %w", err)
+	}
+
+	h := &Handler{
+		prefix:   o.Prefix,
+		manifest: map[string]string{},
+		files:    map[string]*asset{},
+	}
+	for name, data := range raw {
+		if isVariant(name, raw) {
+			continue
+		}
+		sum := sha256.Sum256(data)
+		a := &asset{
+			name:        name,
+			hash:        hex.EncodeToString(sum[:])[:hashLen],
+			contentType: contentType(name),
+			plain:       data,
+			gzip:        raw[name+".gz"],
+			brotli:      raw[name+".br"],
+		}
+		if !o.DisableCompression && len(data) >= o.MinCompressSize && compressible(a.contentType) {
+			if a.gzip == nil {
+				a.gzip, err = gzipBytes(data)
+			}
+			if err == nil && a.brotli == nil {
+				a.brotli, err = brotliBytes(data)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("assets: compressing %s: %w", name, err)
+			}
+		}
+		if o.DisableCompression {
+			a.gzip, a.brotli = nil, nil
+		}
+		a.gzip = smaller(a.gzip, data)
+		a.brotli = smaller(a.brotli, data)
+
+		hashed := fingerprint(name, a.hash)
+		h.manifest[name] = hashed
+		h.files[hashed] = a
+		h.files[name] = a
+	}
+	return h, nil
+}
+
+// URL returns the fingerprinted URL for an asset. name may be the path inside
+// the file system ("css/output.css") or the unhashed URL under the prefix
+// ("/assets/css/output.css"). Unknown names are returned unchanged, or joined
+// to the prefix when relative.
+func (h *Handler) URL(name string) string {
+	logical := strings.TrimPrefix(strings.TrimPrefix(name, h.prefix), "/")
+	if hashed, ok := h.manifest[logical]; ok {
+		return h.prefix + hashed
+	}
+	if strings.HasPrefix(name, "/") || strings.Contains(name, "://") {
+		return name
+	}
+	return h.prefix + name
+}
+
+// Manifest returns a copy of the logical-to-fingerprinted name mapping,
+// for example to write it out for a CDN upload step.
+func (h *Handler) Manifest() map[string]string {
+	return maps.Clone(h.manifest)
+}
+
+// Names returns the logical names of all assets, sorted.
+func (h *Handler) Names() []string {
+	return slices.Sorted(maps.Keys(h.manifest))
+}
+
+// Middleware sets the handler as WebXContext.Assets so layouts resolve
+// stylesheet and script URLs through the manifest.
+func (h *Handler) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			wctx := webx.FromContext(r.Context())
+			wctx.Assets = h
+			next.ServeHTTP(w, r.WithContext(wctx.WithContext(r.Context())))
+		})
+	}
+}
+
+// ServeHTTP serves an asset. Mount it at the configured prefix; the prefix is
+// stripped from the request path.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, h.prefix), "/")
+	a, ok := h.files[name]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	hdr := w.Header()
+	if name == a.name {
+		hdr.Set("Cache-Control", "no-cache")
+	} else {
+		hdr.Set("Cache-Control", "public, max-age=31536000, immutable")
+	}
+	hdr.Set("Content-Type", a.contentType)
+	hdr.Set("X-Content-Type-Options", "nosniff")
+
+	body, encoding := a.plain, ""
+	if a.gzip != nil || a.brotli != nil {
+		hdr.Add("Vary", "Accept-Encoding")
+		accept := r.Header.Get("Accept-Encoding")
+		switch {
+		case a.brotli != nil && acceptsEncoding(accept, "br"):
+			body, encoding = a.brotli, "br"
+		case a.gzip != nil && acceptsEncoding(accept, "gzip"):
+			body, encoding = a.gzip, "gzip"
+		}
+	}
+	etag := `"` + a.hash
+	if encoding != "" {
+		hdr.Set("Content-Encoding", encoding)
+		etag += "-" + encoding
+	}
+	hdr.Set("ETag", etag+`"`)
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(body))
+}
+
+// fingerprint inserts hash before the extension: "css/app.css" becomes
+// "css/app.<hash>.css".
+func fingerprint(name, hash string) string {
+	ext := path.Ext(name)
+	if ext == "" || ext == path.Base(name) {
+		return name + "." + hash
+	}
+	return strings.TrimSuffix(name, ext) + "." + hash + ext
+}
+
+// isVariant reports whether name is a precompressed copy of another file.
+func isVariant(name string, files map[string][]byte) bool {
+	for _, ext := range []string{".gz", ".br"} {
+		if base, ok := strings.CutSuffix(name, ext); ok {
+			if _, exists := files[base]; exists {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func contentType(name string) string {
+	switch path.Ext(name) {
+	case ".js", ".mjs":
+		return "text/javascript; charset=utf-8"
+	}
+	if ct := mime.TypeByExtension(path.Ext(name)); ct != "" {
+		return ct
+	}
+	return "application/octet-stream"
+}
+
+func compressible(contentType string) bool {
+	ct, _, _ := strings.Cut(contentType, ";")
+	switch {
+	case strings.HasPrefix(ct, "text/"):
+		return true
+	case strings.HasSuffix(ct, "+xml"), strings.HasSuffix(ct, "/json"), strings.HasSuffix(ct, "/xml"):
+		return true
+	case ct == "application/javascript", ct == "application/wasm", ct == "font/ttf", ct == "font/otf":
+		return true
+	}
+	return false
+}
+
+// acceptsEncoding reports whether an Accept-Encoding header allows enc.
+func acceptsEncoding(header, enc string) bool {
+	for part := range strings.SplitSeq(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(name), enc) {
+			continue
+		}
+		q := strings.ReplaceAll(strings.TrimSpace(params), " ", "")
+		return q != "q=0" && q != "q=0.0" && q != "q=0.00" && q != "q=0.000"
+	}
+	return false
+}
+
+// smaller drops a compressed variant that does not save any bytes.
+func smaller(variant, plain []byte) []byte {
+	if variant == nil || len(variant) >= len(plain) {
+		return nil
+	}
+	return variant
+}
+
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func brotliBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	bw := brotli.NewWriterLevel(&buf, brotliLevel)
+	if _, err := bw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := bw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
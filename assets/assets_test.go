@@ -0,0 +1,169 @@
+package assets_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/andybalholm/brotli"
+	"github.com/plaenen/webx"
+	"github.com/plaenen/webx/assets"
+)
+
+var css = strings.Repeat("body { color: red; }\n", 200)
+
+func newHandler(t *testing.T, opts ...assets.Options) *assets.Handler {
+	t.Helper()
+	h, err := assets.New(fstest.MapFS{
+		"css/app.css":    {Data: []byte(css)},
+		"js/app.js":      {Data: []byte("console.log(1)")},
+		"img/logo.png":   {Data: bytes.Repeat([]byte{0x89}, 4096)},
+		"js/big.js":      {Data: []byte(strings.Repeat("x();", 1000))},
+		"js/big.js.br":   {Data: []byte("precompressed")},
+		"LICENCE":        {Data: []byte("MIT")},
+		"orphan/file.gz": {Data: []byte("gz")},
+	}, opts...)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return h
+}
+
+func get(h http.Handler, path, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestURL(t *testing.T) {
+	h := newHandler(t)
+	hashed := h.URL("css/app.css")
+	if !strings.HasPrefix(hashed, "/assets/css/app.") || !strings.HasSuffix(hashed, ".css") || hashed == "/assets/css/app.css" {
+		t.Fatalf("URL(css/app.css) = %q", hashed)
+	}
+	if got := h.URL("/assets/css/app.css"); got != hashed {
+		t.Errorf("URL with prefix = %q, want %q", got, hashed)
+	}
+	if got := h.URL("/other/x.css"); got != "/other/x.css" {
+		t.Errorf("unknown absolute URL = %q", got)
+	}
+	if got := h.URL("https://cdn.test/x.js"); got != "https://cdn.test/x.js" {
+		t.Errorf("external URL = %q", got)
+	}
+	if got := h.URL("LICENCE"); !strings.HasPrefix(got, "/assets/LICENCE.") {
+		t.Errorf("extensionless URL = %q", got)
+	}
+	if _, ok := h.Manifest()["js/big.js.br"]; ok {
+		t.Error("precompressed variant listed as its own asset")
+	}
+	if _, ok := h.Manifest()["orphan/file.gz"]; !ok {
+		t.Error(".gz file without a base file should be an asset")
+	}
+
+	other, err := assets.New(fstest.MapFS{"css/app.css": {Data: []byte(css + " ")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.URL("css/app.css") == hashed {
+		t.Error("different content produced the same URL")
+	}
+}
+
+func TestServe_Caching(t *testing.T) {
+	h := newHandler(t)
+
+	rec := get(h, h.URL("js/app.js"), "")
+	if rec.Code != http.StatusOK || rec.Body.String() != "console.log(1)" {
+		t.Fatalf("hashed: %d %q", rec.Code, rec.Body.String())
+	}
+	if cc := rec.Header().Get("Cache-Control"); !strings.Contains(cc, "immutable") {
+		t.Errorf("hashed Cache-Control = %q", cc)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/javascript") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	rec = get(h, "/assets/js/app.js", "")
+	if rec.Code != http.StatusOK || rec.Header().Get("Cache-Control") != "no-cache" {
+		t.Errorf("plain name: %d Cache-Control %q", rec.Code, rec.Header().Get("Cache-Control"))
+	}
+
+	req := httptest.NewRequest(http.MethodGet, h.URL("js/app.js"), nil)
+	req.Header.Set("If-None-Match", rec.Header().Get("ETag"))
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("If-None-Match: status = %d, want 304", rec.Code)
+	}
+
+	if rec := get(h, "/assets/css/app.000000000000.css", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("stale hash: status = %d, want 404", rec.Code)
+	}
+}
+
+func TestServe_Compression(t *testing.T) {
+	h := newHandler(t)
+	url := h.URL("css/app.css")
+
+	rec := get(h, url, "gzip, deflate, br")
+	if rec.Header().Get("Content-Encoding") != "br" {
+		t.Fatalf("Content-Encoding = %q, want br", rec.Header().Get("Content-Encoding"))
+	}
+	body, err := io.ReadAll(brotli.NewReader(rec.Body))
+	if err != nil || string(body) != css {
+		t.Errorf("brotli body does not round-trip: %v", err)
+	}
+
+	rec = get(h, url, "gzip")
+	if rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", rec.Header().Get("Content-Encoding"))
+	}
+	zr, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body, _ := io.ReadAll(zr); string(body) != css {
+		t.Error("gzip body does not round-trip")
+	}
+	if rec.Header().Get("Vary") != "Accept-Encoding" {
+		t.Errorf("Vary = %q", rec.Header().Get("Vary"))
+	}
+
+	if rec := get(h, url, "br;q=0, gzip;q=0"); rec.Header().Get("Content-Encoding") != "" || rec.Body.String() != css {
+		t.Error("q=0 encodings must not be used")
+	}
+	if rec := get(h, h.URL("js/app.js"), "br"); rec.Header().Get("Content-Encoding") != "" {
+		t.Error("files below MinCompressSize must not be compressed")
+	}
+	if rec := get(h, h.URL("img/logo.png"), "br"); rec.Header().Get("Content-Encoding") != "" {
+		t.Error("images must not be compressed")
+	}
+	if rec := get(h, h.URL("js/big.js"), "br"); rec.Body.String() != "precompressed" {
+		t.Errorf("precompressed sibling not used: %q", rec.Body.String())
+	}
+
+	plain := newHandler(t, assets.Options{DisableCompression: true})
+	if rec := get(plain, plain.URL("css/app.css"), "br"); rec.Header().Get("Content-Encoding") != "" {
+		t.Error("DisableCompression ignored")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	h := newHandler(t, assets.Options{Prefix: "/static"})
+	var got string
+	h.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = webx.FromContext(r.Context()).AssetURL("/static/css/app.css")
+	})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if got != h.URL("css/app.css") || !strings.HasPrefix(got, "/static/css/app.") {
+		t.Errorf("AssetURL = %q", got)
+	}
+}
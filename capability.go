@@ -0,0 +1,84 @@
+package webx
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/plaenen/webx/capability"
+)
+
+// CapabilityResolver returns the capabilities of the user making a
+// request, typically by loading the session's user and expanding their
+// roles with a capability.Policy. A nil Set means no capabilities.
+type CapabilityResolver interface {
+	Capabilities(r *http.Request) (capability.Set, error)
+}
+
+// CapabilityResolverFunc adapts a function to CapabilityResolver.
+type CapabilityResolverFunc func(r *http.Request) (capability.Set, error)
+
+// Capabilities calls f(r).
+func (f CapabilityResolverFunc) Capabilities(r *http.Request) (capability.Set, error) {
+	return f(r)
+}
+
+// CapabilityMiddleware resolves the current user's capabilities once per
+// request and stores them in WebXContext.Capabilities and in the request
+// context for capability.FromContext, where RequireCapability and
+// capability.Guard read them. Install it after SessionMiddleware when the
+// resolver needs the session.
+func CapabilityMiddleware(resolver CapabilityResolver) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			caps, err := resolver.Capabilities(r)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("capability resolver error: %v", err), http.StatusInternalServerError)
+				return
+			}
+			if caps == nil {
+				// Non-nil, so layouts.Dashboard hides gated nav items rather
+				// showing them all as it does without the middleware.
+				caps = capability.Set{}
+			}
+			wctx := FromContext(r.Context())
+			wctx.Capabilities = caps
+			ctx := capability.NewContext(wctx.WithContext(r.Context()), caps)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequireCapability responds 403 Forbidden unless the request's
+// capabilities, as stored by CapabilityMiddleware, satisfy required. With
+// chi, protect a single route or a group:
+//
+//	r.With(webx.RequireCapability("invoices:write")).Post("/invoices", h.Create)
+//
+// Requests that did not pass through CapabilityMiddleware are refused.
+func RequireCapability(required string) func(http.Handler) http.Handler {
+	return requireCapabilities(func(s capability.Set) bool { return s.Can(required) })
+}
+
+// RequireAnyCapability is RequireCapability for requests that need at
+// least one of required.
+func RequireAnyCapability(required ...string) func(http.Handler) http.Handler {
+	return requireCapabilities(func(s capability.Set) bool { return s.CanAny(required...) })
+}
+
+// RequireAllCapabilities is RequireCapability for requests that need every
+// one of required.
+func RequireAllCapabilities(required ...string) func(http.Handler) http.Handler {
+	return requireCapabilities(func(s capability.Set) bool { return s.CanAll(required...) })
+}
+
+func requireCapabilities(allowed func(capability.Set) bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !allowed(capability.FromContext(r.Context())) {
+				http.Error(w, "forbidden", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
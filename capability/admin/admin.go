@@ -0,0 +1,105 @@
+// Package admin provides components and handlers for managing roles and
+// the roles assigned to users: a role table, a role editor with
+// capability autocomplete and a live preview, and a user table showing
+// each user's effective capability.Set.
+//
+// Roles and assignments are kept in a Repository; Memory is an in-memory
+// implementation for tests and demos.
+//
+//	repo := admin.NewMemory(roles...)
+//	r.Use(webx.CapabilityMiddleware(admin.Resolver(repo)))
+//	r.Route(basePath, func(r chi.Router) {
+//	    admin.RegisterRoutes(r, repo)
+//	})
+//
+//	props, err := admin.LoadProps(ctx, repo)
+//	@admin.Admin(props)
+package admin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/plaenen/webx"
+	"github.com/plaenen/webx/capability"
+)
+
+// ManageCapability is required by the handlers mounted by RegisterRoutes.
+const ManageCapability = "roles:manage"
+
+var (
+	// ErrNotFound is returned for a role that does not exist.
+	ErrNotFound = errors.New("admin: role not found")
+	// ErrRoleInUse is returned when deleting a role another role includes.
+	ErrRoleInUse = errors.New("admin: role is included by another role")
+)
+
+// User is a user and the names of the roles assigned to them.
+type User struct {
+	ID    string
+	Roles []string
+}
+
+// Repository stores roles and role assignments.
+type Repository interface {
+	// Roles returns every role, in display order.
+	Roles(ctx context.Context) ([]capability.Role, error)
+	// SaveRole creates the role, or replaces the role with the same name.
+	SaveRole(ctx context.Context, role capability.Role) error
+	// DeleteRole removes a role and unassigns it from every user. It
+	// returns ErrNotFound for unknown roles and ErrRoleInUse when another
+	// role includes it.
+	DeleteRole(ctx context.Context, name string) error
+
+	// Users returns every user with at least one role, sorted by ID.
+	Users(ctx context.Context) ([]User, error)
+	// UserRoles returns the roles assigned to a user.
+	UserRoles(ctx context.Context, userID string) ([]string, error)
+	// SetUserRoles replaces the roles assigned to a user. It returns an
+	// error wrapping capability.ErrUnknownRole for roles that do not exist.
+	SetUserRoles(ctx context.Context, userID string, roles []string) error
+}
+
+// Effective returns the capabilities a user has through their roles.
+func Effective(ctx context.Context, repo Repository, userID string) (capability.Set, error) {
+	roles, err := repo.Roles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names, err := repo.UserRoles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := capability.NewPolicy(roles...)
+	if err != nil {
+		return nil, err
+	}
+	return policy.Expand(names...)
+}
+
+// Resolver returns a webx.CapabilityResolver that expands the roles of the
+// user linked to the session (see webx.SetSessionUser). Anonymous sessions
+// have no capabilities.
+func Resolver(repo Repository) webx.CapabilityResolver {
+	return webx.CapabilityResolverFunc(func(r *http.Request) (capability.Set, error) {
+		userID, err := webx.SessionUser(r)
+		if err != nil || userID == "" {
+			return nil, err
+		}
+		return Effective(r.Context(), repo, userID)
+	})
+}
+
+// LoadProps reads the roles and users of repo into Props for Admin.
+func LoadProps(ctx context.Context, repo Repository) (Props, error) {
+	roles, err := repo.Roles(ctx)
+	if err != nil {
+		return Props{}, err
+	}
+	users, err := repo.Users(ctx)
+	if err != nil {
+		return Props{}, err
+	}
+	return Props{Roles: roles, Users: users}, nil
+}
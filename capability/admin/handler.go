@@ -0,0 +1,343 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/plaenen/webx"
+	"github.com/plaenen/webx/capability"
+	"github.com/plaenen/webx/ds"
+	"github.com/plaenen/webx/utils"
+)
+
+// Standard handler paths. Mount them under your app's base path with
+// RegisterRoutes, or one by one.
+const (
+	SaveRolePath   = "/api/admin/roles/save"
+	DeleteRolePath = "/api/admin/roles/delete"
+	PreviewPath    = "/api/admin/roles/preview"
+	AssignPath     = "/api/admin/roles/assign"
+)
+
+const (
+	editorSuffix = "-editor"
+	modalSuffix  = "-modal"
+)
+
+// adminSignals are the signals of the Admin component, used by the user
+// table and the delete buttons.
+type adminSignals struct {
+	User  string `json:"user"`
+	Role  string `json:"role"`
+	Grant bool   `json:"grant"`
+	Error string `json:"error"`
+}
+
+// editorSignals are the signals of the role editor form.
+type editorSignals struct {
+	Editing      bool   `json:"editing"` // Name is an existing role and cannot change
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	Includes     string `json:"includes"`     // role names separated by commas
+	Capabilities string `json:"capabilities"` // one per line
+	Add          string `json:"add"`          // the autocomplete input
+	Submitting   bool   `json:"submitting"`
+	Error        string `json:"error"`
+}
+
+func (s editorSignals) role() capability.Role {
+	role := capability.Role{
+		Name:        strings.TrimSpace(s.Name),
+		Description: strings.TrimSpace(s.Description),
+	}
+	for name := range strings.SplitSeq(s.Includes, ",") {
+		if name = strings.TrimSpace(name); name != "" && !slices.Contains(role.Includes, name) {
+			role.Includes = append(role.Includes, name)
+		}
+	}
+	for c := range strings.Lines(s.Capabilities) {
+		if c = strings.TrimSpace(c); c != "" && !slices.Contains(role.Capabilities, c) {
+			role.Capabilities = append(role.Capabilities, c)
+		}
+	}
+	return role
+}
+
+// HandlerOption configures the handlers that re-render the Admin
+// component.
+type HandlerOption func(*handlerConfig)
+
+type handlerConfig struct {
+	suggestions []string
+}
+
+// WithSuggestions sets the capabilities offered while typing one, as
+// Props.Suggestions does for the first render. Pass the same list to both.
+func WithSuggestions(caps ...string) HandlerOption {
+	return func(c *handlerConfig) { c.suggestions = caps }
+}
+
+func resolveHandlerConfig(opts []HandlerOption) *handlerConfig {
+	cfg := &handlerConfig{}
+	for _, o := range opts {
+		o(cfg)
+	}
+	return cfg
+}
+
+// RegisterRoutes mounts the admin handlers, restricted to users with
+// ManageCapability. Install webx.CapabilityMiddleware first.
+//
+//	r.Route(basePath, func(r chi.Router) {
+//	    admin.RegisterRoutes(r, repo, admin.WithSuggestions(caps...))
+//	})
+func RegisterRoutes(r chi.Router, repo Repository, opts ...HandlerOption) {
+	r.Group(func(r chi.Router) {
+		r.Use(webx.RequireCapability(ManageCapability))
+		r.Post(SaveRolePath, SaveRoleHandler(repo, opts...))
+		r.Post(DeleteRolePath, DeleteRoleHandler(repo, opts...))
+		r.Post(PreviewPath, PreviewHandler(repo))
+		r.Post(AssignPath, AssignHandler(repo, opts...))
+	})
+}
+
+// SaveRoleHandler returns an http.HandlerFunc that saves the role in the
+// editor of the Admin component and re-renders it. Invalid roles, such as
+// ones including an unknown role or themselves, are reported in the form.
+func SaveRoleHandler(repo Repository, opts ...HandlerOption) http.HandlerFunc {
+	cfg := resolveHandlerConfig(opts)
+	return webx.SignalHandler(func(ctx context.Context, editorID string, in editorSignals, sse *webx.SignalSSE) error {
+		role := in.role()
+		roles, err := repo.Roles(ctx)
+		if err != nil {
+			return err
+		}
+		if !in.Editing && slices.ContainsFunc(roles, func(r capability.Role) bool { return r.Name == role.Name }) {
+			return showError(sse, fmt.Sprintf("A role named %q already exists.", role.Name))
+		}
+		if _, err := draftPolicy(roles, role); err != nil {
+			return showError(sse, err.Error())
+		}
+		if err := repo.SaveRole(ctx, role); err != nil {
+			return err
+		}
+		if err := sse.PatchSignals(map[string]any{"submitting": false, "error": ""}); err != nil {
+			return err
+		}
+		id := strings.TrimSuffix(editorID, editorSuffix)
+		if err := patchSignals(sse, id+modalSuffix, map[string]any{"open": false}); err != nil {
+			return err
+		}
+		return render(ctx, sse, repo, id, cfg)
+	})
+}
+
+// PreviewHandler returns an http.HandlerFunc that patches the effective
+// capabilities of the role being edited, or why it is invalid.
+func PreviewHandler(repo Repository) http.HandlerFunc {
+	return webx.SignalHandler(func(ctx context.Context, editorID string, in editorSignals, sse *webx.SignalSSE) error {
+		role := in.role()
+		roles, err := repo.Roles(ctx)
+		if err != nil {
+			return err
+		}
+		if role.Name == "" {
+			role.Name = "(unnamed)"
+		}
+		var s capability.Set
+		policy, err := draftPolicy(roles, role)
+		if err == nil {
+			s, err = policy.Expand(role.Name)
+		}
+		problem := ""
+		if err != nil {
+			problem = err.Error()
+		}
+		return sse.PatchElementTempl(previewPanel(editorID, s, problem))
+	})
+}
+
+// DeleteRoleHandler returns an http.HandlerFunc that deletes the role
+// named by the "role" signal of the Admin component and re-renders it.
+func DeleteRoleHandler(repo Repository, opts ...HandlerOption) http.HandlerFunc {
+	cfg := resolveHandlerConfig(opts)
+	return webx.SignalHandler(func(ctx context.Context, id string, in adminSignals, sse *webx.SignalSSE) error {
+		err := repo.DeleteRole(ctx, in.Role)
+		switch {
+		case errors.Is(err, ErrRoleInUse):
+			return showError(sse, fmt.Sprintf("%s is included by another role; remove it there first.", in.Role))
+		case errors.Is(err, ErrNotFound):
+			return showError(sse, fmt.Sprintf("There is no role named %q.", in.Role))
+		case err != nil:
+			return err
+		}
+		if err := sse.PatchSignals(map[string]any{"role": "", "error": ""}); err != nil {
+			return err
+		}
+		return render(ctx, sse, repo, id, cfg)
+	})
+}
+
+// AssignHandler returns an http.HandlerFunc that grants or revokes the
+// role in the "role" signal of the Admin component for the user in the
+// "user" signal, then re-renders it.
+func AssignHandler(repo Repository, opts ...HandlerOption) http.HandlerFunc {
+	cfg := resolveHandlerConfig(opts)
+	return webx.SignalHandler(func(ctx context.Context, id string, in adminSignals, sse *webx.SignalSSE) error {
+		user := strings.TrimSpace(in.User)
+		if user == "" || in.Role == "" {
+			return showError(sse, "Enter a user ID and choose a role.")
+		}
+		roles, err := repo.UserRoles(ctx, user)
+		if err != nil {
+			return err
+		}
+		has := slices.Contains(roles, in.Role)
+		switch {
+		case in.Grant && !has:
+			roles = append(roles, in.Role)
+		case !in.Grant && has:
+			roles = slices.DeleteFunc(roles, func(r string) bool { return r == in.Role })
+		}
+		err = repo.SetUserRoles(ctx, user, roles)
+		if errors.Is(err, capability.ErrUnknownRole) {
+			return showError(sse, fmt.Sprintf("There is no role named %q.", in.Role))
+		}
+		if err != nil {
+			return err
+		}
+		if err := sse.PatchSignals(map[string]any{"user": "", "role": "", "error": ""}); err != nil {
+			return err
+		}
+		return render(ctx, sse, repo, id, cfg)
+	})
+}
+
+// draftPolicy returns the policy of roles with role added or replaced.
+func draftPolicy(roles []capability.Role, role capability.Role) (*capability.Policy, error) {
+	next := slices.Clone(roles)
+	if i := slices.IndexFunc(next, func(r capability.Role) bool { return r.Name == role.Name }); i >= 0 {
+		next[i] = role
+	} else {
+		next = append(next, role)
+	}
+	return capability.NewPolicy(next...)
+}
+
+// render re-renders the Admin component with ID id from repo.
+func render(ctx context.Context, sse *webx.SignalSSE, repo Repository, id string, cfg *handlerConfig) error {
+	props, err := LoadProps(ctx, repo)
+	if err != nil {
+		return err
+	}
+	props.ID = id
+	props.Suggestions = cfg.suggestions
+	return sse.PatchElementTempl(Admin(props))
+}
+
+// showError reports a problem the user can fix in the component's "error"
+// signal, which both the Admin alert and the editor form display.
+func showError(sse *webx.SignalSSE, message string) error {
+	return sse.PatchSignals(map[string]any{"submitting": false, "error": message})
+}
+
+// patchSignals patches the signals of another component than sse.ID.
+func patchSignals(sse *webx.SignalSSE, componentID string, signals map[string]any) error {
+	return sse.Generator().MarshalAndPatchSignals(map[string]any{webx.SignalID(componentID): signals})
+}
+
+func resolveProps(p Props) Props {
+	if p.ID == "" {
+		p.ID = "roles-admin"
+	}
+	p.Suggestions = suggestions(p.Suggestions, p.Roles)
+	return p
+}
+
+// suggestions returns known, ManageCapability, the capabilities roles
+// grant, and the wildcards covering them, sorted and without duplicates.
+func suggestions(known []string, roles []capability.Role) []string {
+	seen := map[string]bool{"*": true}
+	add := func(c string) {
+		c = strings.TrimPrefix(c, "!")
+		if capability.Validate(c) != nil || seen[c] {
+			return
+		}
+		seen[c] = true
+		segs := strings.Split(c, ":")
+		for i := 1; i < len(segs); i++ {
+			seen[strings.Join(segs[:i], ":")+":*"] = true
+		}
+	}
+	for _, c := range known {
+		add(c)
+	}
+	add(ManageCapability)
+	for _, r := range roles {
+		for _, c := range r.Capabilities {
+			add(c)
+		}
+	}
+	out := make([]string, 0, len(seen))
+	for c := range seen {
+		out = append(out, c)
+	}
+	slices.Sort(out)
+	return out
+}
+
+// editRole returns the expression that loads role into the editor and
+// opens it. A zero role with editing false starts a new one.
+func editRole(ctx context.Context, id string, role capability.Role, editing bool) string {
+	e := id + editorSuffix
+	return string(ds.Seq(
+		ds.Set(ds.Signal(e, "editing"), ds.Bool(editing)),
+		ds.Set(ds.Signal(e, "name"), ds.Str(role.Name)),
+		ds.Set(ds.Signal(e, "description"), ds.Str(role.Description)),
+		ds.Set(ds.Signal(e, "includes"), ds.Str(strings.Join(role.Includes, ", "))),
+		ds.Set(ds.Signal(e, "capabilities"), ds.Str(strings.Join(role.Capabilities, "\n"))),
+		ds.Set(ds.Signal(e, "add"), ds.Str("")),
+		ds.Set(ds.Signal(e, "error"), ds.Str("")),
+		ds.Set(ds.Signal(id+modalSuffix, "open"), ds.Bool(true)),
+		ds.Raw(ds.Post(webx.FromContext(ctx).APIPath(PreviewPath)+"?id="+e)),
+	))
+}
+
+// deleteRole returns the expression that deletes a role after confirming.
+func deleteRole(ctx context.Context, id, name string) string {
+	return fmt.Sprintf("confirm(%s) && (%s, %s)",
+		ds.Str("Delete the role "+name+"?"),
+		ds.Set(ds.Signal(id, "role"), ds.Str(name)),
+		ds.Post(webx.FromContext(ctx).APIPath(DeleteRolePath)+"?id="+id))
+}
+
+// assign returns the expression that grants or revokes role for user.
+func assign(ctx context.Context, id, user, role string, grant bool) string {
+	return string(ds.Seq(
+		ds.Set(ds.Signal(id, "user"), ds.Str(user)),
+		ds.Set(ds.Signal(id, "role"), ds.Str(role)),
+		ds.Set(ds.Signal(id, "grant"), ds.Bool(grant)),
+		ds.Raw(ds.Post(webx.FromContext(ctx).APIPath(AssignPath)+"?id="+id)),
+	))
+}
+
+// assignSelected returns the expression that grants the role chosen in the
+// user table's footer to the user typed there.
+func assignSelected(ctx context.Context, id string) string {
+	return string(ds.Seq(
+		ds.Set(ds.Signal(id, "grant"), ds.Bool(true)),
+		ds.Raw(ds.Post(webx.FromContext(ctx).APIPath(AssignPath)+"?id="+id)),
+	))
+}
+
+// addCapability returns the expression that appends the autocomplete
+// input to the editor's capability list, as a single expression.
+func addCapability(es *utils.SignalManager) string {
+	add, caps := es.Signal("add"), es.Signal("capabilities")
+	return fmt.Sprintf(`%[1]s.trim() && (%[2]s = (%[2]s.trim() ? %[2]s.trim() + '\n' : '') + %[1]s.trim(), %[1]s = '')`, add, caps)
+}
@@ -0,0 +1,228 @@
+package admin_test
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/plaenen/webx"
+	"github.com/plaenen/webx/capability"
+	"github.com/plaenen/webx/capability/admin"
+	"github.com/plaenen/webx/webxtest"
+)
+
+func post(t *testing.T, h http.Handler, id string, signals map[string]any) *webxtest.Response {
+	t.Helper()
+	res := webxtest.Serve(t, h, webxtest.Request{Method: http.MethodPost, ID: id, Signals: signals})
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", res.Code, res.Body)
+	}
+	return res
+}
+
+// rendered returns the HTML of the re-rendered Admin component, or "".
+func rendered(res *webxtest.Response) string {
+	for _, e := range res.Elements() {
+		if strings.Contains(e.Elements, `id="roles-admin"`) {
+			return e.Elements
+		}
+	}
+	return ""
+}
+
+func TestSaveRoleHandler(t *testing.T) {
+	ctx := context.Background()
+	m := seed()
+	h := admin.SaveRoleHandler(m)
+
+	res := post(t, h, "roles-admin-editor", map[string]any{
+		"name":         " auditor ",
+		"includes":     "viewer, viewer",
+		"capabilities": "reports:read\n\n!reports:export\nreports:read\n",
+	})
+	if got := res.ComponentSignals("roles-admin-modal")["open"]; got != false {
+		t.Errorf("modal open = %v, want false", got)
+	}
+	if html := rendered(res); !strings.Contains(html, "auditor") {
+		t.Errorf("Admin not re-rendered with the new role: %s", html)
+	}
+	roles, _ := m.Roles(ctx)
+	want := capability.Role{Name: "auditor", Includes: []string{"viewer"}, Capabilities: capability.Set{"reports:read", "!reports:export"}}
+	if i := len(roles) - 1; roles[i].Name != want.Name || !slices.Equal(roles[i].Includes, want.Includes) || !slices.Equal(roles[i].Capabilities, want.Capabilities) {
+		t.Errorf("saved %+v, want %+v", roles[i], want)
+	}
+
+	for name, signals := range map[string]map[string]any{
+		"duplicate":      {"name": "viewer"},
+		"unknown":        {"name": "x", "includes": "ghost"},
+		"bad capability": {"name": "x", "capabilities": "a::b"},
+		"cycle":          {"name": "viewer", "editing": true, "includes": "accountant"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			res := post(t, h, "roles-admin-editor", signals)
+			if got, _ := res.ComponentSignals("roles-admin-editor")["error"].(string); got == "" {
+				t.Error("no error reported")
+			}
+			if rendered(res) != "" {
+				t.Error("Admin re-rendered after a failed save")
+			}
+		})
+	}
+	if after, _ := m.Roles(ctx); len(after) != len(roles) {
+		t.Errorf("failed saves changed the roles: %+v", after)
+	}
+}
+
+func TestPreviewHandler(t *testing.T) {
+	h := admin.PreviewHandler(seed())
+
+	res := post(t, h, "roles-admin-editor", map[string]any{"includes": "accountant", "capabilities": "reports:read"})
+	els := res.Elements()
+	if len(els) != 1 || !strings.Contains(els[0].Elements, `id="roles-admin-editor-preview"`) {
+		t.Fatalf("elements = %+v", els)
+	}
+	for _, c := range []string{"invoices:read", "!invoices:delete", "reports:read"} {
+		if !strings.Contains(els[0].Elements, c) {
+			t.Errorf("preview lacks %s: %s", c, els[0].Elements)
+		}
+	}
+
+	res = post(t, h, "roles-admin-editor", map[string]any{"name": "x", "includes": "ghost"})
+	if els := res.Elements(); len(els) != 1 || !strings.Contains(els[0].Elements, "unknown role") {
+		t.Errorf("preview of an invalid role = %+v", els)
+	}
+}
+
+func TestAssignHandler(t *testing.T) {
+	ctx := context.Background()
+	m := seed()
+	h := admin.AssignHandler(m)
+
+	res := post(t, h, "roles-admin", map[string]any{"user": "ann", "role": "viewer", "grant": true})
+	if html := rendered(res); !strings.Contains(html, "ann") {
+		t.Errorf("Admin not re-rendered with the user: %s", html)
+	}
+	post(t, h, "roles-admin", map[string]any{"user": "ann", "role": "accountant", "grant": true})
+	post(t, h, "roles-admin", map[string]any{"user": "ann", "role": "viewer", "grant": false})
+	if roles, _ := m.UserRoles(ctx, "ann"); !slices.Equal(roles, []string{"accountant"}) {
+		t.Errorf("ann's roles = %q", roles)
+	}
+
+	for name, signals := range map[string]map[string]any{
+		"no user":      {"role": "viewer", "grant": true},
+		"unknown role": {"user": "ann", "role": "ghost", "grant": true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			res := post(t, h, "roles-admin", signals)
+			if got, _ := res.ComponentSignals("roles-admin")["error"].(string); got == "" {
+				t.Error("no error reported")
+			}
+		})
+	}
+}
+
+func TestDeleteRoleHandler(t *testing.T) {
+	ctx := context.Background()
+	m := seed()
+	h := admin.DeleteRoleHandler(m)
+
+	res := post(t, h, "roles-admin", map[string]any{"role": "viewer"})
+	if got, _ := res.ComponentSignals("roles-admin")["error"].(string); !strings.Contains(got, "included by another role") {
+		t.Errorf("error = %q", got)
+	}
+	res = post(t, h, "roles-admin", map[string]any{"role": "accountant"})
+	if html := rendered(res); html == "" || strings.Contains(html, "accountant") {
+		t.Errorf("Admin not re-rendered without the role: %s", html)
+	}
+	if roles, _ := m.Roles(ctx); len(roles) != 1 {
+		t.Errorf("roles = %+v", roles)
+	}
+}
+
+func TestRegisterRoutes_RequiresManageCapability(t *testing.T) {
+	m := seed()
+	if err := m.SaveRole(context.Background(), capability.Role{Name: "admin", Capabilities: capability.Set{admin.ManageCapability}}); err != nil {
+		t.Fatal(err)
+	}
+	r := chi.NewRouter()
+	r.Use(webx.CapabilityMiddleware(webx.CapabilityResolverFunc(func(r *http.Request) (capability.Set, error) {
+		return admin.Effective(r.Context(), m, r.Header.Get("X-User"))
+	})))
+	admin.RegisterRoutes(r, m)
+	if err := m.SetUserRoles(context.Background(), "root", []string{"admin"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for user, want := range map[string]int{"root": http.StatusOK, "ann": http.StatusForbidden} {
+		res := webxtest.Serve(t, r, webxtest.Request{
+			Method:  http.MethodPost,
+			Target:  admin.PreviewPath,
+			ID:      "roles-admin-editor",
+			Signals: map[string]any{"name": "x"},
+			Header:  http.Header{"X-User": {user}},
+		})
+		if res.Code != want {
+			t.Errorf("%s: status = %d, want %d", user, res.Code, want)
+		}
+	}
+}
+
+func TestWithSuggestions(t *testing.T) {
+	m := seed()
+	tests := []struct {
+		name string
+		opts []admin.HandlerOption
+		want []string
+		not  []string
+	}{
+		{"default", nil, []string{admin.ManageCapability, "invoices:read"}, []string{"billing:refund"}},
+		{"with suggestions", []admin.HandlerOption{admin.WithSuggestions("billing:refund")}, []string{admin.ManageCapability, "billing:refund", "billing:*"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := post(t, admin.AssignHandler(m, tt.opts...), "roles-admin", map[string]any{"user": "ann", "role": "viewer", "grant": true})
+			html := rendered(res)
+			for _, c := range tt.want {
+				if !strings.Contains(html, `value="`+c+`"`) {
+					t.Errorf("suggestion %q missing from the re-rendered editor", c)
+				}
+			}
+			for _, c := range tt.not {
+				if strings.Contains(html, `value="`+c+`"`) {
+					t.Errorf("unexpected suggestion %q", c)
+				}
+			}
+		})
+	}
+}
+
+func TestResolver(t *testing.T) {
+	m := seed()
+	if err := m.SetUserRoles(context.Background(), "ann", []string{"viewer"}); err != nil {
+		t.Fatal(err)
+	}
+	var got capability.Set
+	h := webx.CapabilityMiddleware(admin.Resolver(m))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = capability.FromContext(r.Context())
+	}))
+	sess := webxtest.NewSession(t)
+
+	sess.Serve(t, h, webxtest.Request{})
+	if got == nil || len(got) != 0 {
+		t.Errorf("anonymous set = %#v, want empty", got)
+	}
+
+	login := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := webx.SetSessionUser(r, "ann"); err != nil {
+			t.Error(err)
+		}
+	})
+	sess.Serve(t, login, webxtest.Request{Method: http.MethodPost})
+	sess.Serve(t, h, webxtest.Request{})
+	if !slices.Equal(got, capability.Set{"invoices:read"}) {
+		t.Errorf("ann's set = %q", got)
+	}
+}
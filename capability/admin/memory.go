@@ -0,0 +1,124 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/plaenen/webx/capability"
+)
+
+// Memory is a thread-safe in-memory Repository. Changes that would make
+// the roles an invalid capability.Policy are rejected.
+type Memory struct {
+	mu    sync.Mutex
+	roles []capability.Role
+	users map[string][]string
+}
+
+// NewMemory creates a repository holding roles and no assignments.
+func NewMemory(roles ...capability.Role) *Memory {
+	return &Memory{roles: slices.Clone(roles), users: make(map[string][]string)}
+}
+
+// Roles returns a copy of the roles.
+func (m *Memory) Roles(context.Context) ([]capability.Role, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	roles := make([]capability.Role, len(m.roles))
+	for i, r := range m.roles {
+		roles[i] = cloneRole(r)
+	}
+	return roles, nil
+}
+
+// SaveRole creates or replaces a role after checking that the roles still
+// form a valid policy.
+func (m *Memory) SaveRole(_ context.Context, role capability.Role) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	next := slices.Clone(m.roles)
+	if i := m.index(role.Name); i >= 0 {
+		next[i] = cloneRole(role)
+	} else {
+		next = append(next, cloneRole(role))
+	}
+	if _, err := capability.NewPolicy(next...); err != nil {
+		return err
+	}
+	m.roles = next
+	return nil
+}
+
+// DeleteRole removes a role and its assignments.
+func (m *Memory) DeleteRole(_ context.Context, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := m.index(name)
+	if i < 0 {
+		return fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+	for _, r := range m.roles {
+		if slices.Contains(r.Includes, name) {
+			return fmt.Errorf("%w: %q includes %q", ErrRoleInUse, r.Name, name)
+		}
+	}
+	m.roles = slices.Delete(slices.Clone(m.roles), i, i+1)
+	for id, roles := range m.users {
+		m.setUser(id, slices.DeleteFunc(slices.Clone(roles), func(r string) bool { return r == name }))
+	}
+	return nil
+}
+
+// Users returns the users with at least one role, sorted by ID.
+func (m *Memory) Users(context.Context) ([]User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	users := make([]User, 0, len(m.users))
+	for id, roles := range m.users {
+		users = append(users, User{ID: id, Roles: slices.Clone(roles)})
+	}
+	slices.SortFunc(users, func(a, b User) int { return strings.Compare(a.ID, b.ID) })
+	return users, nil
+}
+
+// UserRoles returns the roles assigned to a user.
+func (m *Memory) UserRoles(_ context.Context, userID string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Clone(m.users[userID]), nil
+}
+
+// SetUserRoles replaces the roles assigned to a user. An empty list
+// removes the user.
+func (m *Memory) SetUserRoles(_ context.Context, userID string, roles []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, r := range roles {
+		if m.index(r) < 0 {
+			return fmt.Errorf("%w %q", capability.ErrUnknownRole, r)
+		}
+	}
+	m.setUser(userID, slices.Clone(roles))
+	return nil
+}
+
+func (m *Memory) setUser(userID string, roles []string) {
+	if len(roles) == 0 {
+		delete(m.users, userID)
+		return
+	}
+	m.users[userID] = roles
+}
+
+func (m *Memory) index(name string) int {
+	return slices.IndexFunc(m.roles, func(r capability.Role) bool { return r.Name == name })
+}
+
+func cloneRole(r capability.Role) capability.Role {
+	r.Capabilities = slices.Clone(r.Capabilities)
+	r.Includes = slices.Clone(r.Includes)
+	return r
+}
@@ -0,0 +1,107 @@
+package admin_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/plaenen/webx/capability"
+	"github.com/plaenen/webx/capability/admin"
+)
+
+func seed() *admin.Memory {
+	return admin.NewMemory(
+		capability.Role{Name: "viewer", Capabilities: capability.Set{"invoices:read"}},
+		capability.Role{Name: "accountant", Includes: []string{"viewer"}, Capabilities: capability.Set{"invoices:*", "!invoices:delete"}},
+	)
+}
+
+func TestMemory_Roles(t *testing.T) {
+	ctx := context.Background()
+	m := seed()
+
+	if err := m.SaveRole(ctx, capability.Role{Name: "viewer", Includes: []string{"accountant"}}); err == nil {
+		t.Error("expected a cycle to be rejected")
+	}
+	if err := m.SaveRole(ctx, capability.Role{Name: "auditor", Includes: []string{"ghost"}}); !errors.Is(err, capability.ErrUnknownRole) {
+		t.Errorf("unknown include: error = %v", err)
+	}
+	if err := m.SaveRole(ctx, capability.Role{Name: "viewer", Capabilities: capability.Set{"reports:read"}}); err != nil {
+		t.Fatal(err)
+	}
+	roles, _ := m.Roles(ctx)
+	if len(roles) != 2 || !slices.Equal(roles[0].Capabilities, capability.Set{"reports:read"}) {
+		t.Errorf("roles = %+v, want viewer replaced in place", roles)
+	}
+
+	// Roles returns copies.
+	roles[0].Capabilities[0] = "changed"
+	if again, _ := m.Roles(ctx); again[0].Capabilities[0] != "reports:read" {
+		t.Error("Roles exposed the stored slice")
+	}
+}
+
+func TestMemory_DeleteRole(t *testing.T) {
+	ctx := context.Background()
+	m := seed()
+	if err := m.SetUserRoles(ctx, "ann", []string{"viewer", "accountant"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.DeleteRole(ctx, "viewer"); !errors.Is(err, admin.ErrRoleInUse) {
+		t.Errorf("included role: error = %v, want ErrRoleInUse", err)
+	}
+	if err := m.DeleteRole(ctx, "ghost"); !errors.Is(err, admin.ErrNotFound) {
+		t.Errorf("unknown role: error = %v, want ErrNotFound", err)
+	}
+	if err := m.DeleteRole(ctx, "accountant"); err != nil {
+		t.Fatal(err)
+	}
+	if roles, _ := m.UserRoles(ctx, "ann"); !slices.Equal(roles, []string{"viewer"}) {
+		t.Errorf("ann's roles = %q, want the deleted role unassigned", roles)
+	}
+}
+
+func TestMemory_Users(t *testing.T) {
+	ctx := context.Background()
+	m := seed()
+	if err := m.SetUserRoles(ctx, "bob", []string{"viewer"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.SetUserRoles(ctx, "ann", []string{"accountant"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.SetUserRoles(ctx, "cid", []string{"ghost"}); !errors.Is(err, capability.ErrUnknownRole) {
+		t.Errorf("unknown role: error = %v", err)
+	}
+
+	users, _ := m.Users(ctx)
+	if len(users) != 2 || users[0].ID != "ann" || users[1].ID != "bob" {
+		t.Errorf("users = %+v, want ann and bob", users)
+	}
+	if err := m.SetUserRoles(ctx, "bob", nil); err != nil {
+		t.Fatal(err)
+	}
+	if users, _ := m.Users(ctx); len(users) != 1 {
+		t.Errorf("users = %+v, want bob removed", users)
+	}
+}
+
+func TestEffective(t *testing.T) {
+	ctx := context.Background()
+	m := seed()
+	if err := m.SetUserRoles(ctx, "ann", []string{"accountant"}); err != nil {
+		t.Fatal(err)
+	}
+	s, err := admin.Effective(ctx, m, "ann")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Can("invoices:write") || s.Can("invoices:delete") {
+		t.Errorf("ann's set = %q", s)
+	}
+	if s, _ := admin.Effective(ctx, m, "nobody"); s != nil {
+		t.Errorf("nobody's set = %q, want nil", s)
+	}
+}
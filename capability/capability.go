@@ -1,24 +1,35 @@
 package capability
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
-// Set is a collection of capability strings in "resource:action" format.
-// Supports exact match, resource wildcard ("resource:*"), and superadmin ("*").
+// Set is a collection of capability strings such as "invoices:read" or
+// "org:123:invoice:read". Segments are separated by colons.
+//
+// Entries are patterns:
+//   - "*" on its own matches everything (superadmin).
+//   - A "*" segment matches any one segment: "org:*:invoice:read".
+//   - A trailing "*" segment matches one or more segments: "invoices:*"
+//     matches "invoices:read" and "invoices:42:read", but not "invoices".
+//   - A leading "!" makes the entry a deny rule: "!billing:delete".
+//     A capability matched by a deny rule is refused, whatever allows it.
 type Set []string
 
 // Can reports whether this Set satisfies the required capability.
 func (s Set) Can(required string) bool {
+	allowed := false
 	for _, c := range s {
-		if c == "*" || c == required {
-			return true
-		}
-		if prefix, ok := strings.CutSuffix(c, ":*"); ok {
-			if res, _, ok := strings.Cut(required, ":"); ok && res == prefix {
-				return true
+		if deny, ok := strings.CutPrefix(c, "!"); ok {
+			if match(deny, required) {
+				return false
 			}
+		} else if !allowed {
+			allowed = match(c, required)
 		}
 	}
-	return false
+	return allowed
 }
 
 // CanAny reports whether this Set satisfies at least one of the required capabilities.
@@ -40,3 +51,45 @@ func (s Set) CanAll(required ...string) bool {
 	}
 	return true
 }
+
+// match reports whether the pattern, without any "!", matches required.
+func match(pattern, required string) bool {
+	if pattern == "*" || pattern == required {
+		return true
+	}
+	if pattern == "" || required == "" {
+		return false
+	}
+	p := strings.Split(pattern, ":")
+	r := strings.Split(required, ":")
+	for i, seg := range p {
+		if seg == "*" && i == len(p)-1 {
+			return len(r) > i
+		}
+		if i >= len(r) || (seg != "*" && seg != r[i]) {
+			return false
+		}
+	}
+	return len(p) == len(r)
+}
+
+// Validate reports whether c is a well-formed Set entry: non-empty
+// segments, "*" only as a whole segment and "!" only as the first
+// character.
+func Validate(c string) error {
+	pattern, _ := strings.CutPrefix(c, "!")
+	if pattern == "" {
+		return fmt.Errorf("capability: empty capability %q", c)
+	}
+	for seg := range strings.SplitSeq(pattern, ":") {
+		switch {
+		case seg == "":
+			return fmt.Errorf("capability: empty segment in %q", c)
+		case seg != "*" && strings.Contains(seg, "*"):
+			return fmt.Errorf("capability: wildcard must be a whole segment in %q", c)
+		case strings.Contains(seg, "!"):
+			return fmt.Errorf("capability: deny marker must lead in %q", c)
+		}
+	}
+	return nil
+}
@@ -123,3 +123,69 @@ func TestCanAny_Empty(t *testing.T) {
 		t.Error("expected CanAny with no args to return false")
 	}
 }
+
+func TestCan_Segments(t *testing.T) {
+	tests := []struct {
+		pattern, required string
+		want              bool
+	}{
+		{"org:123:invoice:read", "org:123:invoice:read", true},
+		{"org:*:invoice:read", "org:123:invoice:read", true},
+		{"org:*:invoice:read", "org:123:invoice:write", false},
+		{"org:*:invoice:read", "org:123:invoice", false},
+		{"org:*:invoice:read", "org:123:invoice:read:extra", false},
+		{"org:123:*", "org:123:invoice:read", true},
+		{"org:123:*", "org:456:invoice:read", false},
+		{"org:123:*", "org:123", false},
+		{"org:*:*", "org:123:invoice", true},
+		{"invoices:*", "invoices:42:read", true},
+		{"*:read", "invoices:read", true},
+		{"*:read", "invoices:write", false},
+	}
+	for _, tt := range tests {
+		if got := (capability.Set{tt.pattern}).Can(tt.required); got != tt.want {
+			t.Errorf("%q.Can(%q) = %v, want %v", tt.pattern, tt.required, got, tt.want)
+		}
+	}
+}
+
+func TestCan_Deny(t *testing.T) {
+	s := capability.Set{"*", "!billing:delete", "!org:*:secrets:*"}
+
+	if !s.Can("billing:read") {
+		t.Error("expected billing:read to be allowed")
+	}
+	if s.Can("billing:delete") {
+		t.Error("expected billing:delete to be denied")
+	}
+	if s.Can("org:1:secrets:read") {
+		t.Error("expected org:1:secrets:read to be denied")
+	}
+	if !s.CanAny("billing:delete", "billing:read") {
+		t.Error("expected CanAny to match the allowed capability")
+	}
+	if s.CanAll("billing:delete", "billing:read") {
+		t.Error("expected CanAll not to match when one is denied")
+	}
+
+	// A deny rule alone grants nothing, and its order does not matter.
+	if (capability.Set{"!billing:delete"}).Can("billing:read") {
+		t.Error("expected a deny rule not to allow anything")
+	}
+	if (capability.Set{"!billing:*", "billing:delete"}).Can("billing:delete") {
+		t.Error("expected deny to win over a later allow")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, c := range []string{"*", "invoices:read", "!billing:delete", "org:*:invoice:*"} {
+		if err := capability.Validate(c); err != nil {
+			t.Errorf("Validate(%q) = %v, want nil", c, err)
+		}
+	}
+	for _, c := range []string{"", "!", "invoices:", ":read", "a::b", "invoices:re*", "!!a", "a:!b"} {
+		if err := capability.Validate(c); err == nil {
+			t.Errorf("Validate(%q) = nil, want an error", c)
+		}
+	}
+}
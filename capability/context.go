@@ -0,0 +1,18 @@
+package capability
+
+import "context"
+
+type ctxKey struct{}
+
+// NewContext returns a copy of ctx carrying s as the current user's
+// capabilities. webx.CapabilityMiddleware calls it for every request.
+func NewContext(ctx context.Context, s Set) context.Context {
+	return context.WithValue(ctx, ctxKey{}, s)
+}
+
+// FromContext returns the capabilities stored by NewContext, or nil when
+// there are none. A nil Set allows nothing.
+func FromContext(ctx context.Context) Set {
+	s, _ := ctx.Value(ctxKey{}).(Set)
+	return s
+}
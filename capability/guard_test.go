@@ -0,0 +1,39 @@
+package capability_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/plaenen/webx/capability"
+)
+
+func TestGuard(t *testing.T) {
+	ctx := capability.NewContext(context.Background(), capability.Set{"invoices:*", "!invoices:delete"})
+	tests := []struct {
+		name  string
+		guard templ.Component
+		ctx   context.Context
+		want  bool
+	}{
+		{"allowed", capability.Guard("invoices:write"), ctx, true},
+		{"denied", capability.Guard("invoices:delete"), ctx, false},
+		{"missing", capability.Guard("users:read"), ctx, false},
+		{"no capabilities", capability.Guard("invoices:write"), context.Background(), false},
+		{"any", capability.GuardAny("users:read", "invoices:read"), ctx, true},
+		{"all", capability.GuardAll("users:read", "invoices:read"), ctx, false},
+	}
+	child := templ.Raw("<button>ok</button>")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := tt.guard.Render(templ.WithChildren(tt.ctx, child), &sb); err != nil {
+				t.Fatal(err)
+			}
+			if got := sb.String() != ""; got != tt.want {
+				t.Errorf("rendered %q, want children: %v", sb.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package capability
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// ErrUnknownRole is returned when a role name is not defined by the policy.
+var ErrUnknownRole = errors.New("capability: unknown role")
+
+// Role is a named set of capabilities. A role inherits the capabilities,
+// deny rules included, of the roles it includes.
+type Role struct {
+	Name         string   `json:"name" yaml:"name"`
+	Description  string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Capabilities Set      `json:"capabilities,omitempty" yaml:"capabilities,omitempty"`
+	Includes     []string `json:"includes,omitempty" yaml:"includes,omitempty"`
+}
+
+// Policy is a list of roles, usually loaded from a JSON or YAML document
+// so roles can be edited without recompiling:
+//
+//	roles:
+//	  - name: viewer
+//	    capabilities: ["invoices:read", "reports:read"]
+//	  - name: accountant
+//	    includes: [viewer]
+//	    capabilities: ["invoices:*", "!invoices:delete"]
+type Policy struct {
+	Roles []Role `json:"roles" yaml:"roles"`
+}
+
+// NewPolicy returns a policy of the given roles, validated.
+func NewPolicy(roles ...Role) (*Policy, error) {
+	p := &Policy{Roles: roles}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ParseJSON parses and validates a JSON policy document. Unknown fields
+// are rejected so typos do not silently drop capabilities.
+func ParseJSON(data []byte) (*Policy, error) {
+	var p Policy
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&p); err != nil {
+		return nil, fmt.Errorf("capability: parse policy: %w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// ParseYAML parses and validates a YAML policy document. Unknown fields
+// are rejected so typos do not silently drop capabilities.
+func ParseYAML(data []byte) (*Policy, error) {
+	var p Policy
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&p); err != nil {
+		return nil, fmt.Errorf("capability: parse policy: %w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// LoadPolicy reads a policy file, parsed as JSON when the name ends in
+// ".json" and as YAML otherwise.
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("capability: load policy: %w", err)
+	}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return ParseJSON(data)
+	}
+	return ParseYAML(data)
+}
+
+// Validate checks that role names are set and unique, that every
+// capability is well formed, and that includes name defined roles
+// without forming a cycle.
+func (p *Policy) Validate() error {
+	seen := make(map[string]bool, len(p.Roles))
+	for _, r := range p.Roles {
+		if r.Name == "" {
+			return errors.New("capability: role without a name")
+		}
+		if seen[r.Name] {
+			return fmt.Errorf("capability: role %q defined twice", r.Name)
+		}
+		seen[r.Name] = true
+		for _, c := range r.Capabilities {
+			if err := Validate(c); err != nil {
+				return fmt.Errorf("role %q: %w", r.Name, err)
+			}
+		}
+	}
+	for _, r := range p.Roles {
+		if _, err := p.Expand(r.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Role returns the role with the given name.
+func (p *Policy) Role(name string) (Role, bool) {
+	i := slices.IndexFunc(p.Roles, func(r Role) bool { return r.Name == name })
+	if i < 0 {
+		return Role{}, false
+	}
+	return p.Roles[i], true
+}
+
+// Expand returns the capabilities granted by the named roles and the roles
+// they include, in order and without duplicates.
+func (p *Policy) Expand(roles ...string) (Set, error) {
+	var s Set
+	done := make(map[string]bool)
+	var expand func(name string, path []string) error
+	expand = func(name string, path []string) error {
+		if slices.Contains(path, name) {
+			return fmt.Errorf("capability: role %q includes itself via %s", name, strings.Join(append(path, name), " -> "))
+		}
+		if done[name] {
+			return nil
+		}
+		r, ok := p.Role(name)
+		if !ok {
+			return fmt.Errorf("%w %q", ErrUnknownRole, name)
+		}
+		for _, inc := range r.Includes {
+			if err := expand(inc, append(path, name)); err != nil {
+				return err
+			}
+		}
+		for _, c := range r.Capabilities {
+			if !slices.Contains(s, c) {
+				s = append(s, c)
+			}
+		}
+		done[name] = true
+		return nil
+	}
+	for _, name := range roles {
+		if err := expand(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
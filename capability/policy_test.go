@@ -0,0 +1,138 @@
+package capability_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/plaenen/webx/capability"
+)
+
+const policyYAML = `
+roles:
+  - name: viewer
+    description: Read-only access
+    capabilities: ["invoices:read", "reports:read"]
+  - name: accountant
+    includes: [viewer]
+    capabilities: ["invoices:*", "!invoices:delete"]
+  - name: admin
+    includes: [accountant, viewer]
+    capabilities: ["*"]
+`
+
+const policyJSON = `{
+  "roles": [
+    {"name": "viewer", "description": "Read-only access", "capabilities": ["invoices:read", "reports:read"]},
+    {"name": "accountant", "includes": ["viewer"], "capabilities": ["invoices:*", "!invoices:delete"]},
+    {"name": "admin", "includes": ["accountant", "viewer"], "capabilities": ["*"]}
+  ]
+}`
+
+func TestParse(t *testing.T) {
+	for name, parse := range map[string]func() (*capability.Policy, error){
+		"yaml": func() (*capability.Policy, error) { return capability.ParseYAML([]byte(policyYAML)) },
+		"json": func() (*capability.Policy, error) { return capability.ParseJSON([]byte(policyJSON)) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			p, err := parse()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r, ok := p.Role("viewer"); !ok || r.Description != "Read-only access" {
+				t.Errorf("Role(viewer) = %+v, %v", r, ok)
+			}
+			s, err := p.Expand("admin")
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := capability.Set{"invoices:read", "reports:read", "invoices:*", "!invoices:delete", "*"}
+			if !slices.Equal(s, want) {
+				t.Errorf("Expand(admin) = %q, want %q", s, want)
+			}
+			// Deny rules are inherited: admin cannot delete invoices.
+			if s.Can("invoices:delete") || !s.Can("users:write") {
+				t.Errorf("unexpected admin permissions %q", s)
+			}
+		})
+	}
+}
+
+func TestExpand(t *testing.T) {
+	p, err := capability.ParseYAML([]byte(policyYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := p.Expand("viewer", "accountant")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Can("invoices:write") || s.Can("invoices:delete") || s.Can("users:read") {
+		t.Errorf("unexpected permissions %q", s)
+	}
+
+	if s, err := p.Expand(); err != nil || s != nil {
+		t.Errorf("Expand() = %q, %v; want nil, nil", s, err)
+	}
+	if _, err := p.Expand("ghost"); !errors.Is(err, capability.ErrUnknownRole) {
+		t.Errorf("Expand(ghost) error = %v, want ErrUnknownRole", err)
+	}
+}
+
+func TestPolicyValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		roles []capability.Role
+		want  string
+	}{
+		{"unnamed", []capability.Role{{}}, "without a name"},
+		{"duplicate", []capability.Role{{Name: "a"}, {Name: "a"}}, `"a" defined twice`},
+		{"bad capability", []capability.Role{{Name: "a", Capabilities: capability.Set{"a::b"}}}, `role "a": capability: empty segment`},
+		{"unknown include", []capability.Role{{Name: "a", Includes: []string{"b"}}}, `unknown role "b"`},
+		{"cycle", []capability.Role{
+			{Name: "a", Includes: []string{"b"}},
+			{Name: "b", Includes: []string{"a"}},
+		}, "a -> b -> a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := capability.NewPolicy(tt.roles...)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_UnknownField(t *testing.T) {
+	if _, err := capability.ParseYAML([]byte("roles:\n  - name: a\n    capabilites: [x:y]\n")); err == nil {
+		t.Error("expected a misspelled YAML field to be rejected")
+	}
+	if _, err := capability.ParseJSON([]byte(`{"roles": [{"name": "a", "capabilites": ["x:y"]}]}`)); err == nil {
+		t.Error("expected a misspelled JSON field to be rejected")
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{"roles.yaml": policyYAML, "roles.json": policyJSON} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		p, err := capability.LoadPolicy(path)
+		if err != nil {
+			t.Fatalf("LoadPolicy(%s): %v", name, err)
+		}
+		if len(p.Roles) != 3 {
+			t.Errorf("LoadPolicy(%s) loaded %d roles, want 3", name, len(p.Roles))
+		}
+	}
+	if _, err := capability.LoadPolicy(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
@@ -0,0 +1,70 @@
+package webx
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/plaenen/webx/capability"
+)
+
+func TestCapabilityMiddleware(t *testing.T) {
+	resolver := CapabilityResolverFunc(func(r *http.Request) (capability.Set, error) {
+		switch r.Header.Get("X-User") {
+		case "admin":
+			return capability.Set{"*", "!billing:delete"}, nil
+		case "clerk":
+			return capability.Set{"invoices:read"}, nil
+		case "broken":
+			return nil, errors.New("db down")
+		}
+		return nil, nil
+	})
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !slices.Equal(FromContext(r.Context()).Capabilities, capability.FromContext(r.Context())) {
+			t.Error("WebXContext and capability.FromContext disagree")
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name   string
+		guard  func(http.Handler) http.Handler
+		user   string
+		status int
+	}{
+		{"allowed", RequireCapability("invoices:read"), "clerk", http.StatusNoContent},
+		{"refused", RequireCapability("invoices:write"), "clerk", http.StatusForbidden},
+		{"denied", RequireCapability("billing:delete"), "admin", http.StatusForbidden},
+		{"anonymous", RequireCapability("invoices:read"), "", http.StatusForbidden},
+		{"any", RequireAnyCapability("invoices:write", "invoices:read"), "clerk", http.StatusNoContent},
+		{"all", RequireAllCapabilities("invoices:write", "invoices:read"), "clerk", http.StatusForbidden},
+		{"all admin", RequireAllCapabilities("invoices:write", "billing:read"), "admin", http.StatusNoContent},
+		{"resolver error", RequireCapability("invoices:read"), "broken", http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := CapabilityMiddleware(resolver)(tt.guard(ok))
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("X-User", tt.user)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+		})
+	}
+}
+
+func TestRequireCapability_WithoutMiddleware(t *testing.T) {
+	h := RequireCapability("invoices:read")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("handler ran without capabilities")
+	}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
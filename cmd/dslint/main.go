@@ -0,0 +1,77 @@
+// Command dslint reports Datastar attribute mistakes in .templ files and
+// generated _templ.go files. See package dslint for the rules.
+//
+//	go run ./cmd/dslint ./...
+//	go run ./cmd/dslint -allow data-tooltip:placement -signals cart ./ui ./pages
+//
+// It exits with status 1 when it finds problems, and 2 when it cannot read
+// the sources.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/token"
+	"os"
+	"strings"
+
+	"github.com/plaenen/webx/dslint"
+)
+
+func main() {
+	var (
+		allow   = flag.String("allow", "", "comma-separated data-* attributes that are not Datastar plugins")
+		signals = flag.String("signals", "", "comma-separated signal namespaces declared outside the checked files")
+		disable = flag.String("disable", "", "comma-separated rules to skip")
+		asJSON  = flag.Bool("json", false, "print diagnostics as JSON")
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: dslint [flags] [path ...]\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	paths := flag.Args()
+	if len(paths) == 0 {
+		paths = []string{"./..."}
+	}
+	c := dslint.NewChecker(token.NewFileSet(), dslint.Options{
+		Allow:   list(*allow),
+		Signals: list(*signals),
+		Disable: list(*disable),
+	})
+	for _, p := range paths {
+		if err := c.AddPath(p); err != nil {
+			fmt.Fprintln(os.Stderr, "dslint:", err)
+			os.Exit(2)
+		}
+	}
+
+	diags := c.Diagnostics()
+	if *asJSON {
+		if diags == nil {
+			diags = []dslint.Diagnostic{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(diags); err != nil {
+			fmt.Fprintln(os.Stderr, "dslint:", err)
+			os.Exit(2)
+		}
+	} else {
+		for _, d := range diags {
+			fmt.Println(d)
+		}
+	}
+	if len(diags) > 0 {
+		os.Exit(1)
+	}
+}
+
+func list(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
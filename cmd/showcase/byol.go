@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/plaenen/webx"
+)
+
+// byolPath is the URL prefix the bring-your-own-license Datastar files
+// are served under.
+const byolPath = "/byol/datastar/"
+
+// byol holds the Datastar Pro files loaded from a local directory. They
+// are licensed separately, so they are never embedded in the binary.
+type byol struct {
+	scripts  []webx.Script
+	bodyTags []webx.BodyTag
+	handler  http.Handler
+}
+
+// loadBYOL checks dir for the Pro bundle and returns what the pages need to
+// load it. datastar-pro.js is required; the inspector and Rocket are added
+// when present.
+func loadBYOL(dir string) (*byol, error) {
+	fsys := os.DirFS(dir)
+	if _, err := fs.Stat(fsys, "datastar-pro.js"); err != nil {
+		return nil, fmt.Errorf("datastar pro: %s: %w", dir, err)
+	}
+	b := &byol{
+		scripts: []webx.Script{{Src: byolPath + "datastar-pro.js"}},
+		handler: http.StripPrefix(byolPath, jsOnly(http.FileServerFS(fsys))),
+	}
+	if exists(fsys, "datastar-pro-rocket.js") {
+		b.scripts = append(b.scripts, webx.Script{Src: byolPath + "datastar-pro-rocket.js"})
+	}
+	if exists(fsys, "datastar-inspector.js") {
+		b.scripts = append(b.scripts, webx.Script{Src: byolPath + "datastar-inspector.js"})
+		b.bodyTags = append(b.bodyTags, webx.BodyTag{Tag: "<datastar-inspector></datastar-inspector>"})
+	}
+	return b, nil
+}
+
+func exists(fsys fs.FS, name string) bool {
+	_, err := fs.Stat(fsys, name)
+	return err == nil
+}
+
+// jsOnly limits h to JavaScript files, so nothing else that happens to be
+// in the directory is served.
+func jsOnly(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path.Ext(r.URL.Path) != ".js" || strings.HasSuffix(r.URL.Path, "/") {
+			http.NotFound(w, r)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/plaenen/webx"
+	"github.com/plaenen/webx/ui/toast"
+	"github.com/starfederation/datastar-go/datastar"
+)
+
+type flashHandlers struct{}
+
+func newFlashHandlers() *flashHandlers {
+	return &flashHandlers{}
+}
+
+func (f *flashHandlers) register(r chi.Router) {
+	r.Post("/api/flash/now", f.now)
+	r.Post("/api/flash/next", f.next)
+}
+
+// now queues a flash and delivers it over SSE right away.
+func (f *flashHandlers) now(w http.ResponseWriter, r *http.Request) {
+	if err := webx.Flash(r.Context(), flashLevel(r), "Saved just now."); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	sse := datastar.NewSSE(w, r)
+	toast.PushFlashes(r.Context(), sse)
+}
+
+// next queues a flash and reloads the page, which shows it through the
+// outlet in layouts.Base. The button sends the page's CSP nonce, so the
+// reload script runs under the page policy.
+func (f *flashHandlers) next(w http.ResponseWriter, r *http.Request) {
+	if err := webx.Flash(r.Context(), flashLevel(r), "Saved before the reload."); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	sse := datastar.NewSSE(w, r)
+	sse.ExecuteScript("window.location.reload()", webx.ScriptNonce(r))
+}
+
+func flashLevel(r *http.Request) webx.FlashLevel {
+	switch level := webx.FlashLevel(r.URL.Query().Get("level")); level {
+	case webx.FlashInfo, webx.FlashWarning, webx.FlashError:
+		return level
+	default:
+		return webx.FlashSuccess
+	}
+}
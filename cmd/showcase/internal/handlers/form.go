@@ -2,9 +2,9 @@ package handlers
 
 import (
 	"net/http"
-	"strings"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/plaenen/webx"
 	"github.com/plaenen/webx/ui/form"
 	"github.com/plaenen/webx/validators"
 	"github.com/starfederation/datastar-go/datastar"
@@ -38,10 +38,7 @@ func (f *formHandlers) login() http.HandlerFunc {
 			if signals.Email == "" {
 				errs = append(errs, form.FieldError{Field: "email_error", Message: "Email is required"})
 			} else {
-				res := validators.Email(signals.Email, false)
-				if !res.Valid {
-					errs = append(errs, form.FieldError{Field: "email_error", Message: res.Error})
-				}
+				errs = append(errs, form.Check("email_error", validators.Email(signals.Email, false).Result())...)
 			}
 			if signals.Password == "" {
 				errs = append(errs, form.FieldError{Field: "password_error", Message: "Password is required"})
@@ -51,9 +48,8 @@ func (f *formHandlers) login() http.HandlerFunc {
 			return errs
 		},
 		func(formID string, sse *datastar.ServerSentEventGenerator) {
-			sanitizedID := strings.ReplaceAll(formID, "-", "_")
 			sse.MarshalAndPatchSignals(map[string]any{
-				sanitizedID: map[string]any{
+				webx.SignalID(formID): map[string]any{
 					"success": "Login successful!",
 				},
 			})
@@ -82,10 +78,7 @@ func (f *formHandlers) contact() http.HandlerFunc {
 			if signals.Email == "" {
 				errs = append(errs, form.FieldError{Field: "email_error", Message: "Email is required"})
 			} else {
-				res := validators.Email(signals.Email, false)
-				if !res.Valid {
-					errs = append(errs, form.FieldError{Field: "email_error", Message: res.Error})
-				}
+				errs = append(errs, form.Check("email_error", validators.Email(signals.Email, false).Result())...)
 			}
 			if signals.Message == "" {
 				errs = append(errs, form.FieldError{Field: "message_error", Message: "Message is required"})
@@ -93,9 +86,8 @@ func (f *formHandlers) contact() http.HandlerFunc {
 			return errs
 		},
 		func(formID string, sse *datastar.ServerSentEventGenerator) {
-			sanitizedID := strings.ReplaceAll(formID, "-", "_")
 			sse.MarshalAndPatchSignals(map[string]any{
-				sanitizedID: map[string]any{
+				webx.SignalID(formID): map[string]any{
 					"success": "Message sent successfully!",
 				},
 			})
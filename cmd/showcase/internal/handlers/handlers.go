@@ -9,6 +9,7 @@ type Handlers struct {
 	form      *formHandlers
 	upload    *uploadHandlers
 	preview   *previewHandlers
+	flash     *flashHandlers
 }
 
 func New() *Handlers {
@@ -19,6 +20,7 @@ func New() *Handlers {
 		form:     newFormHandlers(),
 		upload:   newUploadHandlers(fileStore),
 		preview:  newPreviewHandlers(),
+		flash:    newFlashHandlers(),
 	}
 }
 
@@ -29,4 +31,5 @@ func (h *Handlers) RegisterRoutes(r chi.Router) {
 	h.form.register(r)
 	h.upload.register(r)
 	h.preview.register(r)
+	h.flash.register(r)
 }
@@ -12,13 +12,37 @@ func newValidateHandlers() *validateHandlers {
 	return &validateHandlers{}
 }
 
+// register mounts the validators. Card numbers and passwords are posted,
+// so they do not end up in URLs and access logs.
 func (v *validateHandlers) register(r chi.Router) {
 	r.Get("/api/validate/email", validator.Handler(func(value string) validator.Result {
-		res := validators.Email(value, false)
-		return validator.Result{Valid: res.Valid, Error: res.Error}
+		return validators.Email(value, false).Result()
 	}))
 	r.Get("/api/validate/email-mx", validator.Handler(func(value string) validator.Result {
-		res := validators.Email(value, true)
-		return validator.Result{Valid: res.Valid, Error: res.Error}
+		return validators.Email(value, true).Result()
+	}))
+	r.Get("/api/validate/phone", validator.Handler(func(value string) validator.Result {
+		return validators.Phone(value).Result
+	}))
+	r.Get("/api/validate/url", validator.Handler(func(value string) validator.Result {
+		return validators.URL(value).Result
+	}))
+	r.Get("/api/validate/iban", validator.Handler(func(value string) validator.Result {
+		return validators.IBAN(value).Result
+	}))
+	r.Post("/api/validate/card", validator.Handler(func(value string) validator.Result {
+		return validators.CreditCard(value).Result
+	}))
+	r.Get("/api/validate/postal-code", validator.Handler(func(value string) validator.Result {
+		return validators.PostalCode(value, "BE").Result
+	}))
+	r.Get("/api/validate/vat", validator.Handler(func(value string) validator.Result {
+		return validators.VAT(value).Result
+	}))
+	r.Post("/api/validate/password", validator.Handler(func(value string) validator.Result {
+		return validators.Password(value).Result
+	}))
+	r.Get("/api/validate/username", validator.Handler(func(value string) validator.Result {
+		return validators.Username(value)
 	}))
 }
@@ -69,6 +69,7 @@ var showcaseNav = []layouts.NavGroup{
 			{Label: "Radio", Href: "/components/radio", Icon: icon.CircleDot},
 			{Label: "Range", Href: "/components/range", Icon: icon.SlidersHorizontal},
 			{Label: "Rating", Href: "/components/rating", Icon: icon.Star},
+			{Label: "Roles Admin", Href: "/components/roles-admin", Icon: icon.UserCog},
 			{Label: "Progress", Href: "/components/progress", Icon: icon.CircleGauge},
 			{Label: "Radial Progress", Href: "/components/radial-progress", Icon: icon.CirclePercent},
 			{Label: "Select", Href: "/components/select", Icon: icon.ListCollapse},
@@ -414,7 +414,7 @@ func calendarStateDemo() templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = calendar.Calendar(calendar.Props{ID: "state-cal"}).Render(ctx, templ_7745c5c3_Buffer)
+		templ_7745c5c3_Err = calendar.Calendar(calendar.Props{ID: "state-cal", SyncURL: true}).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
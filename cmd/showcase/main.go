@@ -11,10 +11,12 @@ import (
 	"github.com/a-h/templ"
 	"github.com/go-chi/chi/v5"
 	"github.com/plaenen/webx"
+	"github.com/plaenen/webx/assets"
+	"github.com/plaenen/webx/capability/admin"
 	"github.com/plaenen/webx/cmd/showcase/internal/handlers"
 	"github.com/plaenen/webx/cmd/showcase/internal/pages"
-	memstore "github.com/plaenen/webx/cmd/showcase/internal/session"
 	"github.com/plaenen/webx/cmd/showcase/internal/static"
+	"github.com/plaenen/webx/session"
 	"github.com/plaenen/webx/ui"
 	"github.com/spf13/cobra"
 )
@@ -36,22 +38,33 @@ func serveCmd() *cobra.Command {
 	var (
 		port int
 		pro  bool
+		dir  string
 	)
 
 	cmd := &cobra.Command{
 		Use:   "serve",
 		Short: "Start the showcase HTTP server",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return serve(port, pro)
+			return serve(port, pro, dir)
 		},
 	}
 
 	cmd.Flags().IntVarP(&port, "port", "p", 3000, "port to listen on (0 for random)")
+	cmd.Flags().BoolVar(&pro, "pro", false, "use Datastar Pro from --byol and enable Pro-only component features")
+	cmd.Flags().StringVar(&dir, "byol", "byol/datastar", "directory holding your licensed Datastar Pro files")
 
 	return cmd
 }
 
-func serve(port int, pro bool) error {
+func serve(port int, pro bool, byolDir string) error {
+	var proBundle *byol
+	if pro {
+		var err error
+		if proBundle, err = loadBYOL(byolDir); err != nil {
+			return err
+		}
+	}
+
 	readmeBytes, err := os.ReadFile("README.md")
 	if err != nil {
 		slog.Warn("could not read README.md", "error", err)
@@ -61,15 +74,28 @@ func serve(port int, pro bool) error {
 	r := chi.NewRouter()
 
 	// Session + CSRF middleware
-	store := memstore.NewMemStore()
+	store := session.NewMemoryStore()
+	defer store.Close()
 	r.Use(webx.SessionMiddleware(store))
 	r.Use(webx.SecurityHeadersMiddleware())
+	// Demo pages load sample images from external hosts.
+	r.Use(webx.CSPMiddleware(webx.CSPOptions{ImgSrc: []string{"https:"}}))
+
+	staticFS, _ := fs.Sub(static.Static, "static")
+	staticAssets, err := assets.New(staticFS)
+	if err != nil {
+		return err
+	}
+	r.Use(staticAssets.Middleware())
 
 	// Set dev-mode flag, base path, and dependencies on every request
 	const basePath = "/showcase"
 	stylesheets := []webx.Stylesheet{{Href: "/assets/css/output.css"}}
 	scripts := []webx.Script{{Src: "/assets/js/datastar.js"}}
 	var bodyTags []webx.BodyTag
+	if pro {
+		scripts, bodyTags = proBundle.scripts, proBundle.bodyTags
+	}
 	r.Use(func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			wctx := webx.FromContext(r.Context())
@@ -78,13 +104,23 @@ func serve(port int, pro bool) error {
 			wctx.Stylesheets = stylesheets
 			wctx.Scripts = scripts
 			wctx.BodyTags = bodyTags
+			wctx.DatastarPro = pro
 			next.ServeHTTP(w, r.WithContext(wctx.WithContext(r.Context())))
 		})
 	})
 
-	// Serve static files (css, js) at /assets/
-	staticFS, _ := fs.Sub(static.Static, "static")
-	r.Handle("/assets/*", cacheControl(http.StripPrefix("/assets/", http.FileServerFS(staticFS))))
+	// Capabilities of the demo user, for the roles admin page
+	roles, err := newDemoRoles()
+	if err != nil {
+		return err
+	}
+	r.Use(webx.CapabilityMiddleware(demoCapabilities(roles)))
+
+	// Serve static files (css, js) at /assets/ under fingerprinted names
+	r.Handle("/assets/*", staticAssets)
+	if pro {
+		r.Handle(byolPath+"*", proBundle.handler)
+	}
 
 	r.Get("/favicon.ico", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNoContent)
@@ -151,12 +187,14 @@ func serve(port int, pro bool) error {
 	r.Get("/components/stack", templ.Handler(pages.Stacks()).ServeHTTP)
 	r.Get("/components/form", templ.Handler(pages.Forms()).ServeHTTP)
 	r.Get("/components/file-upload", templ.Handler(pages.FileUploads()).ServeHTTP)
+	r.Get("/components/roles-admin", rolesPage(roles))
 
 	// SSE API endpoints
 	h := handlers.New()
 	r.Route(basePath, func(r chi.Router) {
 		ui.RegisterRoutes(r)
 		h.RegisterRoutes(r)
+		admin.RegisterRoutes(r, roles, admin.WithSuggestions(roleSuggestions...))
 	})
 
 	ln, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
@@ -172,12 +210,3 @@ func serve(port int, pro bool) error {
 
 	return http.Serve(ln, r)
 }
-
-// cacheControl wraps a handler to set Cache-Control: no-cache so browsers
-// revalidate via ETags on each request.
-func cacheControl(h http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Cache-Control", "no-cache")
-		h.ServeHTTP(w, r)
-	})
-}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/a-h/templ"
+	"github.com/plaenen/webx"
+	"github.com/plaenen/webx/capability"
+	"github.com/plaenen/webx/capability/admin"
+	"github.com/plaenen/webx/cmd/showcase/internal/pages"
+)
+
+// demoUser is the user every showcase visitor acts as on the roles page.
+const demoUser = "you@example.com"
+
+// roleSuggestions are the capabilities the role editor offers.
+var roleSuggestions = []string{
+	"invoices:read", "invoices:write", "invoices:delete",
+	"reports:read", "reports:export", "users:read", "users:write",
+}
+
+// newDemoRoles returns the role repository behind the roles admin page,
+// seeded with a few roles and users.
+func newDemoRoles() (*admin.Memory, error) {
+	roles := admin.NewMemory(
+		capability.Role{Name: "viewer", Description: "Read-only access", Capabilities: capability.Set{"invoices:read", "reports:read"}},
+		capability.Role{Name: "accountant", Description: "Manages invoices, cannot delete them", Includes: []string{"viewer"}, Capabilities: capability.Set{"invoices:*", "!invoices:delete"}},
+		capability.Role{Name: "admin", Description: "Everything", Capabilities: capability.Set{"*"}},
+	)
+	ctx := context.Background()
+	for user, assigned := range map[string][]string{
+		demoUser:            {"accountant"},
+		"ann@example.com":   {"viewer"},
+		"admin@example.com": {"admin"},
+	} {
+		if err := roles.SetUserRoles(ctx, user, assigned); err != nil {
+			return nil, fmt.Errorf("seeding roles: %w", err)
+		}
+	}
+	return roles, nil
+}
+
+// demoCapabilities resolves the capabilities of demoUser. The visitor
+// always keeps admin.ManageCapability so the shared demo cannot be locked.
+func demoCapabilities(repo admin.Repository) webx.CapabilityResolver {
+	return webx.CapabilityResolverFunc(func(r *http.Request) (capability.Set, error) {
+		s, err := admin.Effective(r.Context(), repo, demoUser)
+		if err != nil {
+			return nil, err
+		}
+		return append(s, admin.ManageCapability), nil
+	})
+}
+
+// rolesPage renders the roles admin page from repo.
+func rolesPage(repo admin.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		props, err := admin.LoadProps(r.Context(), repo)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		props.Suggestions = roleSuggestions
+		templ.Handler(pages.RolesAdmin(props, demoUser)).ServeHTTP(w, r)
+	}
+}
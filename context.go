@@ -4,6 +4,10 @@ package webx
 import (
 	"context"
 	"fmt"
+
+	"github.com/a-h/templ"
+	"github.com/plaenen/webx/capability"
+	"github.com/plaenen/webx/ds"
 )
 
 // Stylesheet represents a <link rel="stylesheet"> tag to inject in <head>.
@@ -22,6 +26,12 @@ type BodyTag struct {
 	Tag string // e.g. "<datastar-inspector></datastar-inspector>"
 }
 
+// AssetResolver maps a static asset URL to the URL it is actually served at,
+// typically a content-hashed one.
+type AssetResolver interface {
+	URL(path string) string
+}
+
 type WebXContext struct {
 	CSRFToken   string
 	DevMode     bool
@@ -30,6 +40,17 @@ type WebXContext struct {
 	Stylesheets []Stylesheet
 	Scripts     []Script
 	BodyTags    []BodyTag
+	Nonce       string        // CSP nonce for this request, set by CSPMiddleware
+	Assets      AssetResolver // maps asset URLs to fingerprinted ones (see package assets)
+	// DatastarPro reports that the page loads the Datastar Pro bundle, so
+	// components may add Pro-only attributes. See IfPro.
+	DatastarPro bool
+	// Capabilities is the current user's capability set, set by
+	// CapabilityMiddleware.
+	Capabilities capability.Set
+
+	session  *sessionState // set by SessionMiddleware, used by RotateSession
+	required assetRegistry // assets declared with RequireAsset
 }
 
 func NewContext(ctx context.Context) *WebXContext {
@@ -56,7 +77,28 @@ func (wctx *WebXContext) APIPath(path string) string {
 	return wctx.BasePath + path
 }
 
+// AssetURL resolves an asset URL through Assets, or returns it unchanged
+// when no resolver is installed.
+func (wctx *WebXContext) AssetURL(path string) string {
+	if wctx.Assets == nil {
+		return path
+	}
+	return wctx.Assets.URL(path)
+}
+
+// IfPro returns attrs merged when DatastarPro is set, and nil otherwise, so
+// components can add Pro-only attributes that disappear in open-source
+// mode:
+//
+//	{ webx.FromContext(ctx).IfPro(ds.Persist(filter))... }
+func (wctx *WebXContext) IfPro(attrs ...templ.Attributes) templ.Attributes {
+	if !wctx.DatastarPro {
+		return nil
+	}
+	return ds.Merge(attrs...)
+}
+
 // Post returns a Datastar expression that performs a POST request to the given URL.
 func Post(url string) string {
-	return fmt.Sprintf("@post('%s')", url)
+	return fmt.Sprintf("@post(%s)", ds.Str(url))
 }
@@ -0,0 +1,178 @@
+package webx
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/a-h/templ"
+	"github.com/starfederation/datastar-go/datastar"
+)
+
+// CSPOptions configures the Content-Security-Policy set by CSPMiddleware.
+// Every list is appended to the defaults shown on each field.
+//
+// The default policy is what a webx page needs and nothing more:
+//
+//	default-src 'self'; script-src 'self' 'nonce-…' 'unsafe-eval';
+//	style-src 'self' 'unsafe-inline'; img-src 'self' data:;
+//	font-src 'self'; connect-src 'self'; object-src 'none';
+//	base-uri 'self'; form-action 'self'; frame-ancestors 'none'
+//
+// Datastar compiles data-* expressions with the Function constructor, which
+// CSP treats as eval, so script-src keeps 'unsafe-eval' unless DisableEval is
+// set. Script elements still need the nonce, so an injected <script> tag
+// does not run; data-* expressions are not covered by CSP and rely on
+// templ's escaping. Components set inline style attributes, hence
+// 'unsafe-inline' for styles.
+type CSPOptions struct {
+	// ScriptSrc adds sources to script-src (default 'self' plus the nonce).
+	ScriptSrc []string
+	// StyleSrc adds sources to style-src (default 'self' 'unsafe-inline').
+	StyleSrc []string
+	// ImgSrc adds sources to img-src (default 'self' data:).
+	ImgSrc []string
+	// FontSrc adds sources to font-src (default 'self').
+	FontSrc []string
+	// ConnectSrc adds sources to connect-src (default 'self'), e.g. the
+	// origin of an SSE endpoint on another host.
+	ConnectSrc []string
+	// FrameAncestors replaces the default 'none' for pages that may be
+	// embedded.
+	FrameAncestors []string
+
+	// DisableEval drops 'unsafe-eval' from script-src. Datastar stops
+	// working; only use it on pages that do not load Datastar.
+	DisableEval bool
+	// StrictStyles drops 'unsafe-inline' from style-src and requires the
+	// nonce on <style> elements instead. Inline style="" attributes are
+	// then blocked.
+	StrictStyles bool
+
+	// ReportURI is sent as report-uri when set.
+	ReportURI string
+	// ReportOnly sends Content-Security-Policy-Report-Only instead, so
+	// violations are reported but not blocked.
+	ReportOnly bool
+}
+
+// Policy renders the policy for one response using nonce.
+func (o CSPOptions) Policy(nonce string) string {
+	nonceSrc := "'nonce-" + nonce + "'"
+
+	script := []string{"'self'", nonceSrc}
+	if !o.DisableEval {
+		script = append(script, "'unsafe-eval'")
+	}
+	// A nonce in style-src makes browsers ignore 'unsafe-inline', so it is
+	// only added in strict mode.
+	style := []string{"'self'", "'unsafe-inline'"}
+	if o.StrictStyles {
+		style = []string{"'self'", nonceSrc}
+	}
+	ancestors := o.FrameAncestors
+	if len(ancestors) == 0 {
+		ancestors = []string{"'none'"}
+	}
+
+	directives := [][]string{
+		{"default-src", "'self'"},
+		append([]string{"script-src"}, append(script, o.ScriptSrc...)...),
+		append([]string{"style-src"}, append(style, o.StyleSrc...)...),
+		append([]string{"img-src", "'self'", "data:"}, o.ImgSrc...),
+		append([]string{"font-src", "'self'"}, o.FontSrc...),
+		append([]string{"connect-src", "'self'"}, o.ConnectSrc...),
+		{"object-src", "'none'"},
+		{"base-uri", "'self'"},
+		{"form-action", "'self'"},
+		append([]string{"frame-ancestors"}, ancestors...),
+	}
+	if o.ReportURI != "" {
+		directives = append(directives, []string{"report-uri", o.ReportURI})
+	}
+
+	parts := make([]string, len(directives))
+	for i, d := range directives {
+		parts[i] = strings.Join(d, " ")
+	}
+	return strings.Join(parts, "; ")
+}
+
+// CSPMiddleware generates a nonce per request, stores it in
+// WebXContext.Nonce (and in templ's context for templ script helpers), and
+// sets the Content-Security-Policy header. layouts.Base adds the nonce to
+// every script it renders.
+//
+// The policy also applies to the <script> elements that datastar-go's
+// sse.ExecuteScript and sse.Redirect patch into the page, and they run only
+// with the nonce of the page, not the one generated for the SSE request.
+// Send the page nonce with ds.WithScriptNonce and pass ScriptNonce to those
+// calls:
+//
+//	ds.Post("/api/save", ds.WithScriptNonce())
+//	sse.Redirect("/done", webx.ScriptNonce(r))
+func CSPMiddleware(opts ...CSPOptions) func(http.Handler) http.Handler {
+	var o CSPOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	header := "Content-Security-Policy"
+	if o.ReportOnly {
+		header = "Content-Security-Policy-Report-Only"
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			nonce, err := randomHex(16)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("failed to generate CSP nonce: %v", err), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set(header, o.Policy(nonce))
+
+			wctx := FromContext(r.Context())
+			wctx.Nonce = nonce
+			ctx := templ.WithNonce(wctx.WithContext(r.Context()), nonce)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// CSPNonceHeader carries the CSP nonce of the page that sent a Datastar
+// request. ds.WithScriptNonce sets it.
+const CSPNonceHeader = "X-CSP-Nonce"
+
+// nonceRegex matches the nonces CSPMiddleware generates.
+var nonceRegex = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// ScriptNonce returns the option that lets a script sent with
+// sse.ExecuteScript or sse.Redirect run under the page's CSP, using the
+// nonce in the CSPNonceHeader of r. Without a valid header the script is
+// sent without a nonce. It sets the script attributes, so it replaces an
+// earlier datastar.WithExecuteScriptAttributes.
+func ScriptNonce(r *http.Request) datastar.ExecuteScriptOption {
+	nonce := r.Header.Get(CSPNonceHeader)
+	if !nonceRegex.MatchString(nonce) {
+		return datastar.WithExecuteScriptAttributes()
+	}
+	return datastar.WithExecuteScriptAttributes(`nonce="` + nonce + `"`)
+}
+
+// openingTag matches <script and <style start tags that lack a nonce.
+var openingTag = regexp.MustCompile(`(?i)<(script|style)\b([^>]*)>`)
+
+// WithNonce returns tag with a nonce attribute added to every <script> and
+// <style> start tag that does not already carry one. layouts.Base applies it
+// to BodyTags; use it for any other trusted raw HTML containing scripts.
+func (bt BodyTag) WithNonce(nonce string) string {
+	if nonce == "" {
+		return bt.Tag
+	}
+	return openingTag.ReplaceAllStringFunc(bt.Tag, func(m string) string {
+		if strings.Contains(strings.ToLower(m), "nonce=") {
+			return m
+		}
+		sub := openingTag.FindStringSubmatch(m)
+		return "<" + sub[1] + ` nonce="` + nonce + `"` + sub[2] + ">"
+	})
+}
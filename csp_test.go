@@ -0,0 +1,137 @@
+package webx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/starfederation/datastar-go/datastar"
+)
+
+func TestCSPOptions_Policy(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     CSPOptions
+		contains []string
+		excludes []string
+	}{
+		{
+			name: "defaults",
+			contains: []string{
+				"default-src 'self'",
+				"script-src 'self' 'nonce-abc' 'unsafe-eval'",
+				"style-src 'self' 'unsafe-inline'",
+				"img-src 'self' data:",
+				"connect-src 'self'",
+				"object-src 'none'",
+				"frame-ancestors 'none'",
+			},
+			excludes: []string{"report-uri"},
+		},
+		{
+			name:     "extra sources",
+			opts:     CSPOptions{ImgSrc: []string{"https:"}, ConnectSrc: []string{"https://api.test"}, FrameAncestors: []string{"'self'"}},
+			contains: []string{"img-src 'self' data: https:", "connect-src 'self' https://api.test", "frame-ancestors 'self'"},
+			excludes: []string{"frame-ancestors 'none'"},
+		},
+		{
+			name:     "strict",
+			opts:     CSPOptions{DisableEval: true, StrictStyles: true, ReportURI: "/csp"},
+			contains: []string{"script-src 'self' 'nonce-abc'", "style-src 'self' 'nonce-abc'", "report-uri /csp"},
+			excludes: []string{"unsafe-eval", "unsafe-inline"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.opts.Policy("abc")
+			for _, s := range tt.contains {
+				if !strings.Contains(got, s) {
+					t.Errorf("policy missing %q:\n%s", s, got)
+				}
+			}
+			for _, s := range tt.excludes {
+				if strings.Contains(got, s) {
+					t.Errorf("policy contains %q:\n%s", s, got)
+				}
+			}
+		})
+	}
+}
+
+func TestCSPMiddleware(t *testing.T) {
+	var nonces []string
+	h := CSPMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nonce := FromContext(r.Context()).Nonce
+		if nonce == "" {
+			t.Fatal("nonce not set on WebXContext")
+		}
+		if got := templ.GetNonce(r.Context()); got != nonce {
+			t.Errorf("templ nonce = %q, want %q", got, nonce)
+		}
+		nonces = append(nonces, nonce)
+	}))
+	for range 2 {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		policy := rec.Header().Get("Content-Security-Policy")
+		if !strings.Contains(policy, "'nonce-"+nonces[len(nonces)-1]+"'") {
+			t.Errorf("header does not carry the request nonce: %s", policy)
+		}
+	}
+	if nonces[0] == nonces[1] {
+		t.Error("nonce reused across requests")
+	}
+
+	rec := httptest.NewRecorder()
+	CSPMiddleware(CSPOptions{ReportOnly: true})(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})).
+		ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Header().Get("Content-Security-Policy-Report-Only") == "" || rec.Header().Get("Content-Security-Policy") != "" {
+		t.Errorf("report-only headers = %v", rec.Header())
+	}
+}
+
+func TestBodyTag_WithNonce(t *testing.T) {
+	tests := []struct {
+		tag, nonce, want string
+	}{
+		{"<datastar-inspector></datastar-inspector>", "n", "<datastar-inspector></datastar-inspector>"},
+		{`<script src="/a.js"></script>`, "n", `<script nonce="n" src="/a.js"></script>`},
+		{`<SCRIPT>x()</SCRIPT><style>a{}</style>`, "n", `<SCRIPT nonce="n">x()</SCRIPT><style nonce="n">a{}</style>`},
+		{`<script nonce="keep"></script>`, "n", `<script nonce="keep"></script>`},
+		{`<script></script>`, "", `<script></script>`},
+		{`<scripts></scripts>`, "n", `<scripts></scripts>`},
+	}
+	for _, tt := range tests {
+		if got := (BodyTag{Tag: tt.tag}).WithNonce(tt.nonce); got != tt.want {
+			t.Errorf("WithNonce(%q) = %q, want %q", tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestScriptNonce(t *testing.T) {
+	nonce := strings.Repeat("ab", 16)
+	tests := []struct {
+		name, header, want string
+	}{
+		{"page nonce", nonce, `<script nonce="` + nonce + `" data-effect`},
+		{"missing", "", `<script data-effect`},
+		{"not a nonce", `x" onload="alert(1)`, `<script data-effect`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(CSPNonceHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			if err := datastar.NewSSE(rec, req).Redirect("/next", ScriptNonce(req)); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(rec.Body.String(), tt.want) {
+				t.Errorf("missing %s in\n%s", tt.want, rec.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package webx
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// CSRFOptions configures the cross-site request forgery checks that
+// SessionMiddleware runs on mutating requests (anything but GET, HEAD and
+// OPTIONS). Each layer is switched independently. The zero value keeps the
+// historic behaviour: only the X-CSRF-Token header is checked.
+type CSRFOptions struct {
+	// DisableToken turns off the X-CSRF-Token header check. Only do this
+	// when the Origin and Fetch-Metadata checks are enabled instead.
+	DisableToken bool
+
+	// CheckOrigin requires the Origin header (or, when absent, the origin of
+	// the Referer header) to be the request's own origin, scheme and host, or
+	// one of AllowedOrigins. Requests carrying neither header are rejected.
+	CheckOrigin bool
+	// TrustForwardedProto takes the request's scheme from the
+	// X-Forwarded-Proto header. Set it behind a proxy that terminates TLS
+	// and sets the header; otherwise requests without TLS count as http.
+	TrustForwardedProto bool
+	// AllowedOrigins lists extra origins such as "https://app.example.com".
+	// A leading "*." in the host ("https://*.example.com") matches any
+	// subdomain.
+	AllowedOrigins []string
+
+	// CheckFetchSite rejects requests whose Sec-Fetch-Site header is
+	// "cross-site", or "same-site" unless AllowSameSite is set. Browsers
+	// that do not send Fetch Metadata are let through.
+	CheckFetchSite bool
+	// AllowSameSite accepts requests from sibling subdomains.
+	AllowSameSite bool
+
+	// DevMode writes the failing check into 403 response bodies. Leave it
+	// off in production, where every rejection reads "forbidden".
+	DevMode bool
+}
+
+// isMutating reports whether CSRF checks apply to the request method.
+func isMutating(r *http.Request) bool {
+	return r.Method != http.MethodGet && r.Method != http.MethodHead && r.Method != http.MethodOptions
+}
+
+// check runs the enabled layers in order and returns the reason of the first
+// failure, or "" when the request passes.
+func (o CSRFOptions) check(r *http.Request, token string) string {
+	if o.CheckFetchSite {
+		if reason := o.checkFetchSite(r); reason != "" {
+			return reason
+		}
+	}
+	if o.CheckOrigin {
+		if reason := o.checkOrigin(r); reason != "" {
+			return reason
+		}
+	}
+	if !o.DisableToken {
+		headerToken := r.Header.Get("X-CSRF-Token")
+		if subtle.ConstantTimeCompare([]byte(headerToken), []byte(token)) != 1 {
+			return "invalid or missing CSRF token"
+		}
+	}
+	return ""
+}
+
+func (o CSRFOptions) checkFetchSite(r *http.Request) string {
+	switch site := r.Header.Get("Sec-Fetch-Site"); site {
+	case "", "same-origin", "none":
+		return ""
+	case "same-site":
+		if o.AllowSameSite {
+			return ""
+		}
+		return "cross-origin request blocked: Sec-Fetch-Site is same-site"
+	default:
+		return "cross-origin request blocked: Sec-Fetch-Site is " + site
+	}
+}
+
+func (o CSRFOptions) checkOrigin(r *http.Request) string {
+	origin := r.Header.Get("Origin")
+	if origin == "" || origin == "null" {
+		ref := r.Header.Get("Referer")
+		if ref == "" {
+			return "cross-origin request blocked: missing Origin and Referer headers"
+		}
+		u, err := url.Parse(ref)
+		if err != nil || u.Host == "" {
+			return "cross-origin request blocked: malformed Referer header"
+		}
+		origin = u.Scheme + "://" + u.Host
+	}
+	if o.originAllowed(r, origin) {
+		return ""
+	}
+	return "cross-origin request blocked: origin " + origin + " is not allowed"
+}
+
+func (o CSRFOptions) originAllowed(r *http.Request, origin string) bool {
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	if strings.EqualFold(u.Host, r.Host) && strings.EqualFold(u.Scheme, o.requestScheme(r)) {
+		return true
+	}
+	for _, allowed := range o.AllowedOrigins {
+		a, err := url.Parse(strings.TrimSuffix(allowed, "/"))
+		if err != nil || !strings.EqualFold(a.Scheme, u.Scheme) {
+			continue
+		}
+		if suffix, ok := strings.CutPrefix(a.Host, "*."); ok {
+			if strings.HasSuffix(strings.ToLower(u.Host), "."+strings.ToLower(suffix)) {
+				return true
+			}
+			continue
+		}
+		if strings.EqualFold(a.Host, u.Host) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestScheme returns the scheme the client used for r.
+func (o CSRFOptions) requestScheme(r *http.Request) string {
+	if r.TLS != nil {
+		return "https"
+	}
+	if o.TrustForwardedProto {
+		if proto := strings.ToLower(r.Header.Get("X-Forwarded-Proto")); proto == "https" || proto == "http" {
+			return proto
+		}
+	}
+	return "http"
+}
+
+// forbid writes a 403 response. The reason is only exposed in dev mode.
+func (o CSRFOptions) forbid(w http.ResponseWriter, reason string) {
+	if !o.DevMode {
+		reason = "forbidden"
+	}
+	http.Error(w, reason, http.StatusForbidden)
+}
@@ -0,0 +1,161 @@
+package webx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCSRFOptions_Layers(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    CSRFOptions
+		headers map[string]string
+		token   bool
+		want    int
+	}{
+		{"token only, valid", CSRFOptions{}, nil, true, http.StatusOK},
+		{"token only, missing", CSRFOptions{}, nil, false, http.StatusForbidden},
+		{"token disabled", CSRFOptions{DisableToken: true}, nil, false, http.StatusOK},
+
+		{"origin same host", CSRFOptions{CheckOrigin: true}, map[string]string{"Origin": "http://example.com"}, true, http.StatusOK},
+		{"origin foreign", CSRFOptions{CheckOrigin: true}, map[string]string{"Origin": "https://evil.test"}, true, http.StatusForbidden},
+		{"origin same host, other scheme", CSRFOptions{CheckOrigin: true}, map[string]string{"Origin": "https://example.com"}, true, http.StatusForbidden},
+		{"origin forwarded https", CSRFOptions{CheckOrigin: true, TrustForwardedProto: true}, map[string]string{"Origin": "https://example.com", "X-Forwarded-Proto": "https"}, true, http.StatusOK},
+		{"origin forwarded https, untrusted", CSRFOptions{CheckOrigin: true}, map[string]string{"Origin": "https://example.com", "X-Forwarded-Proto": "https"}, true, http.StatusForbidden},
+		{"origin http on forwarded https", CSRFOptions{CheckOrigin: true, TrustForwardedProto: true}, map[string]string{"Origin": "http://example.com", "X-Forwarded-Proto": "https"}, true, http.StatusForbidden},
+		{"origin allow-listed", CSRFOptions{CheckOrigin: true, AllowedOrigins: []string{"https://app.test/"}}, map[string]string{"Origin": "https://app.test"}, true, http.StatusOK},
+		{"origin wildcard", CSRFOptions{CheckOrigin: true, AllowedOrigins: []string{"https://*.app.test"}}, map[string]string{"Origin": "https://eu.app.test"}, true, http.StatusOK},
+		{"origin wildcard scheme mismatch", CSRFOptions{CheckOrigin: true, AllowedOrigins: []string{"https://*.app.test"}}, map[string]string{"Origin": "http://eu.app.test"}, true, http.StatusForbidden},
+		{"referer fallback", CSRFOptions{CheckOrigin: true}, map[string]string{"Referer": "http://example.com/page"}, true, http.StatusOK},
+		{"referer foreign", CSRFOptions{CheckOrigin: true}, map[string]string{"Origin": "null", "Referer": "https://evil.test/"}, true, http.StatusForbidden},
+		{"origin missing", CSRFOptions{CheckOrigin: true}, nil, true, http.StatusForbidden},
+
+		{"fetch same-origin", CSRFOptions{CheckFetchSite: true}, map[string]string{"Sec-Fetch-Site": "same-origin"}, true, http.StatusOK},
+		{"fetch cross-site", CSRFOptions{CheckFetchSite: true}, map[string]string{"Sec-Fetch-Site": "cross-site"}, true, http.StatusForbidden},
+		{"fetch same-site", CSRFOptions{CheckFetchSite: true}, map[string]string{"Sec-Fetch-Site": "same-site"}, true, http.StatusForbidden},
+		{"fetch same-site allowed", CSRFOptions{CheckFetchSite: true, AllowSameSite: true}, map[string]string{"Sec-Fetch-Site": "same-site"}, true, http.StatusOK},
+		{"fetch header absent", CSRFOptions{CheckFetchSite: true}, nil, true, http.StatusOK},
+		{"fetch cross-site, check off", CSRFOptions{}, map[string]string{"Sec-Fetch-Site": "cross-site"}, true, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mw := SessionMiddleware(newMapStore(), SessionOptions{CSRF: tt.opts})
+			_, first := serve(t, mw, httptest.NewRequest(http.MethodGet, "/", nil), nil)
+
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: first.SessionID})
+			if tt.token {
+				req.Header.Set("X-CSRF-Token", first.CSRFToken)
+			}
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec, _ := serve(t, mw, req, nil)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d (%s)", rec.Code, tt.want, strings.TrimSpace(rec.Body.String()))
+			}
+		})
+	}
+}
+
+func TestCSRFOptions_OriginOverTLS(t *testing.T) {
+	opts := CSRFOptions{CheckOrigin: true, DisableToken: true}
+	for origin, want := range map[string]bool{"https://example.com": true, "http://example.com": false} {
+		req := httptest.NewRequest(http.MethodPost, "https://example.com/", nil)
+		if got := opts.originAllowed(req, origin); got != want {
+			t.Errorf("originAllowed(%s) over TLS = %v, want %v", origin, got, want)
+		}
+	}
+}
+
+func TestCSRFOptions_DevModeReason(t *testing.T) {
+	for _, dev := range []bool{false, true} {
+		mw := SessionMiddleware(newMapStore(), SessionOptions{CSRF: CSRFOptions{CheckFetchSite: true, DevMode: dev}})
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.Header.Set("Sec-Fetch-Site", "cross-site")
+		rec, _ := serve(t, mw, req, nil)
+		body := strings.TrimSpace(rec.Body.String())
+		if got := strings.Contains(body, "Sec-Fetch-Site"); got != dev {
+			t.Errorf("DevMode=%v: body %q", dev, body)
+		}
+	}
+}
+
+func TestFormToken(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	withClock(t, &now)
+	mw := SessionMiddleware(newMapStore(), SessionOptions{CSRF: CSRFOptions{DisableToken: true, DevMode: true}})
+	guarded := func(h http.Handler) http.Handler { return mw(RequireFormToken("delete")(h)) }
+
+	var token, other string
+	_, first := serve(t, mw, httptest.NewRequest(http.MethodGet, "/", nil), func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		if token, err = FormToken(r.Context(), "delete"); err != nil {
+			t.Fatalf("FormToken: %v", err)
+		}
+		if other, err = FormToken(r.Context(), "rename"); err != nil {
+			t.Fatalf("FormToken: %v", err)
+		}
+	})
+
+	post := func(tok string, header bool) int {
+		target := "/"
+		if !header && tok != "" {
+			target += "?" + FormTokenParam + "=" + tok
+		}
+		req := httptest.NewRequest(http.MethodPost, target, nil)
+		req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: first.SessionID})
+		if header {
+			req.Header.Set(FormTokenHeader, tok)
+		}
+		rec, _ := serve(t, guarded, req, nil)
+		return rec.Code
+	}
+
+	if code := post("", false); code != http.StatusForbidden {
+		t.Errorf("missing token: status = %d, want 403", code)
+	}
+	if code := post(other, true); code != http.StatusForbidden {
+		t.Errorf("token for other action: status = %d, want 403", code)
+	}
+	if code := post(token, false); code != http.StatusOK {
+		t.Errorf("valid token: status = %d, want 200", code)
+	}
+	if code := post(token, true); code != http.StatusForbidden {
+		t.Errorf("replayed token: status = %d, want 403", code)
+	}
+
+	var late string
+	serve(t, mw, func() *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: first.SessionID})
+		return req
+	}(), func(w http.ResponseWriter, r *http.Request) {
+		late, _ = FormToken(r.Context(), "delete")
+	})
+	now = now.Add(FormTokenTTL)
+	if code := post(late, true); code != http.StatusForbidden {
+		t.Errorf("expired token: status = %d, want 403", code)
+	}
+
+	var tokens []string
+	serve(t, mw, func() *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: first.SessionID})
+		return req
+	}(), func(w http.ResponseWriter, r *http.Request) {
+		for range MaxFormTokens + 1 {
+			tok, _ := FormToken(r.Context(), "delete")
+			tokens = append(tokens, tok)
+		}
+	})
+	if code := post(tokens[0], true); code != http.StatusForbidden {
+		t.Errorf("token beyond MaxFormTokens: status = %d, want 403", code)
+	}
+	if code := post(tokens[1], true); code != http.StatusOK {
+		t.Errorf("oldest kept token: status = %d, want 200", code)
+	}
+}
@@ -0,0 +1,320 @@
+package ds
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http/httpguts"
+)
+
+// ActionOption customizes a backend action expression (@get, @post, etc.).
+//
+// Options check their arguments when they are created, and the action
+// checks how they combine when it is built. Invalid options are
+// programming errors, so both panic, the way regexp.MustCompile does;
+// snapshot tests catch them before they reach a browser. Each option says
+// which arguments it checks: pass compile-time constants for those, never
+// request data.
+type ActionOption func(*actionConfig)
+
+type actionConfig struct {
+	contentType    string // "json" or "form"
+	selector       string // form to submit with contentType form
+	filter         Filter
+	headers        []header
+	payload        Expr
+	openWhenHidden *bool
+	cancellation   RequestCancellation
+	retry          RetryMode
+	retryInterval  time.Duration
+	retryScaler    float64
+	retryMaxWait   time.Duration
+	retries        *int // nil = Datastar default; 0 = no retry; >0 = custom count
+}
+
+type header struct {
+	name  string
+	value Expr
+}
+
+// Content types for WithContentType.
+const (
+	// ContentTypeJSON sends signals as a JSON body, or as the datastar query
+	// parameter for GET. It is Datastar's default.
+	ContentTypeJSON = "json"
+	// ContentTypeForm sends the closest form, or the one WithSelector
+	// picks, as multipart/form-data. Use it for file uploads.
+	ContentTypeForm = "form"
+)
+
+// RetryMode says which failures Datastar retries.
+type RetryMode string
+
+const (
+	// RetryAuto retries network errors, not error responses. It is
+	// Datastar's default.
+	RetryAuto RetryMode = "auto"
+	// RetryError also retries responses with an error status.
+	RetryError RetryMode = "error"
+	// RetryAlways also reconnects after a stream ends normally.
+	RetryAlways RetryMode = "always"
+	// RetryNever never retries.
+	RetryNever RetryMode = "never"
+)
+
+// RequestCancellation says what happens to a request still in flight when
+// the element starts another one.
+type RequestCancellation struct{ js Expr }
+
+var (
+	// CancelAuto aborts the earlier request. It is Datastar's default.
+	CancelAuto = RequestCancellation{Str("auto")}
+	// CancelDisabled lets requests from the same element run concurrently.
+	CancelDisabled = RequestCancellation{Str("disabled")}
+)
+
+// CancelWith ties the request to an AbortController, such as one stored in
+// a signal, so other code can abort it. It panics when the expression is
+// empty.
+//
+//	ds.CancelWith(ds.Signal("search", "controller"))
+func CancelWith(controller Expr) RequestCancellation {
+	if controller == "" {
+		panic("ds: CancelWith: empty controller expression")
+	}
+	return RequestCancellation{controller}
+}
+
+// WithRetries sets the maximum number of retry attempts.
+// Use 0 to disable retries entirely. It panics when n is negative.
+func WithRetries(n int) ActionOption {
+	if n < 0 {
+		panic(fmt.Sprintf("ds: WithRetries: negative count %d", n))
+	}
+	return func(c *actionConfig) { c.retries = &n }
+}
+
+// WithContentType sets the content type for the action: ContentTypeJSON
+// or ContentTypeForm. It panics on any other value.
+func WithContentType(ct string) ActionOption {
+	if ct != ContentTypeJSON && ct != ContentTypeForm {
+		panic(fmt.Sprintf("ds: WithContentType: %q is not %q or %q", ct, ContentTypeJSON, ContentTypeForm))
+	}
+	return func(c *actionConfig) { c.contentType = ct }
+}
+
+// WithSelector picks the form to submit with ContentTypeForm, when it is
+// not the element's closest form. It panics when the selector is blank.
+//
+//	ds.Post("/upload", ds.WithContentType(ds.ContentTypeForm), ds.WithSelector("#avatar-form"))
+func WithSelector(selector string) ActionOption {
+	if strings.TrimSpace(selector) == "" {
+		panic("ds: WithSelector: empty selector")
+	}
+	return func(c *actionConfig) { c.selector = selector }
+}
+
+// WithFilterSignals limits the signals sent to those f selects. It panics
+// on the zero Filter.
+//
+//	ds.Post("/save", ds.WithFilterSignals(ds.Filter{Include: `^profile\.`}))
+func WithFilterSignals(f Filter) ActionOption {
+	if f == (Filter{}) {
+		panic("ds: WithFilterSignals: empty filter")
+	}
+	return func(c *actionConfig) { c.filter = f }
+}
+
+// WithHeader adds a request header with a fixed value. Mutating actions
+// set X-CSRF-Token themselves, so it cannot be overridden there. It panics
+// on an invalid header name. The value is encoded with Str, so it may come
+// from anywhere.
+func WithHeader(name, value string) ActionOption {
+	return WithHeaderExpr(name, Str(value))
+}
+
+// WithHeaderExpr adds a request header computed in the browser, such as a
+// signal value. It panics on an invalid header name or an empty
+// expression.
+//
+//	ds.WithHeaderExpr("X-Tenant", ds.Signal("app", "tenant"))
+func WithHeaderExpr(name string, value Expr) ActionOption {
+	if !httpguts.ValidHeaderFieldName(name) {
+		panic(fmt.Sprintf("ds: WithHeader: invalid header name %q", name))
+	}
+	if value == "" {
+		panic(fmt.Sprintf("ds: WithHeader: empty value expression for %s", name))
+	}
+	return func(c *actionConfig) { c.headers = append(c.headers, header{name, value}) }
+}
+
+// scriptNonceJS reads the CSP nonce of the page from one of its scripts.
+// Browsers hide the nonce attribute, but not the nonce property.
+const scriptNonceJS = `document.querySelector('script[nonce]')?.nonce||''`
+
+// WithScriptNonce sends the page's CSP nonce in the X-CSP-Nonce header, so
+// scripts the handler sends with sse.ExecuteScript or sse.Redirect can carry
+// it; see webx.ScriptNonce.
+func WithScriptNonce() ActionOption {
+	return WithHeaderExpr("X-CSP-Nonce", scriptNonceJS)
+}
+
+// WithPayload sends v, encoded as JSON, instead of the signals.
+func WithPayload(v any) ActionOption {
+	return WithPayloadExpr(JSON(v))
+}
+
+// WithPayloadExpr sends the object expr evaluates to instead of the
+// signals. It panics when the expression is empty.
+func WithPayloadExpr(expr Expr) ActionOption {
+	if expr == "" {
+		panic("ds: WithPayload: empty payload expression")
+	}
+	return func(c *actionConfig) { c.payload = expr }
+}
+
+// WithOpenWhenHidden keeps the connection open while the page is hidden.
+// By default Datastar closes it and reconnects when the page is shown.
+func WithOpenWhenHidden(open bool) ActionOption {
+	return func(c *actionConfig) { c.openWhenHidden = &open }
+}
+
+// WithRequestCancellation sets what happens to a request still in flight
+// when the element starts another one. It panics on the zero
+// RequestCancellation.
+func WithRequestCancellation(rc RequestCancellation) ActionOption {
+	if rc.js == "" {
+		panic("ds: WithRequestCancellation: zero RequestCancellation; use CancelAuto, CancelDisabled or CancelWith")
+	}
+	return func(c *actionConfig) { c.cancellation = rc }
+}
+
+// WithRetry sets which failures are retried. It panics on anything but the
+// RetryMode constants.
+func WithRetry(mode RetryMode) ActionOption {
+	switch mode {
+	case RetryAuto, RetryError, RetryAlways, RetryNever:
+	default:
+		panic(fmt.Sprintf("ds: WithRetry: unknown mode %q", mode))
+	}
+	return func(c *actionConfig) { c.retry = mode }
+}
+
+// WithRetryInterval sets the wait before the first retry. Datastar
+// defaults to one second. It panics when d is under a millisecond.
+func WithRetryInterval(d time.Duration) ActionOption {
+	if d < time.Millisecond {
+		panic(fmt.Sprintf("ds: WithRetryInterval: %v is less than 1ms", d))
+	}
+	return func(c *actionConfig) { c.retryInterval = d }
+}
+
+// WithRetryScaler sets the factor the wait grows by after each retry.
+// Datastar defaults to 2; 1 retries at a fixed interval. It panics when f
+// is below 1 or not finite.
+func WithRetryScaler(f float64) ActionOption {
+	if f < 1 || math.IsInf(f, 0) || math.IsNaN(f) {
+		panic(fmt.Sprintf("ds: WithRetryScaler: %v is not a finite number >= 1", f))
+	}
+	return func(c *actionConfig) { c.retryScaler = f }
+}
+
+// WithRetryMaxWait caps the wait between retries. Datastar defaults to 30
+// seconds. It panics when d is under a millisecond, and the action panics
+// when d is shorter than WithRetryInterval.
+func WithRetryMaxWait(d time.Duration) ActionOption {
+	if d < time.Millisecond {
+		panic(fmt.Sprintf("ds: WithRetryMaxWait: %v is less than 1ms", d))
+	}
+	return func(c *actionConfig) { c.retryMaxWait = d }
+}
+
+// noRetry is a pre-built option that disables retries.
+var noRetry = WithRetries(0)
+
+// buildAction constructs a @method('url', {options}) expression. The URL
+// is encoded with Str, so query strings may contain any characters.
+func buildAction(method, url string, csrf bool, opts []ActionOption) string {
+	cfg := &actionConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	cfg.validate(method, csrf)
+
+	var parts []string
+	var headers []string
+	if csrf {
+		headers = append(headers, fmt.Sprintf("'X-CSRF-Token': %s", csrfJS))
+	}
+	for _, h := range cfg.headers {
+		headers = append(headers, fmt.Sprintf("%s: %s", Str(h.name), h.value))
+	}
+	if len(headers) > 0 {
+		parts = append(parts, "headers: {"+strings.Join(headers, ", ")+"}")
+	}
+	if cfg.contentType != "" {
+		parts = append(parts, "contentType: "+string(Str(cfg.contentType)))
+	}
+	if cfg.selector != "" {
+		parts = append(parts, "selector: "+string(Str(cfg.selector)))
+	}
+	if cfg.filter != (Filter{}) {
+		parts = append(parts, "filterSignals: "+cfg.filter.String())
+	}
+	if cfg.payload != "" {
+		parts = append(parts, "payload: "+string(cfg.payload))
+	}
+	if cfg.openWhenHidden != nil {
+		parts = append(parts, "openWhenHidden: "+strconv.FormatBool(*cfg.openWhenHidden))
+	}
+	if cfg.cancellation.js != "" {
+		parts = append(parts, "requestCancellation: "+string(cfg.cancellation.js))
+	}
+	if cfg.retry != "" {
+		parts = append(parts, "retry: "+string(Str(string(cfg.retry))))
+	}
+	if cfg.retryInterval != 0 {
+		parts = append(parts, fmt.Sprintf("retryInterval: %d", cfg.retryInterval.Milliseconds()))
+	}
+	if cfg.retryScaler != 0 {
+		parts = append(parts, "retryScaler: "+string(Num(cfg.retryScaler)))
+	}
+	if cfg.retryMaxWait != 0 {
+		parts = append(parts, fmt.Sprintf("retryMaxWaitMs: %d", cfg.retryMaxWait.Milliseconds()))
+	}
+	if cfg.retries != nil {
+		parts = append(parts, fmt.Sprintf("retryMaxCount: %d", *cfg.retries))
+	}
+
+	if len(parts) == 0 {
+		return fmt.Sprintf("@%s(%s)", method, Str(url))
+	}
+	return fmt.Sprintf("@%s(%s, {%s})", method, Str(url), strings.Join(parts, ", "))
+}
+
+// validate checks how options combine.
+func (c *actionConfig) validate(method string, csrf bool) {
+	if c.selector != "" && c.contentType != ContentTypeForm {
+		panic(fmt.Sprintf("ds: @%s: WithSelector requires WithContentType(ContentTypeForm)", method))
+	}
+	if c.payload != "" && c.contentType == ContentTypeForm {
+		panic(fmt.Sprintf("ds: @%s: WithPayload cannot be combined with ContentTypeForm", method))
+	}
+	if c.retryMaxWait != 0 && c.retryInterval > c.retryMaxWait {
+		panic(fmt.Sprintf("ds: @%s: retry interval %v exceeds max wait %v", method, c.retryInterval, c.retryMaxWait))
+	}
+	seen := map[string]bool{}
+	for _, h := range c.headers {
+		name := strings.ToLower(h.name)
+		if csrf && name == "x-csrf-token" {
+			panic(fmt.Sprintf("ds: @%s: X-CSRF-Token is set automatically", method))
+		}
+		if seen[name] {
+			panic(fmt.Sprintf("ds: @%s: header %s set twice", method, h.name))
+		}
+		seen[name] = true
+	}
+}
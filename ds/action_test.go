@@ -0,0 +1,142 @@
+package ds_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/plaenen/webx/ds"
+)
+
+const csrfHeader = `'X-CSRF-Token': document.querySelector('meta[name=csrf-token]')?.content||''`
+
+func TestWithContentType(t *testing.T) {
+	got := ds.Get("/api/data", ds.WithContentType(ds.ContentTypeJSON))
+	assertString(t, got, "@get('/api/data', {contentType: 'json'})")
+}
+
+func TestWithSelector(t *testing.T) {
+	got := ds.Get("/upload", ds.WithContentType(ds.ContentTypeForm), ds.WithSelector("#avatar"))
+	assertString(t, got, "@get('/upload', {contentType: 'form', selector: '#avatar'})")
+}
+
+func TestWithFilterSignals(t *testing.T) {
+	got := ds.Get("/api/data", ds.WithFilterSignals(ds.Filter{Include: `^user\.`, Exclude: "password"}))
+	assertString(t, got, `@get('/api/data', {filterSignals: {include: /^user\./, exclude: /password/}})`)
+}
+
+func TestWithHeader(t *testing.T) {
+	got := ds.Get("/api/data", ds.WithHeader("X-Tenant", "it's"))
+	assertString(t, got, `@get('/api/data', {headers: {'X-Tenant': 'it\'s'}})`)
+
+	got = ds.Get("/api/data", ds.WithHeader("X-Tenant", "a\r\nb"))
+	assertString(t, got, `@get('/api/data', {headers: {'X-Tenant': 'a\r\nb'}})`)
+}
+
+func TestWithHeaderAfterCSRF(t *testing.T) {
+	got := ds.Post("/api/submit", ds.WithHeaderExpr("X-Tenant", ds.Signal("app", "tenant")))
+	assertString(t, got, "@post('/api/submit', {headers: {"+csrfHeader+", 'X-Tenant': $app.tenant}})")
+}
+
+func TestWithPayload(t *testing.T) {
+	got := ds.Post("/api/submit", ds.WithPayload(map[string]any{"id": 7}))
+	assertContains(t, got, `payload: {"id":7}`)
+	got = ds.Get("/api/data", ds.WithPayloadExpr(ds.Raw("{q: $q}")))
+	assertString(t, got, "@get('/api/data', {payload: {q: $q}})")
+}
+
+func TestWithOpenWhenHidden(t *testing.T) {
+	assertString(t, ds.Get("/feed", ds.WithOpenWhenHidden(true)), "@get('/feed', {openWhenHidden: true})")
+	assertString(t, ds.Get("/feed", ds.WithOpenWhenHidden(false)), "@get('/feed', {openWhenHidden: false})")
+}
+
+func TestWithRequestCancellation(t *testing.T) {
+	assertString(t, ds.Get("/q", ds.WithRequestCancellation(ds.CancelDisabled)), "@get('/q', {requestCancellation: 'disabled'})")
+	assertString(t, ds.Get("/q", ds.WithRequestCancellation(ds.CancelAuto)), "@get('/q', {requestCancellation: 'auto'})")
+	assertString(t, ds.Get("/q", ds.WithRequestCancellation(ds.CancelWith(ds.Signal("ctl")))), "@get('/q', {requestCancellation: $ctl})")
+}
+
+func TestWithRetryOptions(t *testing.T) {
+	got := ds.Get("/feed",
+		ds.WithRetries(4),
+		ds.WithRetry(ds.RetryError),
+		ds.WithRetryInterval(500*time.Millisecond),
+		ds.WithRetryScaler(1.5),
+		ds.WithRetryMaxWait(10*time.Second),
+	)
+	assertString(t, got, "@get('/feed', {retry: 'error', retryInterval: 500, retryScaler: 1.5, retryMaxWaitMs: 10000, retryMaxCount: 4})")
+}
+
+func TestActionOptionOrder(t *testing.T) {
+	// Options render in a fixed order regardless of how they are passed.
+	a := ds.Post("/x", ds.WithRetries(1), ds.WithOpenWhenHidden(true), ds.WithHeader("X-A", "1"))
+	b := ds.Post("/x", ds.WithHeader("X-A", "1"), ds.WithOpenWhenHidden(true), ds.WithRetries(1))
+	assertString(t, a, b)
+	if !strings.HasPrefix(a, "@post('/x', {headers: {") || !strings.HasSuffix(a, "retryMaxCount: 1})") {
+		t.Errorf("unexpected order: %s", a)
+	}
+}
+
+func TestActionOptionValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func()
+		want string
+	}{
+		{"negative retries", func() { ds.WithRetries(-1) }, "negative count"},
+		{"unknown content type", func() { ds.WithContentType("xml") }, `"xml" is not`},
+		{"empty selector", func() { ds.WithSelector(" ") }, "empty selector"},
+		{"empty filter", func() { ds.WithFilterSignals(ds.Filter{}) }, "empty filter"},
+		{"bad header name", func() { ds.WithHeader("X Bad", "v") }, "invalid header name"},
+		{"empty header expr", func() { ds.WithHeaderExpr("X-A", "") }, "empty value"},
+		{"empty payload", func() { ds.WithPayloadExpr("") }, "empty payload"},
+		{"zero cancellation", func() { ds.WithRequestCancellation(ds.RequestCancellation{}) }, "zero RequestCancellation"},
+		{"empty controller", func() { ds.CancelWith("") }, "empty controller"},
+		{"unknown retry mode", func() { ds.WithRetry("sometimes") }, "unknown mode"},
+		{"tiny retry interval", func() { ds.WithRetryInterval(time.Microsecond) }, "less than 1ms"},
+		{"scaler below 1", func() { ds.WithRetryScaler(0.5) }, "not a finite number"},
+		{"infinite scaler", func() { ds.WithRetryScaler(math.Inf(1)) }, "not a finite number"},
+		{"tiny max wait", func() { ds.WithRetryMaxWait(0) }, "less than 1ms"},
+
+		{"selector without form", func() { ds.Get("/x", ds.WithSelector("#f")) }, "requires WithContentType"},
+		{"payload with form", func() {
+			ds.Post("/x", ds.WithContentType(ds.ContentTypeForm), ds.WithPayload(1))
+		}, "cannot be combined"},
+		{"interval above max wait", func() {
+			ds.Get("/x", ds.WithRetryInterval(time.Minute), ds.WithRetryMaxWait(time.Second))
+		}, "exceeds max wait"},
+		{"csrf override", func() { ds.Post("/x", ds.WithHeader("x-csrf-token", "t")) }, "set automatically"},
+		{"duplicate header", func() { ds.Get("/x", ds.WithHeader("X-A", "1"), ds.WithHeader("x-a", "2")) }, "set twice"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertPanics(t, tt.fn, tt.want)
+		})
+	}
+}
+
+func TestGetAllowsCSRFHeader(t *testing.T) {
+	got := ds.Get("/x", ds.WithHeader("X-CSRF-Token", "t"))
+	assertString(t, got, "@get('/x', {headers: {'X-CSRF-Token': 't'}})")
+}
+
+func TestWithScriptNonce(t *testing.T) {
+	got := ds.Post("/x", ds.WithScriptNonce())
+	assertString(t, got, "@post('/x', {headers: {"+csrfHeader+", 'X-CSP-Nonce': document.querySelector('script[nonce]')?.nonce||''}})")
+}
+
+func assertPanics(t *testing.T, fn func(), substr string) {
+	t.Helper()
+	defer func() {
+		t.Helper()
+		r := recover()
+		if r == nil {
+			t.Fatalf("expected panic containing %q", substr)
+		}
+		if msg, _ := r.(string); !strings.Contains(msg, substr) {
+			t.Errorf("panic %v does not contain %q", r, substr)
+		}
+	}()
+	fn()
+}
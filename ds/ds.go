@@ -2,11 +2,12 @@
 //
 // Datastar's parameterized plugins use a colon separator (e.g. data-on:click),
 // NOT a hyphen (data-on-click). A hyphen is silently ignored as an unknown
-// plugin. This package makes that mistake impossible by construction.
+// plugin, while plugins such as data-on-intersect really are hyphenated.
+// This package makes that mistake impossible by construction, and its
+// typed modifiers do the same for suffixes such as __debounce.500ms.
 package ds
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/a-h/templ"
@@ -14,144 +15,162 @@ import (
 
 // --- Parameterized attributes (colon syntax) ---
 
-// On returns a data-on:<event> attribute.
+// On returns a data-on:<event> attribute. Like every helper taking an
+// expression, it accepts a string or an Expr.
 //
 //	ds.On("click", expr) → {"data-on:click": expr}
-func On(event, expr string) templ.Attributes {
-	return templ.Attributes{"data-on:" + event: expr}
+//	ds.On("input", expr, ds.Debounce(500*time.Millisecond)) → {"data-on:input__debounce.500ms": expr}
+func On[E Expression](event string, expr E, mods ...EventModifier) templ.Attributes {
+	return templ.Attributes{withModifiers("data-on:"+event, mods): string(expr)}
 }
 
-// OnClick is shorthand for On("click", expr).
-func OnClick(expr string) templ.Attributes {
-	return On("click", expr)
+// OnClick is shorthand for On("click", expr, mods...).
+func OnClick[E Expression](expr E, mods ...EventModifier) templ.Attributes {
+	return On("click", expr, mods...)
 }
 
 // Bind returns a data-bind:<signal> attribute.
-func Bind(signal string) templ.Attributes {
-	return templ.Attributes{"data-bind:" + signal: ""}
+func Bind(signal string, mods ...Casing) templ.Attributes {
+	return templ.Attributes{withModifiers("data-bind:"+signal, mods): ""}
 }
 
 // ClassToggle returns a data-class:<name> attribute (single class toggle).
-func ClassToggle(name, expr string) templ.Attributes {
-	return templ.Attributes{"data-class:" + name: expr}
+func ClassToggle[E Expression](name string, expr E, mods ...Casing) templ.Attributes {
+	return templ.Attributes{withModifiers("data-class:"+name, mods): string(expr)}
 }
 
-// Attr returns a data-attr:<name> attribute.
-func Attr(name, expr string) templ.Attributes {
-	return templ.Attributes{"data-attr:" + name: expr}
+// Attr returns a data-attr:<name> attribute. Datastar removes the
+// attribute while expr is false, so bind ARIA states that must stay present
+// to a string:
+//
+//	ds.Attr("aria-expanded", ds.Cond(ds.Signal("nav", "open"), ds.Str("true"), ds.Str("false")))
+func Attr[E Expression](name string, expr E) templ.Attributes {
+	return templ.Attributes{"data-attr:" + name: string(expr)}
 }
 
 // Style returns a data-style:<prop> attribute.
-func Style(prop, expr string) templ.Attributes {
-	return templ.Attributes{"data-style:" + prop: expr}
+func Style[E Expression](prop string, expr E) templ.Attributes {
+	return templ.Attributes{"data-style:" + prop: string(expr)}
 }
 
 // Computed returns a data-computed:<name> attribute.
-func Computed(name, expr string) templ.Attributes {
-	return templ.Attributes{"data-computed:" + name: expr}
+func Computed[E Expression](name string, expr E, mods ...Casing) templ.Attributes {
+	return templ.Attributes{withModifiers("data-computed:"+name, mods): string(expr)}
 }
 
 // Indicator returns a data-indicator:<name> attribute.
-func Indicator(name string) templ.Attributes {
-	return templ.Attributes{"data-indicator:" + name: ""}
+func Indicator(name string, mods ...Casing) templ.Attributes {
+	return templ.Attributes{withModifiers("data-indicator:"+name, mods): ""}
 }
 
 // Ref returns a data-ref:<name> attribute.
-func Ref(name string) templ.Attributes {
-	return templ.Attributes{"data-ref:" + name: ""}
+func Ref(name string, mods ...Casing) templ.Attributes {
+	return templ.Attributes{withModifiers("data-ref:"+name, mods): ""}
 }
 
 // --- Standalone attributes (no colon) ---
+//
+// Some plugin names contain hyphens (data-on-intersect, data-ignore-morph).
+// Those are whole names, not a parameter, so they must not become colons.
 
 // Signals returns a data-signals attribute.
-func Signals(value string) templ.Attributes {
-	return templ.Attributes{"data-signals": value}
+func Signals[E Expression](value E, mods ...SignalsModifier) templ.Attributes {
+	return templ.Attributes{withModifiers("data-signals", mods): string(value)}
 }
 
 // Show returns a data-show attribute.
-func Show(expr string) templ.Attributes {
-	return templ.Attributes{"data-show": expr}
+func Show[E Expression](expr E) templ.Attributes {
+	return templ.Attributes{"data-show": string(expr)}
 }
 
 // Text returns a data-text attribute.
-func Text(expr string) templ.Attributes {
-	return templ.Attributes{"data-text": expr}
+func Text[E Expression](expr E) templ.Attributes {
+	return templ.Attributes{"data-text": string(expr)}
 }
 
 // Class returns a data-class attribute (object syntax).
-func Class(value string) templ.Attributes {
-	return templ.Attributes{"data-class": value}
+func Class[E Expression](value E, mods ...Casing) templ.Attributes {
+	return templ.Attributes{withModifiers("data-class", mods): string(value)}
 }
 
 // Init returns a data-init attribute.
-func Init(expr string) templ.Attributes {
-	return templ.Attributes{"data-init": expr}
+func Init[E Expression](expr E, mods ...InitModifier) templ.Attributes {
+	return templ.Attributes{withModifiers("data-init", mods): string(expr)}
 }
 
 // Effect returns a data-effect attribute.
-func Effect(expr string) templ.Attributes {
-	return templ.Attributes{"data-effect": expr}
+func Effect[E Expression](expr E) templ.Attributes {
+	return templ.Attributes{"data-effect": string(expr)}
 }
 
-// --- Backend action expressions ---
+// OnIntersect returns a data-on-intersect attribute, which runs expr when
+// the element enters the viewport. Modifiers: Once, Half, Full, Exit,
+// Delay, Debounce, Throttle, ViewTransition.
 //
-// These return JS expressions for use in data-on:* handlers.
-// Mutating methods (POST, PUT, PATCH, DELETE) automatically include
-// the CSRF token from <meta name="csrf-token">.
-//
-// By default, Datastar's built-in retry behavior is used (retry: 'auto',
-// retryMaxCount: 10). Use WithRetries to customize, or the *Once
-// convenience functions for single-shot requests.
+//	ds.OnIntersect(ds.Get("/more"), ds.Once) → {"data-on-intersect__once": "@get('/more')"}
+func OnIntersect[E Expression](expr E, mods ...IntersectModifier) templ.Attributes {
+	return templ.Attributes{withModifiers("data-on-intersect", mods): string(expr)}
+}
 
-// csrfJS is the JS expression that reads the CSRF token from the meta tag.
-const csrfJS = `document.querySelector('meta[name=csrf-token]')?.content||''`
+// OnInterval returns a data-on-interval attribute, which runs expr
+// repeatedly. Set the period with Duration.
+//
+//	ds.OnInterval(ds.Get("/poll"), ds.Duration(5*time.Second)) → {"data-on-interval__duration.5000ms": "@get('/poll')"}
+func OnInterval[E Expression](expr E, mods ...IntervalModifier) templ.Attributes {
+	return templ.Attributes{withModifiers("data-on-interval", mods): string(expr)}
+}
 
-// ActionOption customizes a backend action expression (@get, @post, etc.).
-type ActionOption func(*actionConfig)
+// OnSignalPatch returns a data-on-signal-patch attribute, which runs expr
+// whenever signals change; patch holds the changes. Modifiers: Delay,
+// Debounce, Throttle. Narrow it with OnSignalPatchFilter.
+func OnSignalPatch[E Expression](expr E, mods ...SignalPatchModifier) templ.Attributes {
+	return templ.Attributes{withModifiers("data-on-signal-patch", mods): string(expr)}
+}
 
-type actionConfig struct {
-	retries     *int   // nil = Datastar default; 0 = no retry; >0 = custom count
-	contentType string // e.g. "form" for multipart/form-data
+// OnSignalPatchFilter returns a data-on-signal-patch-filter attribute,
+// limiting OnSignalPatch on the same element to the signals f selects.
+func OnSignalPatchFilter(f Filter) templ.Attributes {
+	return templ.Attributes{"data-on-signal-patch-filter": f.String()}
 }
 
-// WithRetries sets the maximum number of retry attempts.
-// Use 0 to disable retries entirely.
-func WithRetries(n int) ActionOption {
-	return func(c *actionConfig) { c.retries = &n }
+// Ignore returns a data-ignore attribute: Datastar skips the element and
+// its descendants. With Self it skips only the element.
+func Ignore(mods ...IgnoreFlag) templ.Attributes {
+	return templ.Attributes{withModifiers("data-ignore", mods): ""}
 }
 
-// WithContentType sets the content type for the action.
-// Use "form" to send multipart/form-data (file uploads).
-func WithContentType(ct string) ActionOption {
-	return func(c *actionConfig) { c.contentType = ct }
+// IgnoreMorph returns a data-ignore-morph attribute: patches leave the
+// element and its descendants untouched.
+func IgnoreMorph() templ.Attributes {
+	return templ.Attributes{"data-ignore-morph": ""}
 }
 
-// noRetry is a pre-built option that disables retries.
-var noRetry = WithRetries(0)
+// PreserveAttr returns a data-preserve-attr attribute: patches keep the
+// current values of the named attributes, such as "open" on <details>.
+func PreserveAttr(names ...string) templ.Attributes {
+	return templ.Attributes{"data-preserve-attr": strings.Join(names, " ")}
+}
 
-// buildAction constructs a @method('url', {options}) expression.
-func buildAction(method, url string, csrf bool, opts []ActionOption) string {
-	cfg := &actionConfig{}
-	for _, opt := range opts {
-		opt(cfg)
-	}
+// JSONSignals returns a data-json-signals attribute, which shows the
+// signals f selects as JSON, for debugging. The zero Filter shows all of
+// them. Terse prints compact JSON.
+func JSONSignals(f Filter, mods ...JSONSignalsFlag) templ.Attributes {
+	return templ.Attributes{withModifiers("data-json-signals", mods): f.String()}
+}
 
-	var parts []string
-	if csrf {
-		parts = append(parts, fmt.Sprintf("headers: {'X-CSRF-Token': %s}", csrfJS))
-	}
-	if cfg.contentType != "" {
-		parts = append(parts, fmt.Sprintf("contentType: '%s'", cfg.contentType))
-	}
-	if cfg.retries != nil {
-		parts = append(parts, fmt.Sprintf("retryMaxCount: %d", *cfg.retries))
-	}
+// --- Backend action expressions ---
+//
+// These return JS expressions for use in data-on:* handlers.
+// Mutating methods (POST, PUT, PATCH, DELETE) automatically include
+// the CSRF token from <meta name="csrf-token">.
+//
+// By default, Datastar's built-in retry behavior is used (retry: 'auto',
+// retryMaxCount: 10). Use WithRetries to customize, or the *Once
+// convenience functions for single-shot requests. The other fetch options
+// are in action.go.
 
-	if len(parts) == 0 {
-		return fmt.Sprintf("@%s('%s')", method, url)
-	}
-	return fmt.Sprintf("@%s('%s', {%s})", method, url, strings.Join(parts, ", "))
-}
+// csrfJS is the JS expression that reads the CSRF token from the meta tag.
+const csrfJS = `document.querySelector('meta[name=csrf-token]')?.content||''`
 
 // Get returns a @get('url') expression.
 //
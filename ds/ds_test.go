@@ -3,6 +3,7 @@ package ds_test
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/plaenen/webx/ds"
 )
@@ -114,6 +115,8 @@ func TestNoHyphenInParameterizedAttrs(t *testing.T) {
 		{"Computed", ds.Computed("name", "x")},
 		{"Indicator", ds.Indicator("load")},
 		{"Ref", ds.Ref("el")},
+		{"On with modifiers", ds.On("input", "x", ds.Debounce(time.Second), ds.Window)},
+		{"Bind with modifiers", ds.Bind("val", ds.Case(ds.CaseCamel))},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,221 @@
+package ds
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// --- Expressions ---
+//
+// Datastar attribute values are JavaScript expressions. Building them with
+// fmt.Sprintf("'%s'", v) breaks as soon as v contains a quote or backslash,
+// and lets user input run as script. Expr values keep literals and code
+// apart: Str, Num, Bool and JSON encode Go values as JS literals, Signal
+// references a signal, and Raw marks hand-written code.
+//
+//	ds.Set(ds.Signal("filter", "q"), ds.Str(query)) // → $filter.q = 'it\'s'
+
+// Expr is a JavaScript expression for a Datastar attribute. Every helper
+// in this package that takes an expression accepts an Expr or a plain
+// string.
+type Expr string
+
+// Expression is satisfied by string and Expr.
+type Expression interface{ ~string }
+
+func (e Expr) String() string { return string(e) }
+
+// Raw returns js unchanged. Use it for hand-written code only, never for
+// values that may come from users.
+func Raw(js string) Expr { return Expr(js) }
+
+// Str returns s as a single-quoted JavaScript string literal. Quotes,
+// backslashes, control characters and line separators are escaped, and so
+// is $, which Datastar would otherwise read as a signal reference.
+//
+//	ds.Str("it's") // → 'it\'s'
+func Str(s string) Expr {
+	var b strings.Builder
+	b.Grow(len(s) + 2)
+	b.WriteByte('\'')
+	for _, r := range s {
+		switch r {
+		case '\'', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '$', '\u2028', '\u2029':
+			b.WriteString(`\u`)
+			b.WriteString(strconv.FormatInt(int64(r)|0x10000, 16)[1:])
+		default:
+			if r < 0x20 || r == 0x7f {
+				b.WriteString(`\x`)
+				b.WriteString(strconv.FormatInt(int64(r)|0x100, 16)[1:])
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('\'')
+	return Expr(b.String())
+}
+
+// Number is any Go integer or float type.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Num returns n as a JavaScript number literal. NaN and infinities become
+// NaN and Infinity.
+func Num[N Number](n N) Expr {
+	f := float64(n)
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "Infinity"
+	case math.IsInf(f, -1):
+		return "-Infinity"
+	case f == math.Trunc(f) && math.Abs(f) < 1<<53:
+		return Expr(strconv.FormatInt(int64(f), 10))
+	}
+	return Expr(strconv.FormatFloat(f, 'g', -1, 64))
+}
+
+// Bool returns true or false.
+func Bool(b bool) Expr { return Expr(strconv.FormatBool(b)) }
+
+// JSON returns v encoded as a JavaScript literal, for objects and arrays.
+// Values JSON cannot encode become null.
+func JSON(v any) Expr {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "null"
+	}
+	// json.Marshal already escapes <, >, & and the line separators. $ can
+	// only appear inside strings, where the escape keeps Datastar from
+	// reading it as a signal reference.
+	return Expr(strings.ReplaceAll(string(data), "$", `\u0024`))
+}
+
+// Signal returns a reference to the signal at path. Hyphens become
+// underscores, matching the namespaces utils.Signals creates.
+//
+//	ds.Signal("date-picker", "open") // → $date_picker.open
+func Signal(path ...string) Expr {
+	return Expr("$" + strings.ReplaceAll(strings.Join(path, "."), "-", "_"))
+}
+
+// --- Operators ---
+
+// Set returns target = value.
+func Set[T, V Expression](target T, value V) Expr {
+	return Expr(string(target) + " = " + string(value))
+}
+
+// Eq returns a === b.
+func Eq[A, B Expression](a A, b B) Expr {
+	return Expr(string(a) + " === " + string(b))
+}
+
+// Neq returns a !== b.
+func Neq[A, B Expression](a A, b B) Expr {
+	return Expr(string(a) + " !== " + string(b))
+}
+
+// Not returns !e.
+func Not[E Expression](e E) Expr {
+	return Expr("!" + group(string(e)))
+}
+
+// And joins exprs with &&.
+func And[E Expression](exprs ...E) Expr { return join(" && ", exprs) }
+
+// Or joins exprs with ||.
+func Or[E Expression](exprs ...E) Expr { return join(" || ", exprs) }
+
+// Cond returns cond ? then : els.
+func Cond[C, T, F Expression](cond C, then T, els F) Expr {
+	return Expr(group(string(cond)) + " ? " + group(string(then)) + " : " + group(string(els)))
+}
+
+// Seq joins statements with semicolons, for handlers that do several
+// things.
+func Seq[E Expression](stmts ...E) Expr {
+	parts := make([]string, len(stmts))
+	for i, s := range stmts {
+		parts[i] = string(s)
+	}
+	return Expr(strings.Join(parts, "; "))
+}
+
+func join[E Expression](sep string, exprs []E) Expr {
+	parts := make([]string, len(exprs))
+	for i, e := range exprs {
+		parts[i] = group(string(e))
+	}
+	return Expr(strings.Join(parts, sep))
+}
+
+// group parenthesizes e unless it is a single operand: a literal, a
+// signal reference, an already parenthesized expression or a negation of
+// one of those.
+func group(e string) string {
+	if isOperand(e) {
+		return e
+	}
+	return "(" + e + ")"
+}
+
+func isOperand(e string) bool {
+	e = strings.TrimLeft(e, "!")
+	if e == "" {
+		return false
+	}
+	if (e[0] == '\'' || e[0] == '(') && closes(e) {
+		return true
+	}
+	for _, r := range e {
+		if !(r == '$' || r == '.' || r == '_' ||
+			r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// closes reports whether the quote or parenthesis opening e closes at its
+// last byte, as in 'a' or (a || b), but not 'a' + 'b' or (a) || (b).
+func closes(e string) bool {
+	depth, quoted := 0, false
+	for i := 0; i < len(e); i++ {
+		c := e[i]
+		switch {
+		case quoted && c == '\\':
+			i++
+		case c == '\'':
+			quoted = !quoted
+			if !quoted && e[0] == '\'' && i < len(e)-1 {
+				return false
+			}
+		case quoted:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 && i < len(e)-1 {
+				return false
+			}
+		}
+	}
+	return !quoted && depth == 0
+}
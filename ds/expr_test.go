@@ -0,0 +1,99 @@
+package ds_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/plaenen/webx/ds"
+)
+
+func TestStr(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"", `''`},
+		{"dark", `'dark'`},
+		{"it's", `'it\'s'`},
+		{`C:\temp`, `'C:\\temp'`},
+		{"a\nb\tc\r", `'a\nb\tc\r'`},
+		{"$count++", `'\u0024count++'`},
+		{"x\u2028y\u2029", `'x\u2028y\u2029'`},
+		{"\x00\x1b\x7f", `'\x00\x1b\x7f'`},
+		{"'); alert(1); ('", `'\'); alert(1); (\''`},
+		{"héllo 世界", `'héllo 世界'`},
+	}
+	for _, tt := range tests {
+		if got := ds.Str(tt.in); string(got) != tt.want {
+			t.Errorf("Str(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNum(t *testing.T) {
+	assertString(t, ds.Num(42).String(), "42")
+	assertString(t, ds.Num(-7).String(), "-7")
+	assertString(t, ds.Num(uint8(255)).String(), "255")
+	assertString(t, ds.Num(1.5).String(), "1.5")
+	assertString(t, ds.Num(float32(0.25)).String(), "0.25")
+	assertString(t, ds.Num(3.0).String(), "3")
+	assertString(t, ds.Num(1e21).String(), "1e+21")
+	assertString(t, ds.Num(math.NaN()).String(), "NaN")
+	assertString(t, ds.Num(math.Inf(1)).String(), "Infinity")
+	assertString(t, ds.Num(math.Inf(-1)).String(), "-Infinity")
+}
+
+func TestBool(t *testing.T) {
+	assertString(t, ds.Bool(true).String(), "true")
+	assertString(t, ds.Bool(false).String(), "false")
+}
+
+func TestJSON(t *testing.T) {
+	got := ds.JSON(map[string]any{"name": "</script>$x", "n": 1})
+	assertString(t, got.String(), `{"n":1,"name":"\u003c/script\u003e\u0024x"}`)
+	assertString(t, ds.JSON(make(chan int)).String(), "null")
+}
+
+func TestSignal(t *testing.T) {
+	assertString(t, ds.Signal("open").String(), "$open")
+	assertString(t, ds.Signal("date-picker", "open").String(), "$date_picker.open")
+}
+
+func TestOperators(t *testing.T) {
+	open := ds.Signal("menu", "open")
+	tests := []struct {
+		name string
+		got  ds.Expr
+		want string
+	}{
+		{"set", ds.Set(ds.Signal("f", "q"), ds.Str("it's")), `$f.q = 'it\'s'`},
+		{"set string target", ds.Set("$x", ds.Num(1)), `$x = 1`},
+		{"eq", ds.Eq(ds.Signal("tab"), ds.Str("a")), `$tab === 'a'`},
+		{"neq", ds.Neq(ds.Signal("tab"), ds.Str("")), `$tab !== ''`},
+		{"not operand", ds.Not(open), `!$menu.open`},
+		{"not expression", ds.Not(ds.Eq("$a", "1")), `!($a === 1)`},
+		{"toggle", ds.Set(open, ds.Not(open)), `$menu.open = !$menu.open`},
+		{"and", ds.And(ds.Signal("a"), ds.Not(ds.Signal("b")), ds.Eq("$c", "1")), `$a && !$b && ($c === 1)`},
+		{"or keeps groups", ds.Or("($a || $b)", "$c"), `($a || $b) || $c`},
+		{"or splits siblings", ds.Or("($a) || ($b)", "$c"), `(($a) || ($b)) || $c`},
+		{"or quoted", ds.Or(ds.Str("a'b"), "'a' + 'b'"), `'a\'b' || ('a' + 'b')`},
+		{"cond", ds.Cond("evt.target.checked", ds.Str("dark"), ds.Str("light")), `evt.target.checked ? 'dark' : 'light'`},
+		{"cond groups", ds.Cond(ds.Eq("$a", "1"), "$b + 1", ds.Num(0)), `($a === 1) ? ($b + 1) : 0`},
+		{"seq", ds.Seq(ds.Set("$a", ds.Num(1)), ds.Raw(ds.Get("/x"))), `$a = 1; @get('/x')`},
+	}
+	for _, tt := range tests {
+		if string(tt.got) != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestAttributesAcceptExpr(t *testing.T) {
+	attrs := ds.OnClick(ds.Set(ds.Signal("tab"), ds.Str("it's")))
+	assertAttr(t, attrs, "data-on:click", `$tab = 'it\'s'`)
+	attrs = ds.Show(ds.Eq(ds.Signal("tab"), ds.Str("a")))
+	assertAttr(t, attrs, "data-show", `$tab === 'a'`)
+}
+
+func TestActionEncodesURL(t *testing.T) {
+	assertString(t, ds.Get("/search?q=it's&sort=$x"), `@get('/search?q=it\'s&sort=\u0024x')`)
+	assertString(t, ds.Get("/upload", ds.WithContentType(ds.ContentTypeForm), ds.WithSelector("form[name='a']")),
+		`@get('/upload', {contentType: 'form', selector: 'form[name=\'a\']'})`)
+}
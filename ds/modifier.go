@@ -0,0 +1,316 @@
+package ds
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Modifiers change how a Datastar attribute behaves. They are appended to
+// the attribute key, as in data-on:input__debounce.500ms, and helpers take
+// them as trailing arguments:
+//
+//	ds.On("input", expr, ds.Debounce(300*time.Millisecond))
+//	ds.OnClick(expr, ds.Prevent, ds.Once)
+//
+// Each plugin accepts its own modifier interface, and each modifier type
+// implements the interfaces of the plugins that support it, so a modifier
+// on the wrong attribute, such as ds.Half on data-on:click, does not
+// compile.
+
+// modifier is implemented by every modifier type.
+type modifier interface{ suffix() string }
+
+// EventModifier is a modifier of data-on:* (On, OnClick): Once, Passive,
+// Capture, Prevent, Stop, Window, Outside, ViewTransition, Debounce,
+// Throttle, Delay and Case.
+type EventModifier interface {
+	modifier
+	eventModifier()
+}
+
+// IntersectModifier is a modifier of data-on-intersect: Once, Half, Full,
+// Exit, ViewTransition, Debounce, Throttle and Delay.
+type IntersectModifier interface {
+	modifier
+	intersectModifier()
+}
+
+// IntervalModifier is a modifier of data-on-interval: Duration and
+// ViewTransition.
+type IntervalModifier interface {
+	modifier
+	intervalModifier()
+}
+
+// SignalPatchModifier is a modifier of data-on-signal-patch: Debounce,
+// Throttle and Delay.
+type SignalPatchModifier interface {
+	modifier
+	signalPatchModifier()
+}
+
+// InitModifier is a modifier of data-init: Delay and ViewTransition.
+type InitModifier interface {
+	modifier
+	initModifier()
+}
+
+// SignalsModifier is a modifier of data-signals: Case and IfMissing.
+type SignalsModifier interface {
+	modifier
+	signalsModifier()
+}
+
+// EventFlag is a modifier that only applies to data-on:*.
+type EventFlag string
+
+// Event modifiers for data-on:*.
+const (
+	// Passive registers a passive listener; preventDefault has no effect.
+	Passive EventFlag = "__passive"
+	// Capture listens in the capture phase.
+	Capture EventFlag = "__capture"
+	// Prevent calls preventDefault on the event.
+	Prevent EventFlag = "__prevent"
+	// Stop calls stopPropagation on the event.
+	Stop EventFlag = "__stop"
+	// Window listens on the window instead of the element.
+	Window EventFlag = "__window"
+	// Outside fires for events outside the element, such as a click that
+	// should close a menu.
+	Outside EventFlag = "__outside"
+)
+
+// OnceFlag is the type of Once.
+type OnceFlag string
+
+// Once removes the listener after the first event. It applies to
+// data-on:* and data-on-intersect.
+const Once OnceFlag = "__once"
+
+// TransitionFlag is the type of ViewTransition.
+type TransitionFlag string
+
+// ViewTransition wraps the expression in a view transition. It applies to
+// data-on:*, data-init, data-on-intersect and data-on-interval.
+const ViewTransition TransitionFlag = "__viewtransition"
+
+// IntersectFlag is a modifier that only applies to data-on-intersect.
+type IntersectFlag string
+
+// Modifiers for data-on-intersect.
+const (
+	// Half fires when half of the element is visible.
+	Half IntersectFlag = "__half"
+	// Full fires when the whole element is visible.
+	Full IntersectFlag = "__full"
+	// Exit fires when the element leaves the viewport instead of entering it.
+	Exit IntersectFlag = "__exit"
+)
+
+// SignalsFlag is a modifier that only applies to data-signals.
+type SignalsFlag string
+
+// IfMissing makes data-signals set only signals that do not exist yet.
+const IfMissing SignalsFlag = "__ifmissing"
+
+// IgnoreFlag is a modifier of data-ignore.
+type IgnoreFlag string
+
+// Self makes data-ignore skip only the element, not its descendants.
+const Self IgnoreFlag = "__self"
+
+// JSONSignalsFlag is a modifier of data-json-signals.
+type JSONSignalsFlag string
+
+// Terse makes data-json-signals print compact JSON.
+const Terse JSONSignalsFlag = "__terse"
+
+// TimingTag adjusts Debounce, Throttle and Duration.
+type TimingTag string
+
+const (
+	// Leading fires on the leading edge as well: the first event of a
+	// debounce, or immediately when an interval starts.
+	Leading TimingTag = "leading"
+	// NoLeading skips the leading edge of a throttle.
+	NoLeading TimingTag = "noleading"
+	// Trailing fires on the trailing edge of a throttle as well.
+	Trailing TimingTag = "trailing"
+	// NoTrailing skips the trailing edge of a debounce.
+	NoTrailing TimingTag = "notrailing"
+)
+
+// CaseStyle is the casing Case converts a key to.
+type CaseStyle string
+
+const (
+	CaseCamel  CaseStyle = "camel"
+	CaseKebab  CaseStyle = "kebab"
+	CaseSnake  CaseStyle = "snake"
+	CasePascal CaseStyle = "pascal"
+)
+
+// Timing is a modifier made by Debounce or Throttle. It applies to
+// data-on:*, data-on-intersect and data-on-signal-patch.
+type Timing string
+
+// Wait is the modifier made by Delay. It applies where Timing does and to
+// data-init.
+type Wait string
+
+// Period is the modifier made by Duration, for data-on-interval.
+type Period string
+
+// Casing is the modifier made by Case. It applies to data-on:*,
+// data-signals and the attributes named after a key: data-bind,
+// data-class, data-computed, data-indicator and data-ref.
+type Casing string
+
+// Debounce waits until events stop for d before running the expression.
+//
+//	ds.Debounce(500*time.Millisecond)          // → __debounce.500ms
+//	ds.Debounce(time.Second, ds.Leading)       // → __debounce.1000ms.leading
+func Debounce(d time.Duration, tags ...TimingTag) Timing {
+	return Timing(timed("debounce", d, tags))
+}
+
+// Throttle runs the expression at most once every d.
+func Throttle(d time.Duration, tags ...TimingTag) Timing {
+	return Timing(timed("throttle", d, tags))
+}
+
+// Delay runs the expression d after the event. It also applies to
+// data-init and data-on-signal-patch.
+func Delay(d time.Duration) Wait {
+	return Wait(timed("delay", d, nil))
+}
+
+// Duration sets the period of data-on-interval. Datastar defaults to one
+// second. Leading also runs the expression when the interval starts.
+func Duration(d time.Duration, tags ...TimingTag) Period {
+	return Period(timed("duration", d, tags))
+}
+
+// Case converts the key to style, such as an event or signal name written
+// in kebab-case in HTML but expected in camelCase.
+func Case(style CaseStyle) Casing {
+	return Casing("__case." + string(style))
+}
+
+func timed(name string, d time.Duration, tags []TimingTag) string {
+	var b strings.Builder
+	b.WriteString("__" + name + "." + strconv.FormatInt(d.Milliseconds(), 10) + "ms")
+	for _, t := range tags {
+		b.WriteString("." + string(t))
+	}
+	return b.String()
+}
+
+func (m EventFlag) suffix() string       { return string(m) }
+func (m OnceFlag) suffix() string        { return string(m) }
+func (m TransitionFlag) suffix() string  { return string(m) }
+func (m IntersectFlag) suffix() string   { return string(m) }
+func (m SignalsFlag) suffix() string     { return string(m) }
+func (m IgnoreFlag) suffix() string      { return string(m) }
+func (m JSONSignalsFlag) suffix() string { return string(m) }
+func (m Timing) suffix() string          { return string(m) }
+func (m Wait) suffix() string            { return string(m) }
+func (m Period) suffix() string          { return string(m) }
+func (m Casing) suffix() string          { return string(m) }
+
+func (EventFlag) eventModifier()      {}
+func (OnceFlag) eventModifier()       {}
+func (TransitionFlag) eventModifier() {}
+func (Timing) eventModifier()         {}
+func (Wait) eventModifier()           {}
+func (Casing) eventModifier()         {}
+
+func (OnceFlag) intersectModifier()       {}
+func (TransitionFlag) intersectModifier() {}
+func (IntersectFlag) intersectModifier()  {}
+func (Timing) intersectModifier()         {}
+func (Wait) intersectModifier()           {}
+
+func (TransitionFlag) intervalModifier() {}
+func (Period) intervalModifier()         {}
+
+func (Timing) signalPatchModifier() {}
+func (Wait) signalPatchModifier()   {}
+
+func (TransitionFlag) initModifier() {}
+func (Wait) initModifier()           {}
+
+func (SignalsFlag) signalsModifier() {}
+func (Casing) signalsModifier()      {}
+
+// withModifiers appends mods to an attribute key.
+func withModifiers[M modifier](key string, mods []M) string {
+	for _, m := range mods {
+		key += m.suffix()
+	}
+	return key
+}
+
+// Filter selects signals by path with regular expressions, for
+// data-json-signals, data-on-signal-patch-filter and the filterSignals
+// option of backend actions. Patterns use JavaScript regex syntax; empty
+// patterns are left out.
+//
+//	ds.Filter{Include: `^user\.`, Exclude: `password`} // → {include: /^user\./, exclude: /password/}
+type Filter struct {
+	Include string
+	Exclude string
+}
+
+// String returns the filter as a JS object literal, or "" for the zero
+// Filter.
+func (f Filter) String() string {
+	var parts []string
+	if f.Include != "" {
+		parts = append(parts, "include: "+regexLiteral(f.Include))
+	}
+	if f.Exclude != "" {
+		parts = append(parts, "exclude: "+regexLiteral(f.Exclude))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// regexLiteral wraps pattern in slashes, escaping unescaped slashes and
+// line breaks that would end the literal early.
+func regexLiteral(pattern string) string {
+	var b strings.Builder
+	b.WriteByte('/')
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case r == '\n' || r == '\r':
+			if !escaped {
+				b.WriteByte('\\')
+			}
+			if r == '\n' {
+				b.WriteByte('n')
+			} else {
+				b.WriteByte('r')
+			}
+			escaped = false
+			continue
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '/':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	if escaped {
+		b.WriteByte('\\')
+	}
+	b.WriteByte('/')
+	return b.String()
+}
@@ -0,0 +1,128 @@
+package ds_test
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/plaenen/webx/ds"
+)
+
+func TestModifiers(t *testing.T) {
+	tests := []struct {
+		got  string
+		want string
+	}{
+		{string(ds.Debounce(500 * time.Millisecond)), "__debounce.500ms"},
+		{string(ds.Debounce(time.Second, ds.Leading, ds.NoTrailing)), "__debounce.1000ms.leading.notrailing"},
+		{string(ds.Throttle(250*time.Millisecond, ds.NoLeading, ds.Trailing)), "__throttle.250ms.noleading.trailing"},
+		{string(ds.Delay(2 * time.Second)), "__delay.2000ms"},
+		{string(ds.Duration(5*time.Second, ds.Leading)), "__duration.5000ms.leading"},
+		{string(ds.Case(ds.CaseKebab)), "__case.kebab"},
+	}
+	for _, tt := range tests {
+		assertString(t, tt.got, tt.want)
+	}
+}
+
+func TestOnModifiers(t *testing.T) {
+	assertAttr(t, ds.OnClick("x", ds.Prevent, ds.Stop), "data-on:click__prevent__stop", "x")
+	assertAttr(t, ds.OnClick("x", ds.Outside), "data-on:click__outside", "x")
+	assertAttr(t, ds.On("keydown", "x", ds.Window, ds.Throttle(100*time.Millisecond)), "data-on:keydown__window__throttle.100ms", "x")
+	assertAttr(t, ds.On("scroll", "x", ds.Passive, ds.Capture, ds.Once), "data-on:scroll__passive__capture__once", "x")
+	assertAttr(t, ds.On("my-event", "x", ds.Case(ds.CaseCamel)), "data-on:my-event__case.camel", "x")
+	assertAttr(t, ds.Init("x", ds.Delay(time.Second), ds.ViewTransition), "data-init__delay.1000ms__viewtransition", "x")
+	assertAttr(t, ds.Signals(`{"a": 1}`, ds.IfMissing), "data-signals__ifmissing", `{"a": 1}`)
+	assertKey(t, ds.Bind("first-name", ds.Case(ds.CaseCamel)), "data-bind:first-name__case.camel")
+}
+
+// TestModifierFamilies pins which plugins accept each modifier; passing
+// any other plugin's modifier does not compile.
+func TestModifierFamilies(t *testing.T) {
+	families := map[string]reflect.Type{
+		"event":        reflect.TypeFor[ds.EventModifier](),
+		"intersect":    reflect.TypeFor[ds.IntersectModifier](),
+		"interval":     reflect.TypeFor[ds.IntervalModifier](),
+		"signal-patch": reflect.TypeFor[ds.SignalPatchModifier](),
+		"init":         reflect.TypeFor[ds.InitModifier](),
+		"signals":      reflect.TypeFor[ds.SignalsModifier](),
+	}
+	tests := []struct {
+		name string
+		mod  any
+		want []string
+	}{
+		{"Prevent", ds.Prevent, []string{"event"}},
+		{"Once", ds.Once, []string{"event", "intersect"}},
+		{"ViewTransition", ds.ViewTransition, []string{"event", "init", "intersect", "interval"}},
+		{"Half", ds.Half, []string{"intersect"}},
+		{"Debounce", ds.Debounce(time.Second), []string{"event", "intersect", "signal-patch"}},
+		{"Delay", ds.Delay(time.Second), []string{"event", "init", "intersect", "signal-patch"}},
+		{"Duration", ds.Duration(time.Second), []string{"interval"}},
+		{"Case", ds.Case(ds.CaseCamel), []string{"event", "signals"}},
+		{"IfMissing", ds.IfMissing, []string{"signals"}},
+		{"Session", ds.Session, nil},
+		{"SkipEmpty", ds.SkipEmpty, nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for name, iface := range families {
+			if reflect.TypeOf(tt.mod).Implements(iface) {
+				got = append(got, name)
+			}
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s applies to %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// Hyphenated plugin names are whole names, not parameters.
+func TestHyphenatedPlugins(t *testing.T) {
+	tests := []struct {
+		name  string
+		attrs templ.Attributes
+		key   string
+		value string
+	}{
+		{"OnIntersect", ds.OnIntersect("@get('/more')", ds.Once, ds.Half), "data-on-intersect__once__half", "@get('/more')"},
+		{"OnInterval", ds.OnInterval("$tick++", ds.Duration(time.Second)), "data-on-interval__duration.1000ms", "$tick++"},
+		{"OnSignalPatch", ds.OnSignalPatch("console.log(patch)", ds.Debounce(time.Second)), "data-on-signal-patch__debounce.1000ms", "console.log(patch)"},
+		{"OnSignalPatchFilter", ds.OnSignalPatchFilter(ds.Filter{Include: "^form\\."}), "data-on-signal-patch-filter", "{include: /^form\\./}"},
+		{"Ignore", ds.Ignore(), "data-ignore", ""},
+		{"Ignore self", ds.Ignore(ds.Self), "data-ignore__self", ""},
+		{"IgnoreMorph", ds.IgnoreMorph(), "data-ignore-morph", ""},
+		{"PreserveAttr", ds.PreserveAttr("open", "class"), "data-preserve-attr", "open class"},
+		{"JSONSignals", ds.JSONSignals(ds.Filter{}), "data-json-signals", ""},
+		{"JSONSignals filtered", ds.JSONSignals(ds.Filter{Exclude: "password"}, ds.Terse), "data-json-signals__terse", "{exclude: /password/}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.attrs) != 1 {
+				t.Fatalf("attrs = %v, want one key", tt.attrs)
+			}
+			assertAttr(t, tt.attrs, tt.key, tt.value)
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		f    ds.Filter
+		want string
+	}{
+		{ds.Filter{}, ""},
+		{ds.Filter{Include: "^user"}, "{include: /^user/}"},
+		{ds.Filter{Include: "a", Exclude: "b$"}, "{include: /a/, exclude: /b$/}"},
+		{ds.Filter{Include: "a/b"}, `{include: /a\/b/}`},
+		{ds.Filter{Include: `a\/b`}, `{include: /a\/b/}`},
+		{ds.Filter{Include: "a\nb"}, `{include: /a\nb/}`},
+		{ds.Filter{Include: `a\`}, `{include: /a\\/}`},
+	}
+	for _, tt := range tests {
+		assertString(t, tt.f.String(), tt.want)
+	}
+}
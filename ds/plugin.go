@@ -0,0 +1,125 @@
+package ds
+
+import (
+	"slices"
+	"strings"
+)
+
+// PluginValue says what a plugin's attribute value holds.
+type PluginValue int
+
+const (
+	ValueExpr    PluginValue = iota // a JS expression
+	ValueSignal                     // a signal name, as in data-bind="query"
+	ValueSignals                    // an object literal of signals
+	ValueOther                      // a filter, attribute names or nothing
+)
+
+// Plugin describes a Datastar attribute plugin, for tools that read
+// data-* attributes rather than build them, such as dslint.
+type Plugin struct {
+	// Name is the plugin name, as in data-<Name>.
+	Name string
+	// Keyed plugins take a key after a colon, as in data-on:click.
+	// Writing the key after a hyphen is the mistake this package exists
+	// to prevent.
+	Keyed bool
+	// Declares is set for plugins whose key or signal name value creates
+	// a signal.
+	Declares bool
+	// Pro plugins need the Datastar Pro bundle.
+	Pro   bool
+	Value PluginValue
+	// Modifiers are the names of the modifiers the plugin accepts, without
+	// the "__" prefix and arguments: "debounce" for __debounce.500ms.
+	Modifiers []string
+}
+
+var (
+	timingMods = []string{"debounce", "throttle", "delay"}
+	caseMods   = []string{"case"}
+)
+
+// plugins are the Datastar attribute plugins, open-source and Pro. The
+// modifiers match the interfaces in modifier.go.
+var plugins = []Plugin{
+	{Name: "attr", Keyed: true},
+	{Name: "bind", Keyed: true, Declares: true, Value: ValueSignal, Modifiers: caseMods},
+	{Name: "class", Keyed: true, Modifiers: caseMods},
+	{Name: "computed", Keyed: true, Declares: true, Modifiers: caseMods},
+	{Name: "effect"},
+	{Name: "ignore", Value: ValueOther, Modifiers: []string{"self"}},
+	{Name: "ignore-morph", Value: ValueOther},
+	{Name: "indicator", Keyed: true, Declares: true, Value: ValueSignal, Modifiers: caseMods},
+	{Name: "init", Modifiers: []string{"delay", "viewtransition"}},
+	{Name: "json-signals", Value: ValueOther, Modifiers: []string{"terse"}},
+	{Name: "on", Keyed: true, Modifiers: append([]string{
+		"once", "passive", "capture", "prevent", "stop", "window", "outside", "viewtransition", "case",
+	}, timingMods...)},
+	{Name: "on-intersect", Modifiers: append([]string{"once", "half", "full", "exit", "viewtransition"}, timingMods...)},
+	{Name: "on-interval", Modifiers: []string{"duration", "viewtransition"}},
+	{Name: "on-signal-patch", Modifiers: timingMods},
+	{Name: "on-signal-patch-filter", Value: ValueOther},
+	{Name: "preserve-attr", Value: ValueOther},
+	{Name: "ref", Keyed: true, Declares: true, Value: ValueSignal, Modifiers: caseMods},
+	{Name: "show"},
+	{Name: "signals", Keyed: true, Declares: true, Value: ValueSignals, Modifiers: []string{"case", "ifmissing"}},
+	{Name: "style", Keyed: true},
+	{Name: "text"},
+
+	{Name: "animate", Pro: true},
+	{Name: "custom-validity", Pro: true},
+	{Name: "on-raf", Pro: true},
+	{Name: "on-resize", Pro: true},
+	{Name: "persist", Pro: true, Keyed: true, Value: ValueOther, Modifiers: []string{"session"}},
+	{Name: "query-string", Pro: true, Value: ValueOther, Modifiers: []string{"filter", "history"}},
+	{Name: "replace-url", Pro: true},
+	{Name: "scroll-into-view", Pro: true, Value: ValueOther},
+	{Name: "view-transition", Pro: true},
+}
+
+// Plugins returns the Datastar attribute plugins, open-source and Pro,
+// sorted by name.
+func Plugins() []Plugin {
+	out := slices.Clone(plugins)
+	slices.SortFunc(out, func(a, b Plugin) int { return strings.Compare(a.Name, b.Name) })
+	return out
+}
+
+// LookupPlugin returns the plugin called name.
+func LookupPlugin(name string) (Plugin, bool) {
+	i := slices.IndexFunc(plugins, func(p Plugin) bool { return p.Name == name })
+	if i < 0 {
+		return Plugin{}, false
+	}
+	return plugins[i], true
+}
+
+// AttrName is a data-* attribute name split into its parts.
+//
+//	data-on:input__debounce.500ms → {Plugin: "on", Key: "input", HasKey: true, Modifiers: ["debounce.500ms"]}
+type AttrName struct {
+	Plugin string
+	Key    string
+	HasKey bool
+	// Modifiers are the modifiers with their arguments, without the "__"
+	// prefix.
+	Modifiers []string
+}
+
+// ParseAttrName splits a data-* attribute name, lowercased as HTML does. It
+// reports false when name does not start with "data-"; it does not check
+// that the plugin exists.
+func ParseAttrName(name string) (AttrName, bool) {
+	rest, ok := strings.CutPrefix(strings.ToLower(name), "data-")
+	if !ok {
+		return AttrName{}, false
+	}
+	var a AttrName
+	rest, mods, hasMods := strings.Cut(rest, "__")
+	a.Plugin, a.Key, a.HasKey = strings.Cut(rest, ":")
+	if hasMods {
+		a.Modifiers = strings.Split(mods, "__")
+	}
+	return a, true
+}
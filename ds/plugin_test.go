@@ -0,0 +1,92 @@
+package ds_test
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/plaenen/webx/ds"
+)
+
+func TestParseAttrName(t *testing.T) {
+	tests := []struct {
+		name string
+		want ds.AttrName
+		ok   bool
+	}{
+		{"data-show", ds.AttrName{Plugin: "show"}, true},
+		{"data-on:click", ds.AttrName{Plugin: "on", Key: "click", HasKey: true}, true},
+		{"data-on:Input__debounce.500ms__once", ds.AttrName{Plugin: "on", Key: "input", HasKey: true, Modifiers: []string{"debounce.500ms", "once"}}, true},
+		{"data-signals__ifmissing", ds.AttrName{Plugin: "signals", Modifiers: []string{"ifmissing"}}, true},
+		{"aria-label", ds.AttrName{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ds.ParseAttrName(tt.name)
+			if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseAttrName(%q) = %+v, %v; want %+v, %v", tt.name, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+// TestPlugins_CoverHelpers checks that every attribute the helpers build is
+// in the plugin table with modifiers it lists, so tools reading the table
+// agree with the package.
+func TestPlugins_CoverHelpers(t *testing.T) {
+	attrs := []templ.Attributes{
+		ds.On("input", "x", ds.Once, ds.Passive, ds.Capture, ds.Prevent, ds.Stop, ds.Window, ds.Outside,
+			ds.ViewTransition, ds.Debounce(time.Second), ds.Throttle(time.Second), ds.Delay(time.Second), ds.Case(ds.CaseKebab)),
+		ds.Bind("q", ds.Case(ds.CaseCamel)),
+		ds.ClassToggle("hidden", "x", ds.Case(ds.CaseCamel)),
+		ds.Class("{}", ds.Case(ds.CaseCamel)),
+		ds.Attr("href", "x"),
+		ds.Style("color", "x"),
+		ds.Computed("total", "x", ds.Case(ds.CaseCamel)),
+		ds.Indicator("busy", ds.Case(ds.CaseCamel)),
+		ds.Ref("input", ds.Case(ds.CaseCamel)),
+		ds.Signals("{}", ds.IfMissing, ds.Case(ds.CaseCamel)),
+		ds.Show("x"),
+		ds.Text("x"),
+		ds.Init("x", ds.Delay(time.Second), ds.ViewTransition),
+		ds.Effect("x"),
+		ds.OnIntersect("x", ds.Once, ds.Half, ds.Full, ds.Exit, ds.ViewTransition, ds.Debounce(time.Second)),
+		ds.OnInterval("x", ds.Duration(time.Second), ds.ViewTransition),
+		ds.OnSignalPatch("x", ds.Throttle(time.Second)),
+		ds.OnSignalPatchFilter(ds.Filter{Include: "a"}),
+		ds.Ignore(ds.Self),
+		ds.IgnoreMorph(),
+		ds.PreserveAttr("open"),
+		ds.JSONSignals(ds.Filter{}, ds.Terse),
+		ds.PersistKey("prefs", ds.Filter{}, ds.Session),
+		ds.QueryString(ds.Filter{}, ds.SkipEmpty, ds.History),
+		ds.ReplaceURL("x"),
+		ds.ViewTransitionName("x"),
+	}
+	for _, attr := range attrs {
+		for name := range attr {
+			a, ok := ds.ParseAttrName(name)
+			if !ok {
+				t.Errorf("%s: not a data-* attribute", name)
+				continue
+			}
+			p, ok := ds.LookupPlugin(a.Plugin)
+			if !ok {
+				t.Errorf("%s: plugin %q is not in the table", name, a.Plugin)
+				continue
+			}
+			if a.HasKey && !p.Keyed {
+				t.Errorf("%s: plugin %q is not keyed in the table", name, a.Plugin)
+			}
+			for _, m := range a.Modifiers {
+				m, _, _ = strings.Cut(m, ".")
+				if !slices.Contains(p.Modifiers, m) {
+					t.Errorf("%s: modifier %q is not listed for %q", name, m, a.Plugin)
+				}
+			}
+		}
+	}
+}
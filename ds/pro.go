@@ -0,0 +1,64 @@
+package ds
+
+import "github.com/a-h/templ"
+
+// --- Datastar Pro attributes ---
+//
+// These attributes need the commercial Datastar Pro bundle; the
+// open-source bundle ignores them. Components wrap them in
+// WebXContext.IfPro so they are only rendered when the page loads Pro.
+
+// PersistFlag is a modifier of data-persist.
+type PersistFlag string
+
+// Session makes data-persist use sessionStorage instead of localStorage.
+const Session PersistFlag = "__session"
+
+// QueryStringFlag is a modifier of data-query-string.
+type QueryStringFlag string
+
+const (
+	// SkipEmpty makes data-query-string leave out empty values.
+	SkipEmpty QueryStringFlag = "__filter"
+	// History makes data-query-string push a history entry for each change
+	// instead of replacing the current one.
+	History QueryStringFlag = "__history"
+)
+
+func (m PersistFlag) suffix() string     { return string(m) }
+func (m QueryStringFlag) suffix() string { return string(m) }
+
+// Persist returns a data-persist attribute, which saves the signals f
+// selects in localStorage and restores them on load. The zero Filter
+// persists every signal.
+func Persist(f Filter, mods ...PersistFlag) templ.Attributes {
+	return templ.Attributes{withModifiers("data-persist", mods): f.String()}
+}
+
+// PersistKey is Persist under a storage key other than Datastar's default,
+// so separate widgets do not overwrite each other.
+func PersistKey(key string, f Filter, mods ...PersistFlag) templ.Attributes {
+	return templ.Attributes{withModifiers("data-persist:"+key, mods): f.String()}
+}
+
+// QueryString returns a data-query-string attribute, which keeps the
+// signals f selects in sync with the URL query string, so the state
+// survives reloads and can be shared as a link.
+func QueryString(f Filter, mods ...QueryStringFlag) templ.Attributes {
+	return templ.Attributes{withModifiers("data-query-string", mods): f.String()}
+}
+
+// ReplaceURL returns a data-replace-url attribute, which replaces the
+// browser URL with the value of expr without navigating.
+//
+//	ds.ReplaceURL("`/orders?page=${$orders.page}`")
+func ReplaceURL[E Expression](expr E) templ.Attributes {
+	return templ.Attributes{"data-replace-url": string(expr)}
+}
+
+// ViewTransitionName returns a data-view-transition attribute, which sets
+// the element's view-transition-name so it animates between states in
+// updates made with the ViewTransition modifier.
+func ViewTransitionName[E Expression](expr E) templ.Attributes {
+	return templ.Attributes{"data-view-transition": string(expr)}
+}
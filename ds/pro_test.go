@@ -0,0 +1,17 @@
+package ds_test
+
+import (
+	"testing"
+
+	"github.com/plaenen/webx/ds"
+)
+
+func TestProAttributes(t *testing.T) {
+	f := ds.Filter{Include: `^theme\.`}
+	assertAttr(t, ds.Persist(ds.Filter{}), "data-persist", "")
+	assertAttr(t, ds.Persist(f, ds.Session), "data-persist__session", `{include: /^theme\./}`)
+	assertAttr(t, ds.PersistKey("prefs", f), "data-persist:prefs", `{include: /^theme\./}`)
+	assertAttr(t, ds.QueryString(f, ds.SkipEmpty, ds.History), "data-query-string__filter__history", `{include: /^theme\./}`)
+	assertAttr(t, ds.ReplaceURL("`/orders?page=${$page}`"), "data-replace-url", "`/orders?page=${$page}`")
+	assertAttr(t, ds.ViewTransitionName(ds.Str("card")), "data-view-transition", `'card'`)
+}
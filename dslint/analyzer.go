@@ -0,0 +1,49 @@
+package dslint
+
+import (
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Analyzer runs the checker on the generated _templ.go files of a package,
+// for use with go/analysis drivers such as singlechecker, multichecker or
+// gopls. Undeclared signals are matched within the package.
+var Analyzer = &analysis.Analyzer{
+	Name: "dslint",
+	Doc:  "report Datastar attribute mistakes in generated templ components",
+	URL:  "https://pkg.go.dev/github.com/plaenen/webx/dslint",
+	Run:  run,
+}
+
+var flagAllow, flagSignals, flagDisable string
+
+func init() {
+	Analyzer.Flags.StringVar(&flagAllow, "allow", "", "comma-separated data-* attributes that are not Datastar plugins")
+	Analyzer.Flags.StringVar(&flagSignals, "signals", "", "comma-separated signal namespaces declared outside the package")
+	Analyzer.Flags.StringVar(&flagDisable, "disable", "", "comma-separated rules to skip")
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	c := NewChecker(pass.Fset, Options{
+		Allow:   split(flagAllow),
+		Signals: split(flagSignals),
+		Disable: split(flagDisable),
+	})
+	for _, f := range pass.Files {
+		if isGenerated(pass.Fset.File(f.Pos()).Name()) {
+			c.AddGoFile(f)
+		}
+	}
+	for _, d := range c.Diagnostics() {
+		pass.Report(analysis.Diagnostic{Pos: d.Pos, Category: d.Rule, Message: d.Message})
+	}
+	return nil, nil
+}
+
+func split(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
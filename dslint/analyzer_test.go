@@ -0,0 +1,12 @@
+package dslint_test
+
+import (
+	"testing"
+
+	"github.com/plaenen/webx/dslint"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), dslint.Analyzer, "page")
+}
@@ -0,0 +1,225 @@
+// Package dslint finds Datastar attribute mistakes in templ components that
+// the browser silently ignores:
+//
+//   - hyphenated-plugin: data-on-click instead of data-on:click
+//   - unknown-plugin: data-* attributes written in Datastar syntax (with a
+//     key or modifiers) or one typo away from a plugin, such as data-signal
+//   - undeclared-signal: $name references to namespaces that nothing on the
+//     checked pages declares, through utils.Signals, a component ID,
+//     data-signals, data-bind and the like
+//   - missing-id: actions to a literal URL without ?id= in templates whose
+//     signals are namespaced by a component ID the handler cannot otherwise
+//     know (see webx.SignalHandler)
+//
+// It checks .templ sources and generated _templ.go files. Analyzer runs it
+// under go/analysis drivers; cmd/dslint runs it over a source tree.
+package dslint
+
+import (
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Rules reported by the checker.
+const (
+	RuleHyphenatedPlugin = "hyphenated-plugin"
+	RuleUnknownPlugin    = "unknown-plugin"
+	RuleUndeclaredSignal = "undeclared-signal"
+	RuleMissingID        = "missing-id"
+)
+
+// Diagnostic is one problem found by the checker.
+type Diagnostic struct {
+	Pos      token.Pos      `json:"-"`
+	Position token.Position `json:"position"`
+	Rule     string         `json:"rule"`
+	Message  string         `json:"message"`
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s (%s)", d.Position, d.Message, d.Rule)
+}
+
+// Options configures a Checker.
+type Options struct {
+	// Allow lists data-* attributes that are not Datastar plugins but would
+	// otherwise be reported, such as a data-tooltip:placement of another
+	// library.
+	Allow []string
+	// Signals lists signal namespaces declared outside the checked files,
+	// such as ones a handler creates with PatchSignals.
+	Signals []string
+	// Disable lists rules to skip.
+	Disable []string
+}
+
+func resolveOptions(opts []Options) Options {
+	var o Options
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	return o
+}
+
+// Checker collects files and reports their problems. Signal declarations are
+// matched against references across every file added, because signals are
+// shared by the whole page; call Diagnostics after adding all of them.
+type Checker struct {
+	fset     *token.FileSet
+	opts     Options
+	diags    []Diagnostic
+	declared map[string]bool
+	refs     []signalRef
+	scope    *scope // template or function being checked
+}
+
+type signalRef struct {
+	name string
+	pos  token.Pos
+}
+
+// NewChecker returns a Checker that records positions in fset.
+func NewChecker(fset *token.FileSet, opts ...Options) *Checker {
+	o := resolveOptions(opts)
+	c := &Checker{fset: fset, opts: o, declared: map[string]bool{}}
+	for _, s := range o.Signals {
+		c.declared[s] = true
+	}
+	return c
+}
+
+// AddGoFile checks f, which must have been parsed with the Checker's
+// FileSet. HTML is only looked for in the output of generated templates.
+func (c *Checker) AddGoFile(f *ast.File) {
+	s := &goScanner{c: c, imports: imports(f), pos: func(p token.Pos) token.Pos { return p }}
+	for _, decl := range f.Decls {
+		c.enter()
+		s.scan(decl)
+		c.leave()
+	}
+}
+
+// Diagnostics returns the problems found in the files added so far, in
+// file order.
+func (c *Checker) Diagnostics() []Diagnostic {
+	diags := slices.Clone(c.diags)
+	for _, r := range c.refs {
+		if !c.declared[r.name] {
+			diags = append(diags, c.diagnostic(r.pos, RuleUndeclaredSignal,
+				"$%s is not declared by utils.Signals, a component ID or a data-signals, data-bind, data-computed, data-ref or data-indicator attribute", r.name))
+		}
+	}
+	diags = slices.DeleteFunc(diags, func(d Diagnostic) bool { return slices.Contains(c.opts.Disable, d.Rule) })
+	slices.SortStableFunc(diags, func(a, b Diagnostic) int {
+		return cmp.Or(
+			cmp.Compare(a.Position.Filename, b.Position.Filename),
+			cmp.Compare(a.Position.Offset, b.Position.Offset),
+			cmp.Compare(a.Rule, b.Rule),
+		)
+	})
+	return slices.CompactFunc(diags, func(a, b Diagnostic) bool {
+		return a.Position == b.Position && a.Rule == b.Rule && a.Message == b.Message
+	})
+}
+
+func (c *Checker) diagnostic(pos token.Pos, rule, format string, args ...any) Diagnostic {
+	return Diagnostic{Pos: pos, Position: c.fset.Position(pos), Rule: rule, Message: fmt.Sprintf(format, args...)}
+}
+
+func (c *Checker) report(pos token.Pos, rule, format string, args ...any) {
+	c.diags = append(c.diags, c.diagnostic(pos, rule, format, args...))
+}
+
+func (c *Checker) declare(name string) {
+	if name != "" {
+		c.declared[name] = true
+	}
+}
+
+func (c *Checker) reference(name string, pos token.Pos) {
+	c.refs = append(c.refs, signalRef{name, pos})
+}
+
+// scope is one template or function. Actions are only reported once the
+// whole scope has been seen, since the utils.Signals call that makes them
+// need an ID may come later.
+type scope struct {
+	dynamicID bool
+	actions   []action
+	vars      map[string]ast.Expr // last assignment of each local, for URLs
+}
+
+type action struct {
+	url string
+	pos token.Pos
+}
+
+func (c *Checker) enter() {
+	c.scope = &scope{vars: map[string]ast.Expr{}}
+}
+
+func (c *Checker) leave() {
+	sc := c.scope
+	c.scope = nil
+	if !sc.dynamicID {
+		return
+	}
+	for _, a := range sc.actions {
+		c.report(a.pos, RuleMissingID,
+			"action URL %q has no id parameter, but this template's signals are namespaced by its component ID; append ?id=<ID> so the handler can find them", a.url)
+	}
+}
+
+// signalID mirrors webx.SignalID.
+func signalID(componentID string) string {
+	return strings.ReplaceAll(componentID, "-", "_")
+}
+
+// AddPath adds a .templ or _templ.go file, or every one under a directory.
+// A trailing "/..." is accepted, as in Go package patterns. Generated files
+// are skipped when their .templ source is present, since the source gives
+// better positions.
+func (c *Checker) AddPath(root string) error {
+	root = strings.TrimSuffix(root, "...")
+	if root == "" {
+		root = "."
+	}
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != root && (strings.HasPrefix(name, ".") || name == "testdata" || name == "vendor" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		switch {
+		case strings.HasSuffix(path, ".templ"):
+			src, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			return c.AddTemplFile(path, src)
+		case isGenerated(path):
+			if _, err := os.Stat(strings.TrimSuffix(path, "_templ.go") + ".templ"); err == nil {
+				return nil
+			}
+			f, err := parser.ParseFile(c.fset, path, nil, parser.SkipObjectResolution)
+			if err != nil {
+				return err
+			}
+			c.AddGoFile(f)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,204 @@
+package dslint_test
+
+import (
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/plaenen/webx/dslint"
+)
+
+const header = `package page
+
+import (
+	"fmt"
+
+	"github.com/plaenen/webx/ds"
+	"github.com/plaenen/webx/utils"
+)
+
+`
+
+// check runs the checker on a templ file made of header and body and
+// returns "line:rule" for each diagnostic, with lines counted from body.
+func check(t *testing.T, body string, opts ...dslint.Options) []string {
+	t.Helper()
+	c := dslint.NewChecker(token.NewFileSet(), opts...)
+	if err := c.AddTemplFile("page.templ", []byte(header+body)); err != nil {
+		t.Fatal(err)
+	}
+	offset := strings.Count(header, "\n")
+	var got []string
+	for _, d := range c.Diagnostics() {
+		got = append(got, fmt.Sprintf("%d:%s", d.Position.Line-offset, d.Rule))
+	}
+	return got
+}
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{
+			"hyphenated plugins",
+			`templ T() {
+	<div data-signals="{a: 1}" data-on-click="$a++" data-class-active="$a > 1"></div>
+	<div data-on:click="$a++" data-on-intersect="$a++" data-on-signal-patch="$a++"></div>
+}`,
+			[]string{"2:hyphenated-plugin", "2:hyphenated-plugin"},
+		},
+		{
+			"hyphenated plugin in templ.Attributes",
+			`templ T() {
+	<div { templ.Attributes{"data-on-keydown": "1"}... }></div>
+}`,
+			[]string{"2:hyphenated-plugin"},
+		},
+		{
+			"unknown plugins",
+			`templ T() {
+	<div data-signal="{a: 1}" data-shwo="1" data-tooltip:top="x" data-foo__once="1"></div>
+	<div data-tip="hi" data-theme="dark" data-testid="x" data-content="y"></div>
+}`,
+			[]string{"2:unknown-plugin", "2:unknown-plugin", "2:unknown-plugin", "2:unknown-plugin"},
+		},
+		{
+			"undeclared signals",
+			`templ T() {
+	{{ sigs := utils.Signals("cart", nil) }}
+	<div data-signals={ sigs.DataSignals } data-signals:ui.open="false">
+		<input data-bind:first-name/>
+		<input data-bind="last"/>
+		<span data-ref="el" data-indicator:loading></span>
+		<span data-computed:total="$cart.n * 2"></span>
+		<span data-text="$cart.n + $ui.open + $firstName + $last + $el + $loading + $total"></span>
+		<span data-text="$ghost.n + '$notASignal'"></span>
+		<span { ds.Show("$ghost2")... }></span>
+		<span { ds.Show(ds.Signal("ghost-3", "open"))... }></span>
+		<span data-text={ "$ghost4" }></span>
+	</div>
+}`,
+			[]string{"9:undeclared-signal", "10:undeclared-signal", "11:undeclared-signal", "12:undeclared-signal"},
+		},
+		{
+			"component ID declares signals",
+			`templ T() {
+	@Accordion(Props{ID: "faq-list"})
+	<span data-text="$faq_list.active"></span>
+}`,
+			nil,
+		},
+		{
+			"missing id",
+			`templ T(id string) {
+	{{
+		sigs := utils.Signals(id, nil)
+		ok := fmt.Sprintf("/api/x?id=%s", id)
+		bad := "/api/y"
+	}}
+	<div data-signals={ sigs.DataSignals }>
+		<a { ds.OnClick(ds.Get(ok))... }></a>
+		<a { ds.OnClick(ds.Get("/api/z?id=" + id))... }></a>
+		<a { ds.OnClick(ds.Post(bad))... }></a>
+		<a data-on:click="@get('/api/w')"></a>
+		<a { ds.OnClick(ds.Get(props.URL))... }></a>
+	</div>
+}
+
+templ Static() {
+	<a { ds.OnClick(ds.Get("/api/static"))... }></a>
+}`,
+			[]string{"10:missing-id", "11:missing-id"},
+		},
+		{
+			"helpers outside templates",
+			`func attrs(id string) templ.Attributes {
+	utils.Signals(id, nil)
+	return ds.OnClick(ds.Post("/api/save"))
+}`,
+			[]string{"3:missing-id"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := check(t, tt.body); !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOptions(t *testing.T) {
+	body := `templ T() {
+	<div data-tooltip:top="x" data-text="$cart.n" data-on-click="1"></div>
+}`
+	got := check(t, body, dslint.Options{
+		Allow:   []string{"data-tooltip:top"},
+		Signals: []string{"cart"},
+		Disable: []string{dslint.RuleHyphenatedPlugin},
+	})
+	if len(got) != 0 {
+		t.Errorf("got %q, want none", got)
+	}
+}
+
+func TestPositions(t *testing.T) {
+	c := dslint.NewChecker(token.NewFileSet())
+	src := header + "// héllo\ntempl T() {\n\t<div data-on-click=\"1\"></div>\n}\n"
+	if err := c.AddTemplFile("page.templ", []byte(src)); err != nil {
+		t.Fatal(err)
+	}
+	diags := c.Diagnostics()
+	if len(diags) != 1 {
+		t.Fatalf("got %v, want one diagnostic", diags)
+	}
+	want := "page.templ:12:7: data-on-click is ignored by Datastar; keyed plugins use a colon: data-on:click (hyphenated-plugin)"
+	if got := diags[0].String(); got != want {
+		t.Errorf("got %s\nwant %s", got, want)
+	}
+}
+
+func TestAddPathSkipsGeneratedWithSource(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("a.templ", "package page\n\ntempl A() {\n\t<div data-on-click=\"1\"></div>\n}\n")
+	write("a_templ.go", "package page\n\nvar _ = 1 +\n") // not parsed: a.templ is checked instead
+	write("b_templ.go", "package page\n\nimport templruntime \"github.com/a-h/templ/runtime\"\n\nfunc B() { templruntime.WriteString(nil, 1, \"<p data-signal=\\\"{}\\\">\") }\n")
+
+	c := dslint.NewChecker(token.NewFileSet())
+	if err := c.AddPath(dir + "/..."); err != nil {
+		t.Fatal(err)
+	}
+	var files []string
+	for _, d := range c.Diagnostics() {
+		files = append(files, filepath.Base(d.Position.Filename)+":"+d.Rule)
+	}
+	want := []string{"a.templ:hyphenated-plugin", "b_templ.go:unknown-plugin"}
+	if !slices.Equal(files, want) {
+		t.Errorf("got %q, want %q", files, want)
+	}
+}
+
+// TestRepositoryIsClean keeps the components and showcase free of the
+// mistakes the checker finds.
+func TestRepositoryIsClean(t *testing.T) {
+	c := dslint.NewChecker(token.NewFileSet())
+	for _, dir := range []string{"../ui", "../layouts", "../cmd/showcase"} {
+		if err := c.AddPath(dir + "/..."); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, d := range c.Diagnostics() {
+		t.Error(d)
+	}
+}
@@ -0,0 +1,286 @@
+package dslint
+
+import (
+	"go/ast"
+	"go/token"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Import paths whose functions the checker understands.
+const (
+	dsPath      = "github.com/plaenen/webx/ds"
+	utilsPath   = "github.com/plaenen/webx/utils"
+	webxPath    = "github.com/plaenen/webx"
+	templPath   = "github.com/a-h/templ"
+	runtimePath = "github.com/a-h/templ/runtime"
+)
+
+// dsActions are the ds functions whose first argument is an action URL.
+var dsActions = map[string]bool{
+	"Get": true, "GetOnce": true, "Post": true, "PostOnce": true,
+	"Put": true, "PutOnce": true, "Patch": true, "PatchOnce": true,
+	"Delete": true, "DeleteOnce": true,
+}
+
+// dsDeclaring are the ds functions whose first argument names a signal.
+var dsDeclaring = map[string]bool{"Bind": true, "Computed": true, "Indicator": true, "Ref": true}
+
+// dsLiterals are the ds functions whose string arguments are data, not
+// expressions.
+var dsLiterals = map[string]bool{"Str": true, "JSON": true, "Signal": true, "PreserveAttr": true, "WithHeader": true, "WithSelector": true}
+
+// goScanner checks Go code: whole files, or snippets embedded in a .templ
+// file, whose positions pos maps back to the file.
+type goScanner struct {
+	c       *Checker
+	imports map[string]string // local name → import path
+	pos     func(token.Pos) token.Pos
+}
+
+// imports returns the local names of f's imports.
+func imports(f *ast.File) map[string]string {
+	m := map[string]string{}
+	for _, spec := range f.Imports {
+		p, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := path.Base(p)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		m[name] = p
+	}
+	return m
+}
+
+func (s *goScanner) scan(n ast.Node) {
+	ast.Inspect(n, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if len(n.Lhs) == len(n.Rhs) {
+				for i, lhs := range n.Lhs {
+					if id, ok := lhs.(*ast.Ident); ok {
+						s.c.scope.vars[id.Name] = n.Rhs[i]
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			if len(n.Names) == len(n.Values) {
+				for i, id := range n.Names {
+					s.c.scope.vars[id.Name] = n.Values[i]
+				}
+			}
+		case *ast.KeyValueExpr:
+			// Components namespace their signals by their ID prop.
+			if id, ok := n.Key.(*ast.Ident); ok && id.Name == "ID" {
+				if v, ok := stringLit(n.Value); ok {
+					s.c.declare(signalID(v))
+				}
+			}
+		case *ast.CompositeLit:
+			if s.is(n.Type, templPath, "Attributes") {
+				s.attributes(n)
+			}
+		case *ast.CallExpr:
+			s.call(n)
+		}
+		return true
+	})
+}
+
+// is reports whether expr is the selector pkg.name.
+func (s *goScanner) is(expr ast.Expr, pkg, name string) bool {
+	p, n := s.selector(expr)
+	return p == pkg && n == name
+}
+
+// selector returns the import path and name of a pkg.Name expression.
+func (s *goScanner) selector(expr ast.Expr) (pkg, name string) {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return "", ""
+	}
+	x, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return "", ""
+	}
+	return s.imports[x.Name], sel.Sel.Name
+}
+
+func (s *goScanner) call(call *ast.CallExpr) {
+	fun := call.Fun
+	if idx, ok := fun.(*ast.IndexExpr); ok { // explicit type arguments
+		fun = idx.X
+	}
+	pkg, name := s.selector(fun)
+	switch {
+	case pkg == runtimePath && name == "WriteString" && len(call.Args) == 3:
+		if v, ok := stringLit(call.Args[2]); ok {
+			s.c.scanHTML(v, s.pos(call.Args[2].Pos()))
+		}
+	case pkg == utilsPath && name == "Signals" && len(call.Args) > 0:
+		if v, ok := stringLit(call.Args[0]); ok {
+			s.c.declare(signalID(v))
+		} else {
+			s.c.scope.dynamicID = true
+		}
+	case (pkg == dsPath && dsActions[name]) || (pkg == webxPath && name == "Post"):
+		if len(call.Args) > 0 && s.url(call.Args[0], 0) == urlNoID {
+			v, _ := s.literalURL(call.Args[0], 0)
+			s.c.action(v, s.pos(call.Args[0].Pos()))
+		}
+	case pkg == dsPath && name == "Signal" && len(call.Args) > 0:
+		if v, ok := stringLit(call.Args[0]); ok {
+			s.c.reference(signalID(v), s.pos(call.Args[0].Pos()))
+		}
+	case pkg == dsPath && dsDeclaring[name] && len(call.Args) > 0:
+		if v, ok := stringLit(call.Args[0]); ok {
+			s.c.declare(namespace(v))
+		}
+		s.exprArgs(call.Args[1:])
+	case pkg == dsPath && name == "Signals" && len(call.Args) > 0:
+		if v, ok := stringLit(call.Args[0]); ok {
+			for _, k := range objectKeys(v) {
+				s.c.declare(k)
+			}
+		}
+		s.exprArgs(call.Args)
+	case pkg == dsPath && name == "On" && len(call.Args) > 1:
+		s.exprArgs(call.Args[1:])
+	case pkg == dsPath && (name == "Attr" || name == "Style" || name == "ClassToggle") && len(call.Args) > 1:
+		s.exprArgs(call.Args[1:])
+	case pkg == dsPath && !dsLiterals[name]:
+		s.exprArgs(call.Args)
+	}
+}
+
+// exprArgs scans the string literal arguments of a ds helper as Datastar
+// expressions.
+func (s *goScanner) exprArgs(args []ast.Expr) {
+	for _, arg := range args {
+		if v, ok := stringLit(arg); ok {
+			s.c.scanExpr(v, s.pos(arg.Pos()))
+		}
+	}
+}
+
+// attributes checks a templ.Attributes literal.
+func (s *goScanner) attributes(lit *ast.CompositeLit) {
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := stringLit(kv.Key)
+		if !ok {
+			continue
+		}
+		value, known := stringLit(kv.Value)
+		s.c.checkAttr(key, value, known, s.pos(kv.Key.Pos()))
+	}
+}
+
+// urlState says whether an action URL carries an id parameter.
+type urlState int
+
+const (
+	urlUnknown urlState = iota
+	urlHasID
+	urlNoID
+)
+
+// url works out whether expr, an action URL, carries an id parameter.
+// Anything it cannot follow, such as a prop, is unknown and not reported.
+func (s *goScanner) url(expr ast.Expr, depth int) urlState {
+	if depth > 8 {
+		return urlUnknown
+	}
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		v, ok := stringLit(e)
+		if !ok {
+			return urlUnknown
+		}
+		if idParamRE.MatchString(v) {
+			return urlHasID
+		}
+		return urlNoID
+	case *ast.ParenExpr:
+		return s.url(e.X, depth+1)
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			return urlUnknown
+		}
+		x, y := s.url(e.X, depth+1), s.url(e.Y, depth+1)
+		switch {
+		case x == urlHasID || y == urlHasID:
+			return urlHasID
+		case x == urlNoID && y == urlNoID:
+			return urlNoID
+		}
+		return urlUnknown
+	case *ast.Ident:
+		if v, ok := s.c.scope.vars[e.Name]; ok {
+			return s.url(v, depth+1)
+		}
+	case *ast.CallExpr:
+		if pkg, name := s.selector(e.Fun); pkg == "fmt" && name == "Sprintf" && len(e.Args) > 0 {
+			if v, ok := stringLit(e.Args[0]); ok && idParamRE.MatchString(v) {
+				return urlHasID
+			}
+			return urlUnknown
+		}
+		// Path helpers such as wctx.APIPath("/api/x") keep the query.
+		if len(e.Args) == 1 {
+			return s.url(e.Args[0], depth+1)
+		}
+	}
+	return urlUnknown
+}
+
+// literalURL returns the literal parts of a URL expression, for messages.
+func (s *goScanner) literalURL(expr ast.Expr, depth int) (string, bool) {
+	if depth > 8 {
+		return "", false
+	}
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		return stringLit(e)
+	case *ast.ParenExpr:
+		return s.literalURL(e.X, depth+1)
+	case *ast.BinaryExpr:
+		x, _ := s.literalURL(e.X, depth+1)
+		y, _ := s.literalURL(e.Y, depth+1)
+		return x + y, true
+	case *ast.Ident:
+		if v, ok := s.c.scope.vars[e.Name]; ok {
+			return s.literalURL(v, depth+1)
+		}
+	case *ast.CallExpr:
+		if len(e.Args) == 1 {
+			return s.literalURL(e.Args[0], depth+1)
+		}
+	}
+	return "", false
+}
+
+// stringLit returns the value of a string literal.
+func stringLit(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	v, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", false
+	}
+	return v, true
+}
+
+// isGenerated reports whether name is a file generated by templ.
+func isGenerated(name string) bool {
+	return strings.HasSuffix(name, "_templ.go")
+}
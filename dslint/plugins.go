@@ -0,0 +1,214 @@
+package dslint
+
+import (
+	"go/token"
+	"html"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/plaenen/webx/ds"
+)
+
+// checkAttr checks one attribute. value is only looked at when known is
+// set, since most values are Go expressions.
+func (c *Checker) checkAttr(name, value string, known bool, pos token.Pos) {
+	attr, ok := ds.ParseAttrName(name)
+	if !ok || slices.Contains(c.opts.Allow, name) {
+		return
+	}
+
+	if p, ok := ds.LookupPlugin(attr.Plugin); ok {
+		if attr.HasKey && p.Declares {
+			c.declare(namespace(camel(attr.Key)))
+		}
+		if !known {
+			return
+		}
+		value = html.UnescapeString(value)
+		switch {
+		case p.Value == ds.ValueSignal && !attr.HasKey:
+			c.declare(namespace(strings.TrimSpace(value)))
+		case p.Value == ds.ValueSignals && !attr.HasKey:
+			for _, k := range objectKeys(value) {
+				c.declare(k)
+			}
+			c.scanExpr(value, pos)
+		case p.Value == ds.ValueExpr || p.Value == ds.ValueSignals:
+			c.scanExpr(value, pos)
+		}
+		return
+	}
+
+	if !attr.HasKey {
+		for _, p := range ds.Plugins() {
+			if !p.Keyed {
+				continue
+			}
+			if k, ok := strings.CutPrefix(attr.Plugin, p.Name+"-"); ok {
+				fixed := "data-" + p.Name + ":" + k
+				for _, m := range attr.Modifiers {
+					fixed += "__" + m
+				}
+				c.report(pos, RuleHyphenatedPlugin, "%s is ignored by Datastar; keyed plugins use a colon: %s", name, fixed)
+				// Check the rest as if it were written correctly, so fixing
+				// the name does not uncover more problems.
+				c.checkAttr(fixed, value, known, pos)
+				return
+			}
+		}
+	}
+	if s := suggest(attr.Plugin); s != "" {
+		c.report(pos, RuleUnknownPlugin, "%s is not a Datastar plugin; did you mean data-%s?", name, s)
+	} else if attr.HasKey || len(attr.Modifiers) > 0 {
+		c.report(pos, RuleUnknownPlugin, "%s is not a Datastar plugin", name)
+	}
+}
+
+// suggest returns the plugin plugin is one edit away from, or "". Short
+// names are left alone: data-tip or data-id are too likely to be meant.
+func suggest(plugin string) string {
+	if len(plugin) < 4 {
+		return ""
+	}
+	var best string
+	for _, p := range ds.Plugins() {
+		if editDistance(plugin, p.Name) == 1 && (best == "" || p.Name < best) {
+			best = p.Name
+		}
+	}
+	return best
+}
+
+// editDistance counts the insertions, deletions, substitutions and
+// transpositions of adjacent characters that turn a into b.
+func editDistance(a, b string) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
+
+var (
+	actionRE    = regexp.MustCompile(`@(?:get|post|put|patch|delete)\(\s*(?:'((?:\\.|[^\\'])*)'|"((?:\\.|[^\\"])*)"|` + "`([^`]*)`)")
+	stringRE    = regexp.MustCompile(`'(?:\\.|[^\\'])*'|"(?:\\.|[^\\"])*"`)
+	signalRE    = regexp.MustCompile(`(?:^|[^\w$.])\$([A-Za-z_][A-Za-z0-9_]*)`)
+	idParamRE   = regexp.MustCompile(`[?&]id=`)
+	htmlAttrRE  = regexp.MustCompile(`(?:^|[\s"'])(data-[A-Za-z0-9_:.\-]+)(?:=("[^"]*"?|'[^']*'?))?`)
+	objectKeyRE = regexp.MustCompile(`^\s*(?:([A-Za-z_$][\w$]*)|"((?:\\.|[^\\"])*)"|'((?:\\.|[^\\'])*)')\s*:`)
+)
+
+// scanExpr records the signals and actions in a Datastar expression.
+// Signals inside string literals are not references; template literals
+// are kept, since their ${} parts are code.
+func (c *Checker) scanExpr(expr string, pos token.Pos) {
+	for _, m := range actionRE.FindAllStringSubmatch(expr, -1) {
+		c.action(m[1]+m[2]+m[3], pos)
+	}
+	code := stringRE.ReplaceAllString(expr, "''")
+	for _, m := range signalRE.FindAllStringSubmatch(code, -1) {
+		c.reference(m[1], pos)
+	}
+}
+
+// action records a backend action with a literal URL in the current
+// scope, to be reported if the scope turns out to need an ID.
+func (c *Checker) action(url string, pos token.Pos) {
+	if c.scope != nil && !idParamRE.MatchString(url) {
+		c.scope.actions = append(c.scope.actions, action{url, pos})
+	}
+}
+
+// scanHTML checks the data-* attributes in a fragment of template output.
+// A value cut off by the end of the fragment is filled in at runtime, so
+// it is not looked at.
+func (c *Checker) scanHTML(fragment string, pos token.Pos) {
+	for _, m := range htmlAttrRE.FindAllStringSubmatch(fragment, -1) {
+		value, known := m[2], false
+		if len(value) >= 2 && value[len(value)-1] == value[0] {
+			value, known = value[1:len(value)-1], true
+		}
+		c.checkAttr(m[1], value, known, pos)
+	}
+}
+
+// objectKeys returns the top-level keys of a JS object literal, such as
+// the value of data-signals, with hyphens replaced as utils.Signals does.
+func objectKeys(obj string) []string {
+	obj = strings.TrimSpace(obj)
+	if !strings.HasPrefix(obj, "{") {
+		return nil
+	}
+	var keys []string
+	depth := 0
+	expectKey := false
+	for i := 0; i < len(obj); i++ {
+		if depth == 1 && expectKey {
+			if m := objectKeyRE.FindStringSubmatch(obj[i:]); m != nil {
+				keys = append(keys, signalID(m[1]+m[2]+m[3]))
+				i += len(m[0]) - 1
+				expectKey = false
+				continue
+			}
+		}
+		switch ch := obj[i]; ch {
+		case '{', '[', '(':
+			depth++
+			expectKey = depth == 1
+		case '}', ']', ')':
+			depth--
+		case ',':
+			expectKey = depth == 1
+		case '\'', '"', '`':
+			for i++; i < len(obj) && obj[i] != ch; i++ {
+				if obj[i] == '\\' {
+					i++
+				}
+			}
+		}
+	}
+	return keys
+}
+
+// camel converts an attribute key to the signal name Datastar uses:
+// data-bind:first-name binds $firstName.
+func camel(key string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range key {
+		switch {
+		case r == '-':
+			upper = true
+		case upper:
+			b.WriteRune(unicode.ToUpper(r))
+			upper = false
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// namespace returns the first segment of a signal path.
+func namespace(path string) string {
+	ns, _, _ := strings.Cut(path, ".")
+	return ns
+}
@@ -0,0 +1,191 @@
+package dslint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+
+	templparser "github.com/a-h/templ/parser/v2"
+	"github.com/a-h/templ/parser/v2/visitor"
+)
+
+// AddTemplFile checks the templ source src, reporting positions under name.
+func (c *Checker) AddTemplFile(name string, src []byte) error {
+	tf, err := templparser.ParseString(string(src))
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", name, err)
+	}
+	file := c.fset.AddFile(name, -1, len(src))
+	file.SetLinesForContent(src)
+	// templ is imported implicitly by generated code.
+	t := &templScanner{c: c, file: file, imports: map[string]string{"templ": templPath}}
+
+	// Imports come first so snippets can resolve ds, utils and templ.
+	var decls []*templparser.TemplateFileGoExpression
+	for _, n := range tf.Nodes {
+		if g, ok := n.(*templparser.TemplateFileGoExpression); ok {
+			if f, _ := t.parseFile(g.Expression); f != nil {
+				for k, v := range imports(f) {
+					t.imports[k] = v
+				}
+			}
+			decls = append(decls, g)
+		}
+	}
+	for _, g := range decls {
+		if f, mapPos := t.parseFile(g.Expression); f != nil {
+			s := &goScanner{c: c, imports: t.imports, pos: mapPos}
+			for _, decl := range f.Decls {
+				c.enter()
+				s.scan(decl)
+				c.leave()
+			}
+		}
+	}
+
+	v := visitor.New()
+	visitTemplate := v.HTMLTemplate
+	v.HTMLTemplate = func(n *templparser.HTMLTemplate) error {
+		c.enter()
+		defer c.leave()
+		return visitTemplate(n)
+	}
+	v.TemplateFileGoExpression = func(*templparser.TemplateFileGoExpression) error { return nil }
+	v.ScriptElement = func(*templparser.ScriptElement) error { return nil }
+	v.ConstantAttribute = func(n *templparser.ConstantAttribute) error {
+		if k, ok := n.Key.(templparser.ConstantAttributeKey); ok {
+			c.checkAttr(k.Name, n.Value, true, t.pos(k.NameRange.From))
+		}
+		return nil
+	}
+	v.BoolConstantAttribute = func(n *templparser.BoolConstantAttribute) error {
+		if k, ok := n.Key.(templparser.ConstantAttributeKey); ok {
+			c.checkAttr(k.Name, "", true, t.pos(k.NameRange.From))
+		}
+		return nil
+	}
+	v.ExpressionAttribute = func(n *templparser.ExpressionAttribute) error {
+		t.attrExpr(n.Key, n.Expression)
+		return nil
+	}
+	v.BoolExpressionAttribute = func(n *templparser.BoolExpressionAttribute) error {
+		t.attrExpr(n.Key, n.Expression)
+		return nil
+	}
+	v.SpreadAttributes = func(n *templparser.SpreadAttributes) error {
+		t.expr(n.Expression)
+		return nil
+	}
+	visitConditional := v.ConditionalAttribute
+	v.ConditionalAttribute = func(n *templparser.ConditionalAttribute) error {
+		t.expr(n.Expression)
+		return visitConditional(n)
+	}
+	visitElement := v.TemplElementExpression
+	v.TemplElementExpression = func(n *templparser.TemplElementExpression) error {
+		t.expr(n.Expression)
+		return visitElement(n)
+	}
+	v.CallTemplateExpression = func(n *templparser.CallTemplateExpression) error {
+		t.expr(n.Expression)
+		return nil
+	}
+	v.GoCode = func(n *templparser.GoCode) error {
+		t.expr(n.Expression)
+		return nil
+	}
+	v.StringExpression = func(n *templparser.StringExpression) error {
+		t.expr(n.Expression)
+		return nil
+	}
+	visitIf := v.IfExpression
+	v.IfExpression = func(n *templparser.IfExpression) error {
+		t.expr(n.Expression)
+		for _, e := range n.ElseIfs {
+			t.expr(e.Expression)
+		}
+		return visitIf(n)
+	}
+	visitFor := v.ForExpression
+	v.ForExpression = func(n *templparser.ForExpression) error {
+		t.expr(n.Expression)
+		return visitFor(n)
+	}
+	visitSwitch := v.SwitchExpression
+	v.SwitchExpression = func(n *templparser.SwitchExpression) error {
+		t.expr(n.Expression)
+		return visitSwitch(n)
+	}
+	return tf.Visit(v)
+}
+
+// templScanner checks the Go snippets of one .templ file.
+type templScanner struct {
+	c       *Checker
+	file    *token.File
+	imports map[string]string
+}
+
+func (t *templScanner) pos(p templparser.Position) token.Pos {
+	return t.file.Pos(int(min(p.Index, int64(t.file.Size()))))
+}
+
+// attrExpr checks an attribute whose value is a Go expression. A string
+// literal value is as good as a constant one.
+func (t *templScanner) attrExpr(key templparser.AttributeKey, e templparser.Expression) {
+	k, ok := key.(templparser.ConstantAttributeKey)
+	if !ok {
+		t.expr(e)
+		return
+	}
+	x, _ := parser.ParseExpr(e.Value)
+	value, known := "", false
+	if x != nil {
+		value, known = stringLit(x)
+	}
+	t.c.checkAttr(k.Name, value, known, t.pos(k.NameRange.From))
+	t.expr(e)
+}
+
+// Wrappers that make a snippet parseable, tried in order: an expression,
+// statements ({{ }} blocks), a for clause and a switch tag.
+var wrappers = []struct{ prefix, suffix string }{
+	{"package p; var _ = ", "\n"},
+	{"package p; func _() {\n", "\n}"},
+	{"package p; func _() { for ", " {} }"},
+	{"package p; func _() { switch ", " {} }"},
+}
+
+// expr checks a Go snippet in the current template.
+func (t *templScanner) expr(e templparser.Expression) {
+	for _, w := range wrappers {
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, "", w.prefix+e.Value+w.suffix, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		s := &goScanner{c: t.c, imports: t.imports, pos: t.mapper(fset, e, len(w.prefix))}
+		s.scan(f.Decls[0])
+		return
+	}
+}
+
+// parseFile parses top-level Go code of a .templ file.
+func (t *templScanner) parseFile(e templparser.Expression) (*ast.File, func(token.Pos) token.Pos) {
+	const prefix = "package p\n"
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", prefix+e.Value, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, nil
+	}
+	return f, t.mapper(fset, e, len(prefix))
+}
+
+// mapper maps positions in a wrapped snippet back to the .templ file.
+func (t *templScanner) mapper(fset *token.FileSet, e templparser.Expression, prefix int) func(token.Pos) token.Pos {
+	return func(p token.Pos) token.Pos {
+		off := fset.Position(p).Offset - prefix
+		return t.file.Pos(int(min(e.Range.From.Index+int64(max(off, 0)), int64(t.file.Size()))))
+	}
+}
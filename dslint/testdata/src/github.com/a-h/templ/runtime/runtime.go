@@ -0,0 +1,4 @@
+// Package runtime is a stub of github.com/a-h/templ/runtime for analyzer tests.
+package runtime
+
+func WriteString(w any, index int, s string) error { return nil }
@@ -0,0 +1,5 @@
+// Package ds is a stub of github.com/plaenen/webx/ds for analyzer tests.
+package ds
+
+func Get(url string) string              { return url }
+func OnClick(expr string) map[string]any { return nil }
@@ -0,0 +1,6 @@
+// Package utils is a stub of github.com/plaenen/webx/utils for analyzer tests.
+package utils
+
+type SignalManager struct{ DataSignals string }
+
+func Signals(id string, v any) *SignalManager { return &SignalManager{} }
@@ -0,0 +1,4 @@
+package page
+
+// Hand-written files are not checked.
+const doc = "<b data-on-click=\"$nothing\">"
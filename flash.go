@@ -0,0 +1,88 @@
+package webx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// FlashLevel is the severity of a flash message.
+type FlashLevel string
+
+const (
+	FlashInfo    FlashLevel = "info"
+	FlashSuccess FlashLevel = "success"
+	FlashWarning FlashLevel = "warning"
+	FlashError   FlashLevel = "error"
+)
+
+// FlashMessage is a one-shot message for the user, such as "Saved!".
+type FlashMessage struct {
+	Level FlashLevel `json:"level"`
+	Text  string     `json:"text"`
+}
+
+// flashSessionKey holds the queued flash messages as a JSON array.
+const flashSessionKey = "flash"
+
+// Flash queues a message in the current session. It is shown by the toast
+// outlet in layouts.Base on the next full page render, which makes it
+// survive a redirect:
+//
+//	webx.Flash(r.Context(), webx.FlashSuccess, "Profile saved")
+//	http.Redirect(w, r, "/profile", http.StatusSeeOther)
+//
+// SSE handlers can deliver queued messages immediately with
+// toast.PushFlashes.
+func Flash(ctx context.Context, level FlashLevel, text string) error {
+	wctx := FromContext(ctx)
+	store := wctx.SessionStore()
+	if store == nil {
+		return ErrNoSession
+	}
+	msgs, err := loadFlashes(store, wctx.SessionID)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(append(msgs, FlashMessage{Level: level, Text: text}))
+	if err != nil {
+		return fmt.Errorf("encoding flash messages: %w", err)
+	}
+	if err := store.Set(wctx.SessionID, flashSessionKey, string(data)); err != nil {
+		return fmt.Errorf("storing flash messages: %w", err)
+	}
+	return nil
+}
+
+// Flashes returns the queued messages of the current session, oldest first,
+// and removes them so each message is shown once.
+func Flashes(ctx context.Context) ([]FlashMessage, error) {
+	wctx := FromContext(ctx)
+	store := wctx.SessionStore()
+	if store == nil {
+		return nil, ErrNoSession
+	}
+	msgs, err := loadFlashes(store, wctx.SessionID)
+	if err != nil || len(msgs) == 0 {
+		return nil, err
+	}
+	if err := store.Set(wctx.SessionID, flashSessionKey, ""); err != nil {
+		return nil, fmt.Errorf("clearing flash messages: %w", err)
+	}
+	return msgs, nil
+}
+
+func loadFlashes(store SessionStore, sessionID string) ([]FlashMessage, error) {
+	raw, err := store.Get(sessionID, flashSessionKey)
+	if err != nil {
+		return nil, fmt.Errorf("loading flash messages: %w", err)
+	}
+	if raw == "" {
+		return nil, nil
+	}
+	var msgs []FlashMessage
+	if err := json.Unmarshal([]byte(raw), &msgs); err != nil {
+		return nil, fmt.Errorf("decoding flash messages: %w", err)
+	}
+	return msgs, nil
+}
@@ -0,0 +1,52 @@
+package webx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestFlash(t *testing.T) {
+	mw := SessionMiddleware(newMapStore())
+	_, first := serve(t, mw, httptest.NewRequest(http.MethodGet, "/", nil), func(w http.ResponseWriter, r *http.Request) {
+		if err := Flash(r.Context(), FlashSuccess, "Saved"); err != nil {
+			t.Fatalf("Flash: %v", err)
+		}
+		if err := Flash(r.Context(), FlashError, "But <not> everything"); err != nil {
+			t.Fatalf("Flash: %v", err)
+		}
+	})
+
+	next := func() []FlashMessage {
+		var got []FlashMessage
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: first.SessionID})
+		serve(t, mw, req, func(w http.ResponseWriter, r *http.Request) {
+			var err error
+			if got, err = Flashes(r.Context()); err != nil {
+				t.Fatalf("Flashes: %v", err)
+			}
+		})
+		return got
+	}
+
+	want := []FlashMessage{{FlashSuccess, "Saved"}, {FlashError, "But <not> everything"}}
+	if got := next(); !reflect.DeepEqual(got, want) {
+		t.Errorf("next request = %v, want %v", got, want)
+	}
+	if got := next(); len(got) != 0 {
+		t.Errorf("flashes shown twice: %v", got)
+	}
+}
+
+func TestFlash_NoSession(t *testing.T) {
+	if err := Flash(context.Background(), FlashInfo, "x"); !errors.Is(err, ErrNoSession) {
+		t.Errorf("Flash without session = %v, want ErrNoSession", err)
+	}
+	if _, err := Flashes(context.Background()); !errors.Is(err, ErrNoSession) {
+		t.Errorf("Flashes without session = %v, want ErrNoSession", err)
+	}
+}
@@ -0,0 +1,179 @@
+package webx
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// FormTokenHeader carries a one-time form token. When it is absent the
+// FormTokenParam query parameter is checked instead, which is the easiest way
+// to attach a token to a Datastar action:
+//
+//	ds.Post("/api/account/delete?form_token=" + token)
+const (
+	FormTokenHeader = "X-Form-Token"
+	FormTokenParam  = "form_token"
+)
+
+// FormTokenTTL is how long an issued form token stays valid.
+const FormTokenTTL = time.Hour
+
+// MaxFormTokens is how many unused form tokens a session keeps. Issuing
+// more drops the oldest, so rendering forms cannot grow the session without
+// bound, which matters most for cookie-backed stores.
+const MaxFormTokens = 16
+
+// formTokensKey holds the session's outstanding form tokens, expired and
+// used ones removed.
+const formTokensKey = "form_tokens"
+
+// formToken is an outstanding token as stored in the session.
+type formToken struct {
+	Token   string `json:"t"`
+	Expires int64  `json:"e"`
+	Action  string `json:"a"`
+}
+
+// formTokensMu serializes the read-modify-write of formTokensKey within
+// this process.
+var formTokensMu sync.Mutex
+
+// ErrInvalidFormToken is returned when a form token is missing, unknown,
+// expired, already used or issued for a different action.
+var ErrInvalidFormToken = errors.New("webx: invalid or already used form token")
+
+// FormToken issues a one-time token for action, bound to the current
+// session. Use it for sensitive actions (deleting an account, changing a
+// password) on top of the session-wide CSRF token, and verify it with
+// ConsumeFormToken or RequireFormToken. Each call issues a new token;
+// render it once per form. A session keeps at most MaxFormTokens unused
+// tokens; older ones stop working.
+func FormToken(ctx context.Context, action string) (string, error) {
+	wctx := FromContext(ctx)
+	store := wctx.SessionStore()
+	if store == nil {
+		return "", ErrNoSession
+	}
+	token, err := randomHex(16)
+	if err != nil {
+		return "", fmt.Errorf("generating form token: %w", err)
+	}
+	formTokensMu.Lock()
+	defer formTokensMu.Unlock()
+	tokens, err := loadFormTokens(store, wctx.SessionID)
+	if err != nil {
+		return "", err
+	}
+	tokens = append(tokens, formToken{Token: token, Expires: timeNow().Add(FormTokenTTL).Unix(), Action: action})
+	if n := len(tokens) - MaxFormTokens; n > 0 {
+		tokens = tokens[n:]
+	}
+	if err := saveFormTokens(store, wctx.SessionID, tokens); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// ConsumeFormToken verifies the form token on r against action and marks it
+// used, so replaying the same request fails.
+func ConsumeFormToken(r *http.Request, action string) error {
+	wctx := FromContext(r.Context())
+	store := wctx.SessionStore()
+	if store == nil {
+		return ErrNoSession
+	}
+	token := r.Header.Get(FormTokenHeader)
+	if token == "" {
+		token = r.URL.Query().Get(FormTokenParam)
+	}
+	if token == "" {
+		return ErrInvalidFormToken
+	}
+	formTokensMu.Lock()
+	defer formTokensMu.Unlock()
+	tokens, err := loadFormTokens(store, wctx.SessionID)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(tokens, func(t formToken) bool {
+		return subtle.ConstantTimeCompare([]byte(t.Token), []byte(token)) == 1
+	})
+	if i < 0 {
+		return ErrInvalidFormToken
+	}
+	found := tokens[i]
+	if err := saveFormTokens(store, wctx.SessionID, slices.Delete(tokens, i, i+1)); err != nil {
+		return fmt.Errorf("consuming form token: %w", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(found.Action), []byte(action)) != 1 {
+		return ErrInvalidFormToken
+	}
+	if !timeNow().Before(time.Unix(found.Expires, 0)) {
+		return ErrInvalidFormToken
+	}
+	return nil
+}
+
+// loadFormTokens returns the session's unexpired form tokens. Unreadable
+// data counts as none, which only invalidates outstanding tokens.
+func loadFormTokens(store SessionStore, sessionID string) ([]formToken, error) {
+	v, err := store.Get(sessionID, formTokensKey)
+	if err != nil {
+		return nil, fmt.Errorf("loading form tokens: %w", err)
+	}
+	var tokens []formToken
+	if v == "" || json.Unmarshal([]byte(v), &tokens) != nil {
+		return nil, nil
+	}
+	now := timeNow()
+	return slices.DeleteFunc(tokens, func(t formToken) bool { return !now.Before(time.Unix(t.Expires, 0)) }), nil
+}
+
+func saveFormTokens(store SessionStore, sessionID string, tokens []formToken) error {
+	v := ""
+	if len(tokens) > 0 {
+		b, err := json.Marshal(tokens)
+		if err != nil {
+			return fmt.Errorf("encoding form tokens: %w", err)
+		}
+		v = string(b)
+	}
+	if err := store.Set(sessionID, formTokensKey, v); err != nil {
+		return fmt.Errorf("storing form tokens: %w", err)
+	}
+	return nil
+}
+
+// RequireFormToken returns middleware that rejects mutating requests without
+// a valid one-time token for action. It must run inside SessionMiddleware.
+func RequireFormToken(action string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !isMutating(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			wctx := FromContext(r.Context())
+			var opts CSRFOptions
+			if wctx.session != nil {
+				opts = wctx.session.opts.CSRF
+			}
+			if err := ConsumeFormToken(r, action); err != nil {
+				if !errors.Is(err, ErrInvalidFormToken) && !errors.Is(err, ErrNoSession) {
+					http.Error(w, fmt.Sprintf("session store error: %v", err), http.StatusInternalServerError)
+					return
+				}
+				opts.forbid(w, err.Error())
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,322 @@
+// Package hub fans Datastar patches out to many long-lived SSE connections.
+//
+// Clients subscribe to topics by opening a stream, typically from
+// data-init on the page:
+//
+//	<div { ds.Init(ds.Get("/api/live", ds.WithRetry(ds.RetryAlways)))... }></div>
+//
+//	r.Get("/api/live", h.Handler(func(r *http.Request) []string {
+//	    return []string{hub.SessionTopic(webx.FromContext(r.Context()).SessionID), "dashboard"}
+//	}))
+//
+// Anything in the app can then publish to a topic:
+//
+//	h.PatchElementTempl("dashboard", stats.Card(latest))
+//
+// Each subscriber has a bounded queue, so a slow client never blocks
+// publishers; when its queue overflows its stream is ended. Datastar only
+// reopens a stream that ended cleanly when the request uses
+// ds.WithRetry(ds.RetryAlways), as above, so subscribe that way to have a
+// dropped client reconnect and get a fresh render. Idle streams get a
+// heartbeat so proxies keep them open and dead peers are noticed.
+package hub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/starfederation/datastar-go/datastar"
+)
+
+// Defaults used when Options fields are zero.
+const (
+	DefaultHeartbeat    = 15 * time.Second
+	DefaultBufferSize   = 64
+	DefaultWriteTimeout = 10 * time.Second
+)
+
+// Errors returned by Subscribe.
+var (
+	// ErrClosed is returned when the hub shuts down while a client is
+	// connected, or when subscribing to a closed hub.
+	ErrClosed = errors.New("hub: closed")
+	// ErrSlowConsumer is returned when a client is dropped because its
+	// queue overflowed.
+	ErrSlowConsumer = errors.New("hub: client too slow, disconnected")
+)
+
+// Event writes one or more SSE events to a subscriber. Events built by the
+// Hub methods do their expensive work (rendering, JSON encoding) once, not
+// once per subscriber.
+type Event func(sse *datastar.ServerSentEventGenerator) error
+
+// Options configures a Hub.
+type Options struct {
+	// Heartbeat is the interval at which an empty signals patch is sent on
+	// idle streams. Defaults to DefaultHeartbeat; negative disables it.
+	Heartbeat time.Duration
+	// BufferSize is the number of events queued per subscriber before it is
+	// considered too slow and disconnected. Defaults to DefaultBufferSize.
+	BufferSize int
+	// WriteTimeout bounds every write to a subscriber, so a stalled
+	// connection cannot pin its goroutine. Defaults to DefaultWriteTimeout.
+	WriteTimeout time.Duration
+}
+
+// Hub holds the open streams and the topics they subscribe to. It is safe
+// for concurrent use. The zero value is not usable; call New.
+type Hub struct {
+	opts   Options
+	mu     sync.RWMutex
+	topics map[string]map[*client]struct{}
+	closed bool
+}
+
+// client is one connected stream.
+type client struct {
+	queue chan Event
+	// drop is closed when the hub disconnects the client; reason says why.
+	drop     chan struct{}
+	dropOnce sync.Once
+	reason   error
+}
+
+func (c *client) disconnect(reason error) {
+	c.dropOnce.Do(func() {
+		c.reason = reason
+		close(c.drop)
+	})
+}
+
+// New returns a ready-to-use Hub. Call Close on shutdown to release
+// connected clients.
+func New(opts ...Options) *Hub {
+	var o Options
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if o.Heartbeat == 0 {
+		o.Heartbeat = DefaultHeartbeat
+	}
+	if o.BufferSize <= 0 {
+		o.BufferSize = DefaultBufferSize
+	}
+	if o.WriteTimeout == 0 {
+		o.WriteTimeout = DefaultWriteTimeout
+	}
+	return &Hub{
+		opts:   o,
+		topics: map[string]map[*client]struct{}{},
+	}
+}
+
+// SessionTopic is the topic for everything open in one browser session.
+func SessionTopic(sessionID string) string { return "session:" + sessionID }
+
+// UserTopic is the topic for every session of one user.
+func UserTopic(userID string) string { return "user:" + userID }
+
+// ResourceTopic is the topic for viewers of one resource, such as
+// ResourceTopic("invoice", "42"). Resource topics have their own prefix, so
+// a kind of "session" or "user" cannot reach SessionTopic or UserTopic
+// subscribers. Colons and percent signs in kind are escaped, so the kind and
+// id boundary is never ambiguous.
+func ResourceTopic(kind, id string) string {
+	return "resource:" + topicEscaper.Replace(kind) + ":" + id
+}
+
+var topicEscaper = strings.NewReplacer("%", "%25", ":", "%3A")
+
+// Subscribe turns the request into an SSE stream subscribed to topics and
+// blocks until the client disconnects, is dropped, or the hub closes.
+// The init funcs run after the subscription is registered and before any
+// published event is delivered, so they can send the current state without
+// missing updates.
+//
+// It returns nil when the client went away, ErrSlowConsumer or ErrClosed
+// when the hub ended the stream, or the first write error.
+func (h *Hub) Subscribe(w http.ResponseWriter, r *http.Request, topics []string, init ...Event) error {
+	c := &client{
+		queue: make(chan Event, h.opts.BufferSize),
+		drop:  make(chan struct{}),
+	}
+	if !h.add(c, topics) {
+		return ErrClosed
+	}
+	defer h.remove(c, topics)
+
+	rc := http.NewResponseController(w)
+	sse := datastar.NewSSE(w, r)
+	send := func(ev Event) error {
+		if h.opts.WriteTimeout > 0 {
+			// Not every ResponseWriter supports deadlines; the write then
+			// simply runs unbounded.
+			_ = rc.SetWriteDeadline(time.Now().Add(h.opts.WriteTimeout))
+		}
+		return ev(sse)
+	}
+
+	for _, ev := range init {
+		if err := send(ev); err != nil {
+			return writeErr(r.Context(), err)
+		}
+	}
+
+	var heartbeat <-chan time.Time
+	if h.opts.Heartbeat > 0 {
+		t := time.NewTicker(h.opts.Heartbeat)
+		defer t.Stop()
+		heartbeat = t.C
+	}
+
+	for {
+		select {
+		case ev := <-c.queue:
+			if err := send(ev); err != nil {
+				return writeErr(r.Context(), err)
+			}
+		case <-heartbeat:
+			if err := send(ping); err != nil {
+				return writeErr(r.Context(), err)
+			}
+		case <-c.drop:
+			return c.reason
+		case <-r.Context().Done():
+			return nil
+		}
+	}
+}
+
+// Handler returns an http.HandlerFunc that subscribes each request to the
+// topics returned by topics. Requests for which topics returns nothing are
+// rejected with 403, so topics doubles as the authorization check.
+func (h *Hub) Handler(topics func(r *http.Request) []string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		t := topics(r)
+		if len(t) == 0 {
+			http.Error(w, "no topics to subscribe to", http.StatusForbidden)
+			return
+		}
+		if err := h.Subscribe(w, r, t); err != nil && !errors.Is(err, ErrClosed) {
+			slog.WarnContext(r.Context(), "hub: subscriber stream ended", "path", r.URL.Path, "error", err)
+		}
+	}
+}
+
+// Publish queues ev for every subscriber of topic and returns how many
+// subscribers it was queued for. It never blocks: subscribers whose queue
+// is full are disconnected instead.
+func (h *Hub) Publish(topic string, ev Event) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	n := 0
+	for c := range h.topics[topic] {
+		select {
+		case c.queue <- ev:
+			n++
+		default:
+			c.disconnect(ErrSlowConsumer)
+		}
+	}
+	return n
+}
+
+// PatchElements publishes a patch of pre-rendered HTML to topic.
+func (h *Hub) PatchElements(topic, elements string, opts ...datastar.PatchElementOption) int {
+	return h.Publish(topic, func(sse *datastar.ServerSentEventGenerator) error {
+		return sse.PatchElements(elements, opts...)
+	})
+}
+
+// PatchElementTempl renders c once and publishes it to topic. The component
+// is rendered without a request, so it must not depend on per-request state
+// such as the CSRF token in WebXContext.
+func (h *Hub) PatchElementTempl(topic string, c templ.Component, opts ...datastar.PatchElementOption) (int, error) {
+	var buf bytes.Buffer
+	if err := c.Render(context.Background(), &buf); err != nil {
+		return 0, fmt.Errorf("hub: rendering patch: %w", err)
+	}
+	return h.PatchElements(topic, buf.String(), opts...), nil
+}
+
+// PatchSignals encodes signals once and publishes the patch to topic.
+func (h *Hub) PatchSignals(topic string, signals any, opts ...datastar.PatchSignalsOption) (int, error) {
+	data, err := json.Marshal(signals)
+	if err != nil {
+		return 0, fmt.Errorf("hub: encoding signals: %w", err)
+	}
+	return h.Publish(topic, func(sse *datastar.ServerSentEventGenerator) error {
+		return sse.PatchSignals(data, opts...)
+	}), nil
+}
+
+// Subscribers returns the number of clients subscribed to topic.
+func (h *Hub) Subscribers(topic string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.topics[topic])
+}
+
+// Close disconnects every client and rejects new subscriptions.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+	h.closed = true
+	for _, clients := range h.topics {
+		for c := range clients {
+			c.disconnect(ErrClosed)
+		}
+	}
+}
+
+func (h *Hub) add(c *client, topics []string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return false
+	}
+	for _, t := range topics {
+		if h.topics[t] == nil {
+			h.topics[t] = map[*client]struct{}{}
+		}
+		h.topics[t][c] = struct{}{}
+	}
+	return true
+}
+
+func (h *Hub) remove(c *client, topics []string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, t := range topics {
+		delete(h.topics[t], c)
+		if len(h.topics[t]) == 0 {
+			delete(h.topics, t)
+		}
+	}
+}
+
+// ping is the heartbeat: an empty signals patch is a no-op for Datastar but
+// keeps intermediaries from timing out the stream.
+func ping(sse *datastar.ServerSentEventGenerator) error {
+	return sse.PatchSignals([]byte("{}"))
+}
+
+// writeErr maps errors caused by the client going away to nil.
+func writeErr(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,228 @@
+package hub_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/plaenen/webx/hub"
+	"github.com/starfederation/datastar-go/datastar"
+)
+
+// stream is a test client reading SSE data lines.
+type stream struct {
+	lines  chan string
+	cancel context.CancelFunc
+}
+
+func connect(t *testing.T, srv *httptest.Server, path string) *stream {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+path, nil)
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		cancel()
+		t.Fatalf("connect: %v", err)
+	}
+	s := &stream{lines: make(chan string, 64), cancel: cancel}
+	go func() {
+		defer resp.Body.Close()
+		sc := bufio.NewScanner(resp.Body)
+		for sc.Scan() {
+			if line, ok := strings.CutPrefix(sc.Text(), "data: "); ok {
+				s.lines <- line
+			}
+		}
+		close(s.lines)
+	}()
+	t.Cleanup(cancel)
+	return s
+}
+
+// expect waits for a data line containing want.
+func (s *stream) expect(t *testing.T, want string) {
+	t.Helper()
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case line, ok := <-s.lines:
+			if !ok {
+				t.Fatalf("stream closed before %q", want)
+			}
+			if strings.Contains(line, want) {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for %q", want)
+		}
+	}
+}
+
+// waitFor polls until cond holds.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func newServer(t *testing.T, h *hub.Hub) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(h.Handler(func(r *http.Request) []string {
+		return r.URL.Query()["topic"]
+	}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(h.Close)
+	return srv
+}
+
+func TestHub_FanOut(t *testing.T) {
+	h := hub.New()
+	srv := newServer(t, h)
+	a := connect(t, srv, "/?topic=room&topic="+hub.UserTopic("ann"))
+	b := connect(t, srv, "/?topic=room")
+	waitFor(t, "subscribers", func() bool { return h.Subscribers("room") == 2 })
+
+	if n := h.PatchElements("room", `<div id="msg">hello</div>`); n != 2 {
+		t.Errorf("PatchElements delivered to %d, want 2", n)
+	}
+	a.expect(t, `<div id="msg">hello</div>`)
+	b.expect(t, `<div id="msg">hello</div>`)
+
+	if n, err := h.PatchSignals(hub.UserTopic("ann"), map[string]int{"unread": 3}); err != nil || n != 1 {
+		t.Fatalf("PatchSignals = %d, %v", n, err)
+	}
+	a.expect(t, `{"unread":3}`)
+
+	card := templ.Raw(`<p id="card">rendered</p>`)
+	if _, err := h.PatchElementTempl("room", card, datastar.WithModeAppend()); err != nil {
+		t.Fatal(err)
+	}
+	b.expect(t, `<p id="card">rendered</p>`)
+
+	if n := h.PatchElements("elsewhere", "<p></p>"); n != 0 {
+		t.Errorf("publish to empty topic reached %d clients", n)
+	}
+}
+
+func TestHub_CleanupOnDisconnect(t *testing.T) {
+	h := hub.New()
+	srv := newServer(t, h)
+	s := connect(t, srv, "/?topic=room")
+	waitFor(t, "subscriber", func() bool { return h.Subscribers("room") == 1 })
+	s.cancel()
+	waitFor(t, "cleanup", func() bool { return h.Subscribers("room") == 0 })
+}
+
+func TestHub_Heartbeat(t *testing.T) {
+	h := hub.New(hub.Options{Heartbeat: 20 * time.Millisecond})
+	srv := newServer(t, h)
+	s := connect(t, srv, "/?topic=room")
+	s.expect(t, "signals {}")
+}
+
+func TestHub_SlowConsumerDropped(t *testing.T) {
+	h := hub.New(hub.Options{BufferSize: 1, Heartbeat: -1})
+	t.Cleanup(h.Close)
+	release := make(chan struct{})
+	result := make(chan error, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The init event blocks, so nothing is drained from the queue.
+		result <- h.Subscribe(w, r, []string{"room"}, func(*datastar.ServerSentEventGenerator) error {
+			<-release
+			return nil
+		})
+	}))
+	t.Cleanup(srv.Close)
+	connect(t, srv, "/")
+	waitFor(t, "subscriber", func() bool { return h.Subscribers("room") == 1 })
+
+	if n := h.PatchElements("room", "<p>1</p>"); n != 1 {
+		t.Fatalf("first publish queued for %d", n)
+	}
+	if n := h.PatchElements("room", "<p>2</p>"); n != 0 {
+		t.Errorf("overflowing publish queued for %d, want 0", n)
+	}
+	close(release)
+	select {
+	case err := <-result:
+		if !errors.Is(err, hub.ErrSlowConsumer) {
+			t.Errorf("Subscribe = %v, want ErrSlowConsumer", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("slow consumer not disconnected")
+	}
+	waitFor(t, "cleanup", func() bool { return h.Subscribers("room") == 0 })
+}
+
+func TestHub_Close(t *testing.T) {
+	h := hub.New()
+	result := make(chan error, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result <- h.Subscribe(w, r, []string{"room"})
+	}))
+	t.Cleanup(srv.Close)
+	connect(t, srv, "/")
+	waitFor(t, "subscriber", func() bool { return h.Subscribers("room") == 1 })
+
+	h.Close()
+	select {
+	case err := <-result:
+		if !errors.Is(err, hub.ErrClosed) {
+			t.Errorf("Subscribe = %v, want ErrClosed", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Close did not end the stream")
+	}
+
+	rec := httptest.NewRecorder()
+	if err := h.Subscribe(rec, httptest.NewRequest(http.MethodGet, "/", nil), []string{"room"}); !errors.Is(err, hub.ErrClosed) {
+		t.Errorf("Subscribe after Close = %v, want ErrClosed", err)
+	}
+}
+
+func TestHub_HandlerRejectsNoTopics(t *testing.T) {
+	h := hub.New()
+	srv := newServer(t, h)
+	resp, err := srv.Client().Get(srv.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("status = %d, want 403", resp.StatusCode)
+	}
+}
+
+func TestTopics_DoNotCollide(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+	}{
+		{"resource of kind session", hub.ResourceTopic("session", "abc"), hub.SessionTopic("abc")},
+		{"resource of kind user", hub.ResourceTopic("user", "ann"), hub.UserTopic("ann")},
+		{"session and user", hub.SessionTopic("ann"), hub.UserTopic("ann")},
+		{"colon in kind", hub.ResourceTopic("a:b", "c"), hub.ResourceTopic("a", "b:c")},
+		{"escaped colon in kind", hub.ResourceTopic("a%3Ab", "c"), hub.ResourceTopic("a:b", "c")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.a == tt.b {
+				t.Errorf("topics collide on %q", tt.a)
+			}
+		})
+	}
+	if got, want := hub.ResourceTopic("invoice", "42"), "resource:invoice:42"; got != want {
+		t.Errorf("ResourceTopic = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,344 @@
+package idgen
+
+import (
+	crand "crypto/rand"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// Generator makes prefixed IDs in one format. NewGenerator and
+// Deterministic return the built-in formats; implement it for a format of
+// your own and install it with SetDefault.
+type Generator interface {
+	// Generate returns a new ID: the prefix, an underscore and the body.
+	Generate(prefix string) string
+	// Validate reports whether id has the prefix and a body in the
+	// generator's format, with a matching check character if it adds one.
+	Validate(id, prefix string) error
+}
+
+// Format is the format of the ID body.
+type Format int
+
+const (
+	// FormatULID is a 26-character lower-case ULID, ordered by time and
+	// monotonic within a millisecond. The default.
+	FormatULID Format = iota
+	// FormatUUIDv7 is a 36-character RFC 9562 version 7 UUID, ordered by
+	// time to the millisecond.
+	FormatUUIDv7
+	// FormatBase58 is Length random base58 characters, which leave out
+	// look-alikes such as 0 and O. Short, but not ordered.
+	FormatBase58
+)
+
+// Options configures a Generator.
+type Options struct {
+	Format Format
+	// Length is the number of characters of a FormatBase58 body. Defaults
+	// to 16, about 94 bits.
+	Length int
+	// Checksum appends a check character to the body, so Validate catches
+	// a mistyped character and most swapped neighbours in pasted IDs.
+	Checksum bool
+	// Rand is the source of randomness. Defaults to crypto/rand.Reader.
+	Rand io.Reader
+	// Now is the clock of the time-ordered formats. Defaults to time.Now.
+	Now func() time.Time
+}
+
+func resolveOptions(opts []Options) Options {
+	var o Options
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if o.Length <= 0 {
+		o.Length = 16
+	}
+	if o.Rand == nil {
+		o.Rand = crand.Reader
+	}
+	if o.Now == nil {
+		o.Now = time.Now
+	}
+	return o
+}
+
+// Alphabets of the formats, which check characters are drawn from.
+const (
+	ulidAlphabet   = "0123456789abcdefghjkmnpqrstvwxyz"
+	uuidAlphabet   = "0123456789abcdef"
+	base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+)
+
+// generator is the built-in Generator. Rand and Now are only used with mu
+// held, so they need not be safe for concurrent use.
+type generator struct {
+	opts    Options
+	mu      sync.Mutex
+	entropy *ulid.MonotonicEntropy
+}
+
+// NewGenerator returns a Generator for the format in opts. It is safe for
+// concurrent use.
+//
+//	orders := idgen.NewGenerator(idgen.Options{Format: idgen.FormatBase58, Checksum: true})
+//	id := orders.Generate("ord") // "ord_7Hq2xKf9RbT4mWcNe"
+func NewGenerator(opts ...Options) Generator {
+	return newGenerator(resolveOptions(opts))
+}
+
+func newGenerator(o Options) *generator {
+	return &generator{opts: o, entropy: ulid.Monotonic(o.Rand, 0)}
+}
+
+// Deterministic returns a Generator that makes the same IDs in the same
+// order for the same seed, for tests and golden files. Unless opts sets
+// them, randomness comes from a ChaCha8 stream seeded with seed, and the
+// clock starts at 2025-01-01 UTC and advances a millisecond per ID.
+//
+//	restore := idgen.SetDefault(idgen.Deterministic(1))
+//	t.Cleanup(restore)
+func Deterministic(seed uint64, opts ...Options) Generator {
+	var o Options
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if o.Rand == nil {
+		var s [32]byte
+		binary.LittleEndian.PutUint64(s[:], seed)
+		o.Rand = rand.NewChaCha8(s)
+	}
+	if o.Now == nil {
+		now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		o.Now = func() time.Time {
+			now = now.Add(time.Millisecond)
+			return now
+		}
+	}
+	return NewGenerator(o)
+}
+
+// Generate implements Generator.
+func (g *generator) Generate(prefix string) string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var body string
+	switch g.opts.Format {
+	case FormatUUIDv7:
+		body = g.uuidV7()
+	case FormatBase58:
+		body = g.base58()
+	default:
+		body = strings.ToLower(g.ulidLocked().String())
+	}
+	if g.opts.Checksum {
+		body += string(checkChar(body, g.alphabet()))
+	}
+	return prefix + "_" + body
+}
+
+// Validate implements Generator. Letters of ULID and UUID bodies are
+// accepted in either case.
+func (g *generator) Validate(id, prefix string) error {
+	i := strings.LastIndexByte(id, '_')
+	if i <= 0 {
+		return fmt.Errorf("%w %q: missing prefix", ErrInvalidID, id)
+	}
+	if id[:i] != prefix {
+		return fmt.Errorf("%w: %q is not a %q id", ErrPrefixMismatch, id, prefix)
+	}
+	if problem := g.check(id[i+1:]); problem != "" {
+		return fmt.Errorf("%w %q: %s", ErrInvalidID, id, problem)
+	}
+	return nil
+}
+
+// check returns what is wrong with body, or "" when it is valid.
+func (g *generator) check(body string) string {
+	if g.opts.Format != FormatBase58 {
+		body = strings.ToLower(body)
+	}
+	var sum byte
+	if g.opts.Checksum {
+		if body == "" {
+			return "missing check character"
+		}
+		body, sum = body[:len(body)-1], body[len(body)-1]
+	}
+	switch g.opts.Format {
+	case FormatUUIDv7:
+		if !isUUIDv7(body) {
+			return "not a version 7 UUID"
+		}
+	case FormatBase58:
+		if len(body) != g.opts.Length || strings.Trim(body, base58Alphabet) != "" {
+			return fmt.Sprintf("not %d base58 characters", g.opts.Length)
+		}
+	default:
+		if _, err := ulid.ParseStrict(body); err != nil {
+			return err.Error()
+		}
+	}
+	if g.opts.Checksum && checkChar(body, g.alphabet()) != sum {
+		return "check character mismatch"
+	}
+	return ""
+}
+
+func (g *generator) alphabet() string {
+	switch g.opts.Format {
+	case FormatUUIDv7:
+		return uuidAlphabet
+	case FormatBase58:
+		return base58Alphabet
+	default:
+		return ulidAlphabet
+	}
+}
+
+// ulid returns a ULID from the generator's clock and randomness, whatever
+// its format. NewID uses it.
+func (g *generator) ulid() ulid.ULID {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.ulidLocked()
+}
+
+func (g *generator) ulidLocked() ulid.ULID {
+	return ulid.MustNew(ulid.Timestamp(g.opts.Now()), g.entropy)
+}
+
+// text returns 26 random base32 characters, like crypto/rand.Text.
+func (g *generator) text() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var b [16]byte
+	g.read(b[:])
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b[:])
+}
+
+func (g *generator) uuidV7() string {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], uint64(g.opts.Now().UnixMilli())<<16)
+	g.read(b[6:])
+	b[6] = b[6]&0x0f | 0x70 // version 7
+	b[8] = b[8]&0x3f | 0x80 // RFC 9562 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func (g *generator) base58() string {
+	out := make([]byte, 0, g.opts.Length)
+	buf := make([]byte, g.opts.Length)
+	for len(out) < g.opts.Length {
+		g.read(buf)
+		for _, c := range buf {
+			// 232 is the largest multiple of 58 below 256; rejecting the
+			// bytes above it keeps every character equally likely.
+			if c < 232 && len(out) < g.opts.Length {
+				out = append(out, base58Alphabet[c%58])
+			}
+		}
+	}
+	return string(out)
+}
+
+func (g *generator) read(p []byte) {
+	if _, err := io.ReadFull(g.opts.Rand, p); err != nil {
+		panic(fmt.Errorf("idgen: reading randomness: %w", err))
+	}
+}
+
+// isUUIDv7 reports whether s is a lower-case version 7 UUID with the RFC
+// 9562 variant.
+func isUUIDv7(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i := range len(s) {
+		switch i {
+		case 8, 13, 18, 23:
+			if s[i] != '-' {
+				return false
+			}
+		default:
+			if strings.IndexByte(uuidAlphabet, s[i]) < 0 {
+				return false
+			}
+		}
+	}
+	return s[14] == '7' && strings.IndexByte("89ab", s[19]) >= 0
+}
+
+// checkChar returns the Luhn mod N check character of body over alphabet,
+// which catches any single mistyped character and most swaps of adjacent
+// ones. Characters outside alphabet, such as UUID hyphens, are skipped.
+func checkChar(body, alphabet string) byte {
+	n := len(alphabet)
+	factor, sum := 2, 0
+	for i := len(body) - 1; i >= 0; i-- {
+		code := strings.IndexByte(alphabet, body[i])
+		if code < 0 {
+			continue
+		}
+		addend := factor * code
+		sum += addend/n + addend%n
+		factor = 3 - factor
+	}
+	return alphabet[(n-sum%n)%n]
+}
+
+var (
+	// std is the initial default, and makes NewID's ULIDs when the default
+	// is not a built-in generator.
+	std = newGenerator(resolveOptions(nil))
+
+	defaultMu  sync.RWMutex
+	defaultGen Generator = std
+)
+
+// Default returns the generator used by New, Generate and Validate.
+func Default() Generator {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultGen
+}
+
+// SetDefault replaces the generator used by New, Generate, Validate, NewID
+// and Text, and returns a function that restores the previous one. It
+// affects the whole process, so tests using it must not run in parallel.
+func SetDefault(g Generator) (restore func()) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	prev := defaultGen
+	defaultGen = g
+	return func() {
+		defaultMu.Lock()
+		defer defaultMu.Unlock()
+		defaultGen = prev
+	}
+}
+
+// builtin returns the default generator if it is a built-in one, and std
+// otherwise.
+func builtin() *generator {
+	if g, ok := Default().(*generator); ok {
+		return g
+	}
+	return std
+}
+
+// Text returns 26 random base32 characters like crypto/rand.Text, read from
+// the default generator, so utils.RandomID is reproducible under a
+// Deterministic default.
+func Text() string {
+	return builtin().text()
+}
@@ -0,0 +1,187 @@
+package idgen
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerator_Formats(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		bodyLen int
+	}{
+		{"ulid", Options{}, 26},
+		{"ulid checksum", Options{Checksum: true}, 27},
+		{"uuidv7", Options{Format: FormatUUIDv7}, 36},
+		{"uuidv7 checksum", Options{Format: FormatUUIDv7, Checksum: true}, 37},
+		{"base58", Options{Format: FormatBase58}, 16},
+		{"base58 length", Options{Format: FormatBase58, Length: 10}, 10},
+		{"base58 checksum", Options{Format: FormatBase58, Checksum: true}, 17},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGenerator(tt.opts)
+			seen := map[string]bool{}
+			for range 100 {
+				id := g.Generate("ord")
+				body, ok := strings.CutPrefix(id, "ord_")
+				if !ok || len(body) != tt.bodyLen {
+					t.Fatalf("Generate = %q, want ord_ and %d characters", id, tt.bodyLen)
+				}
+				if err := g.Validate(id, "ord"); err != nil {
+					t.Fatalf("Validate(%q) = %v", id, err)
+				}
+				if seen[id] {
+					t.Fatalf("duplicate id %q", id)
+				}
+				seen[id] = true
+			}
+		})
+	}
+}
+
+func TestGenerator_UUIDv7(t *testing.T) {
+	now := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	g := NewGenerator(Options{Format: FormatUUIDv7, Now: func() time.Time { return now }})
+	body := strings.TrimPrefix(g.Generate("evt"), "evt_")
+	if body[14] != '7' || !strings.ContainsRune("89ab", rune(body[19])) {
+		t.Errorf("body %q lacks the version 7 and RFC 9562 variant bits", body)
+	}
+	if got, want := body[:13], "019cb73d-3218"; got != want {
+		t.Errorf("timestamp part = %q, want %q", got, want)
+	}
+}
+
+func TestGenerator_Validate(t *testing.T) {
+	ulids := NewGenerator(Options{})
+	uuids := NewGenerator(Options{Format: FormatUUIDv7})
+	short := NewGenerator(Options{Format: FormatBase58})
+	id := ulids.Generate("usr")
+
+	tests := []struct {
+		name    string
+		g       Generator
+		id      string
+		wantErr error
+	}{
+		{"upper case ulid", ulids, "usr_" + strings.ToUpper(strings.TrimPrefix(id, "usr_")), nil},
+		{"wrong prefix", ulids, id, ErrPrefixMismatch},
+		{"no prefix", ulids, strings.TrimPrefix(id, "usr_"), ErrInvalidID},
+		{"ulid as uuid", uuids, id, ErrInvalidID},
+		{"uuid v4", uuids, "usr_9f1c2b4e-8d3a-4c6f-9b2e-1a7d5e3c8f04", ErrInvalidID},
+		{"ulid as base58", short, id, ErrInvalidID},
+		{"base58 look-alike", short, "usr_0OIl456789ABCDEF", ErrInvalidID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefix := "usr"
+			if tt.wantErr == ErrPrefixMismatch {
+				prefix = "inv"
+			}
+			err := tt.g.Validate(tt.id, prefix)
+			if tt.wantErr == nil && err != nil || tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate(%q, %q) = %v, want %v", tt.id, prefix, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGenerator_ChecksumCatchesTypos(t *testing.T) {
+	for _, format := range []Format{FormatULID, FormatUUIDv7, FormatBase58} {
+		g := NewGenerator(Options{Format: format, Checksum: true})
+		alphabet := g.(*generator).alphabet()
+		for range 20 {
+			id := g.Generate("k")
+			for i := 2; i < len(id); i++ {
+				if id[i] == '-' {
+					continue
+				}
+				// Every other character in the same position is a typo.
+				for _, c := range []byte(alphabet) {
+					if c == id[i] {
+						continue
+					}
+					typo := id[:i] + string(c) + id[i+1:]
+					if err := g.Validate(typo, "k"); err == nil {
+						t.Fatalf("format %d: Validate accepted %q, a typo of %q", format, typo, id)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestDeterministic(t *testing.T) {
+	for _, format := range []Format{FormatULID, FormatUUIDv7, FormatBase58} {
+		a := Deterministic(42, Options{Format: format})
+		b := Deterministic(42, Options{Format: format})
+		c := Deterministic(43, Options{Format: format})
+		for i := range 5 {
+			x, y, z := a.Generate("t"), b.Generate("t"), c.Generate("t")
+			if x != y {
+				t.Errorf("format %d, id %d: same seed gave %q and %q", format, i, x, y)
+			}
+			if x == z {
+				t.Errorf("format %d, id %d: seeds 42 and 43 both gave %q", format, i, x)
+			}
+		}
+	}
+}
+
+func TestDeterministic_TimeOrdered(t *testing.T) {
+	g := Deterministic(1)
+	prev := ""
+	for range 100 {
+		id := g.Generate("t")
+		if id <= prev {
+			t.Fatalf("%q does not sort after %q", id, prev)
+		}
+		prev = id
+	}
+	p, err := Parse(prev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2025, 1, 1, 0, 0, 0, 100e6, time.UTC); !p.Time().Equal(want) {
+		t.Errorf("Time of the 100th id = %v, want %v", p.Time(), want)
+	}
+}
+
+func TestSetDefault(t *testing.T) {
+	run := func() []string {
+		restore := SetDefault(Deterministic(7))
+		defer restore()
+		return []string{Generate("usr"), NewID[invoice]().String(), Text()}
+	}
+	first, second := run(), run()
+	for i := range first {
+		if first[i] != second[i] {
+			t.Errorf("value %d differs between runs: %q and %q", i, first[i], second[i])
+		}
+	}
+	if Default() != Generator(std) {
+		t.Error("restore did not reinstate the previous default")
+	}
+	if a, b := Text(), Text(); a == b || len(a) != 26 {
+		t.Errorf("Text() after restore = %q, %q, want distinct 26-character strings", a, b)
+	}
+}
+
+func TestSetDefault_CustomGenerator(t *testing.T) {
+	restore := SetDefault(NewGenerator(Options{Format: FormatBase58, Checksum: true}))
+	defer restore()
+
+	id := Generate("usr")
+	if err := Validate(id, "usr"); err != nil {
+		t.Errorf("Validate(%q) with the base58 default = %v", id, err)
+	}
+	if _, err := Parse(id); err == nil {
+		t.Errorf("Parse(%q) accepted a base58 id as a ULID", id)
+	}
+	if _, err := ParseID[invoice](NewID[invoice]().String()); err != nil {
+		t.Errorf("NewID under a base58 default: %v", err)
+	}
+}
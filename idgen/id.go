@@ -0,0 +1,138 @@
+package idgen
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// Prefixer registers the prefix of a typed ID. Implement it on the type the
+// IDs belong to, with a value receiver:
+//
+//	type Invoice struct{ ID idgen.ID[Invoice] }
+//
+//	func (Invoice) IDPrefix() string { return "inv" }
+type Prefixer interface {
+	IDPrefix() string
+}
+
+// ID is a prefixed ID of a T, such as "inv_01j9..." for an Invoice. IDs of
+// different types do not mix, and parsing checks the prefix.
+//
+// ID implements encoding.TextMarshaler, so it round-trips through JSON and
+// form values as its string form, and sql.Scanner and driver.Valuer, so it
+// is stored as that string too. Read one from a URL with ParseID:
+//
+//	id, err := idgen.ParseID[Invoice](chi.URLParam(r, "id"))
+//
+// The zero ID is empty: it marshals to "" and is stored as NULL.
+type ID[T Prefixer] struct {
+	ulid ulid.ULID
+}
+
+// NewID generates a new ID of a T. Typed IDs are always ULIDs, taken from
+// the clock and randomness of the default generator when it is a built-in
+// one, so a Deterministic default makes them reproducible too.
+func NewID[T Prefixer]() ID[T] {
+	return ID[T]{ulid: newULID()}
+}
+
+// ParseID parses the string form of an ID of a T. It returns an error
+// wrapping ErrPrefixMismatch for IDs of another type.
+func ParseID[T Prefixer](s string) (ID[T], error) {
+	var id ID[T]
+	p, err := Parse(s)
+	if err != nil {
+		return id, err
+	}
+	if prefix := id.Prefix(); p.Prefix != prefix {
+		return id, fmt.Errorf("%w: %q is not a %q id", ErrPrefixMismatch, s, prefix)
+	}
+	id.ulid = p.ULID
+	return id, nil
+}
+
+// MustParseID is like ParseID but panics on invalid input. Use it for
+// constants in tests and fixtures.
+func MustParseID[T Prefixer](s string) ID[T] {
+	id, err := ParseID[T](s)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// Prefix returns the prefix registered by T.
+func (ID[T]) Prefix() string {
+	var t T
+	return t.IDPrefix()
+}
+
+// ULID returns the ULID part of the ID.
+func (id ID[T]) ULID() ulid.ULID {
+	return id.ulid
+}
+
+// Time returns the time the ID was generated, to the millisecond.
+func (id ID[T]) Time() time.Time {
+	return id.ulid.Timestamp()
+}
+
+// IsZero reports whether id is the zero ID.
+func (id ID[T]) IsZero() bool {
+	return id.ulid.IsZero()
+}
+
+// String returns the ID as prefix_ulid, or "" for the zero ID.
+func (id ID[T]) String() string {
+	if id.IsZero() {
+		return ""
+	}
+	return format(id.Prefix(), id.ulid)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (id ID[T]) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Empty text is the
+// zero ID.
+func (id *ID[T]) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*id = ID[T]{}
+		return nil
+	}
+	parsed, err := ParseID[T](string(text))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+// Scan implements sql.Scanner for string and []byte columns. NULL is the
+// zero ID.
+func (id *ID[T]) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*id = ID[T]{}
+		return nil
+	case string:
+		return id.UnmarshalText([]byte(v))
+	case []byte:
+		return id.UnmarshalText(v)
+	default:
+		return fmt.Errorf("idgen: cannot scan %T into %s id", src, id.Prefix())
+	}
+}
+
+// Value implements driver.Valuer. The zero ID is stored as NULL.
+func (id ID[T]) Value() (driver.Value, error) {
+	if id.IsZero() {
+		return nil, nil
+	}
+	return id.String(), nil
+}
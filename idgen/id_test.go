@@ -0,0 +1,117 @@
+package idgen
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type invoice struct{}
+
+func (invoice) IDPrefix() string { return "inv" }
+
+type user struct{}
+
+func (user) IDPrefix() string { return "usr" }
+
+func TestID_RoundTrip(t *testing.T) {
+	id := NewID[invoice]()
+	s := id.String()
+	if err := Validate(s, "inv"); err != nil {
+		t.Fatalf("NewID().String() = %q: %v", s, err)
+	}
+
+	parsed, err := ParseID[invoice](s)
+	if err != nil {
+		t.Fatalf("ParseID(%q): %v", s, err)
+	}
+	if parsed != id {
+		t.Errorf("ParseID(%q) = %v, want %v", s, parsed, id)
+	}
+	if parsed.Time() != id.ULID().Timestamp() {
+		t.Errorf("Time() = %v, want %v", parsed.Time(), id.ULID().Timestamp())
+	}
+}
+
+func TestParseID_WrongType(t *testing.T) {
+	s := NewID[user]().String()
+	if _, err := ParseID[invoice](s); !errors.Is(err, ErrPrefixMismatch) {
+		t.Errorf("ParseID[invoice](%q) error = %v, want ErrPrefixMismatch", s, err)
+	}
+}
+
+func TestID_JSON(t *testing.T) {
+	type doc struct {
+		ID     ID[invoice] `json:"id"`
+		Parent ID[invoice] `json:"parent"`
+	}
+	in := doc{ID: NewID[invoice]()}
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"id":"` + in.ID.String() + `","parent":""}`
+	if string(b) != want {
+		t.Errorf("Marshal = %s, want %s", b, want)
+	}
+
+	var out doc
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out != in {
+		t.Errorf("Unmarshal = %+v, want %+v", out, in)
+	}
+
+	var wrong struct {
+		ID ID[user] `json:"id"`
+	}
+	if err := json.Unmarshal(b, &wrong); !errors.Is(err, ErrPrefixMismatch) {
+		t.Errorf("Unmarshal into ID[user] error = %v, want ErrPrefixMismatch", err)
+	}
+}
+
+func TestID_SQL(t *testing.T) {
+	id := NewID[invoice]()
+	v, err := id.Value()
+	if err != nil || v != id.String() {
+		t.Fatalf("Value() = %v, %v, want %q", v, err, id.String())
+	}
+	if v, err := (ID[invoice]{}).Value(); v != nil || err != nil {
+		t.Errorf("zero Value() = %v, %v, want nil", v, err)
+	}
+
+	tests := []struct {
+		name    string
+		src     any
+		want    ID[invoice]
+		wantErr bool
+	}{
+		{"string", id.String(), id, false},
+		{"bytes", []byte(id.String()), id, false},
+		{"null", nil, ID[invoice]{}, false},
+		{"wrong prefix", NewID[user]().String(), ID[invoice]{}, true},
+		{"unsupported type", 42, ID[invoice]{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got ID[invoice]
+			err := got.Scan(tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Scan(%v) error = %v, wantErr %v", tt.src, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Scan(%v) = %v, want %v", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMustParseID_Panics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("MustParseID did not panic on an invalid id")
+		}
+	}()
+	MustParseID[invoice]("inv_nope")
+}
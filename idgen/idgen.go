@@ -2,35 +2,39 @@ package idgen
 
 import (
 	"crypto/rand"
+	"errors"
 	"fmt"
-	"io"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/oklog/ulid/v2"
 )
 
 var (
-	entropy   io.Reader
-	entropyMu sync.Mutex
+	// ErrInvalidID is returned for strings that are not a prefix and an ID
+	// body in the expected format, separated by an underscore.
+	ErrInvalidID = errors.New("idgen: invalid id")
+	// ErrPrefixMismatch is returned for IDs with another prefix than expected.
+	ErrPrefixMismatch = errors.New("idgen: prefix mismatch")
 )
 
-func init() {
-	entropy = ulid.Monotonic(rand.Reader, 0)
+// Parsed is an ID split into its parts by Parse.
+type Parsed struct {
+	Prefix string
+	ULID   ulid.ULID
 }
 
-// New generates a new ULID with the given prefix.
-// Format: {prefix}_{ulid_lower_case}
+// Time returns the time the ID was generated, to the millisecond.
+func (p Parsed) Time() time.Time {
+	return p.ULID.Timestamp()
+}
+
+// New returns a function generating IDs with the given prefix from the
+// default generator, see SetDefault.
+// Default format: {prefix}_{ulid_lower_case}
 func New(prefix string) func() string {
 	return func() string {
-		entropyMu.Lock()
-		id := ulid.MustNew(ulid.Timestamp(time.Now()), entropy)
-		entropyMu.Unlock()
-
-		// Format: prefix_ulid
-		// Using lowercase for ULID part for consistency with common prefixed ID standards (like Stripe)
-		return fmt.Sprintf("%s_%s", prefix, strings.ToLower(id.String()))
+		return Default().Generate(prefix)
 	}
 }
 
@@ -38,6 +42,45 @@ func Generate(prefix string) string {
 	return New(prefix)()
 }
 
+// Parse splits an ID in FormatULID, without a check character, into its
+// prefix and ULID. The prefix is
+// everything before the last underscore, so it may contain underscores
+// itself. The ULID is accepted in either case.
+func Parse(id string) (Parsed, error) {
+	i := strings.LastIndexByte(id, '_')
+	if i <= 0 {
+		return Parsed{}, fmt.Errorf("%w %q: missing prefix", ErrInvalidID, id)
+	}
+	u, err := ulid.ParseStrict(id[i+1:])
+	if err != nil {
+		return Parsed{}, fmt.Errorf("%w %q: %w", ErrInvalidID, id, err)
+	}
+	return Parsed{Prefix: id[:i], ULID: u}, nil
+}
+
+// Validate reports whether id is a valid ID of the default generator with
+// the given prefix, for checking IDs received in requests:
+//
+//	if err := idgen.Validate(chi.URLParam(r, "id"), "inv"); err != nil {
+//	    http.Error(w, "invalid invoice id", http.StatusBadRequest)
+//	    return
+//	}
+func Validate(id, prefix string) error {
+	return Default().Validate(id, prefix)
+}
+
+// newULID returns a ULID for the current time, monotonically increasing
+// within the same millisecond.
+func newULID() ulid.ULID {
+	return builtin().ulid()
+}
+
+// format returns the string form of an ID: prefix_ulid.
+// Using lowercase for ULID part for consistency with common prefixed ID standards (like Stripe)
+func format(prefix string, u ulid.ULID) string {
+	return prefix + "_" + strings.ToLower(u.String())
+}
+
 // Token generates a cryptographically secure random token of the specified length (in bytes).
 // It returns the hex-encoded string representation of the token.
 func Token(length int) (string, error) {
@@ -1,9 +1,11 @@
 package idgen
 
 import (
+	"errors"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
 func TestNew(t *testing.T) {
@@ -80,6 +82,65 @@ func TestMonotonicity(t *testing.T) {
 	}
 }
 
+func TestParse(t *testing.T) {
+	before := time.Now().Truncate(time.Millisecond)
+	id := Generate("user_account")
+
+	p, err := Parse(id)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", id, err)
+	}
+	if p.Prefix != "user_account" {
+		t.Errorf("Prefix = %q, want %q", p.Prefix, "user_account")
+	}
+	if got := format(p.Prefix, p.ULID); got != id {
+		t.Errorf("formatting the parts gives %q, want %q", got, id)
+	}
+	if ts := p.Time(); ts.Before(before) || ts.After(time.Now()) {
+		t.Errorf("Time() = %v, want between %v and now", ts, before)
+	}
+
+	upper, err := Parse("usr_" + strings.ToUpper(strings.TrimPrefix(Generate("usr"), "usr_")))
+	if err != nil || upper.Prefix != "usr" {
+		t.Errorf("Parse of an upper case ULID = %+v, %v", upper, err)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+	}{
+		{"empty", ""},
+		{"no separator", "01j9z3k4m5n6p7q8r9s0t1v2w3"},
+		{"empty prefix", "_01j9z3k4m5n6p7q8r9s0t1v2w3"},
+		{"empty ulid", "usr_"},
+		{"short ulid", "usr_01j9z3k4m5"},
+		{"invalid character", "usr_01j9z3k4m5n6p7q8r9s0t1v2wu"},
+		{"overflow", "usr_81j9z3k4m5n6p7q8r9s0t1v2w3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.id); !errors.Is(err, ErrInvalidID) {
+				t.Errorf("Parse(%q) error = %v, want ErrInvalidID", tt.id, err)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	id := Generate("inv")
+	if err := Validate(id, "inv"); err != nil {
+		t.Errorf("Validate(%q, inv) = %v", id, err)
+	}
+	if err := Validate(id, "usr"); !errors.Is(err, ErrPrefixMismatch) {
+		t.Errorf("Validate(%q, usr) = %v, want ErrPrefixMismatch", id, err)
+	}
+	if err := Validate("inv_nope", "inv"); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("Validate(inv_nope, inv) = %v, want ErrInvalidID", err)
+	}
+}
+
 func BenchmarkNew(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		Generate("bench")
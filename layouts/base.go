@@ -0,0 +1,36 @@
+package layouts
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/a-h/templ"
+	"github.com/plaenen/webx"
+)
+
+// Base is the HTML document shell. It renders its children before the head
+// so that every asset they declare with webx.RequireAsset is linked from the
+// head, deduplicated, alongside WebXContext.Stylesheets and Scripts. Set
+// BaseProps.Stream to write the head first instead.
+func Base(props BaseProps) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		body := templ.GetChildren(ctx)
+		if body == nil {
+			body = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		// Components and the layout must share one WebXContext for
+		// RequireAsset to reach the head, even without middleware.
+		ctx = webx.FromContext(ctx).WithContext(ctx)
+
+		if !props.Stream {
+			var buf bytes.Buffer
+			if err := body.Render(ctx, &buf); err != nil {
+				return err
+			}
+			body = templ.Raw(buf.String())
+		}
+		return baseDocument(props, body).Render(ctx, w)
+	})
+}
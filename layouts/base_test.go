@@ -0,0 +1,127 @@
+package layouts_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/plaenen/webx"
+	"github.com/plaenen/webx/capability"
+	"github.com/plaenen/webx/layouts"
+)
+
+// chart stands in for a component with its own assets.
+var chart = templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+	webx.RequireAsset(ctx, webx.Stylesheet{Href: "/chart.css"}, webx.Script{Src: "/chart.js"})
+	_, err := io.WriteString(w, `<div class="chart"></div>`)
+	return err
+})
+
+func render(ctx context.Context, t *testing.T, props layouts.BaseProps) string {
+	t.Helper()
+	var sb strings.Builder
+	page := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		for range 2 {
+			if err := chart.Render(ctx, w); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err := layouts.Base(props).Render(templ.WithChildren(ctx, page), &sb); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	return sb.String()
+}
+
+func TestBase_RequiredAssetsInHead(t *testing.T) {
+	wctx := &webx.WebXContext{Stylesheets: []webx.Stylesheet{{Href: "/app.css"}}}
+	html := render(wctx.WithContext(context.Background()), t, layouts.BaseProps{Title: "t"})
+
+	head, body, _ := strings.Cut(html, "</head>")
+	for _, tag := range []string{`href="/app.css"`, `href="/chart.css"`, `src="/chart.js"`} {
+		if n := strings.Count(head, tag); n != 1 {
+			t.Errorf("%s appears %d times in head, want 1", tag, n)
+		}
+		if strings.Contains(body, tag) {
+			t.Errorf("%s repeated in body", tag)
+		}
+	}
+	if strings.Count(body, `class="chart"`) != 2 {
+		t.Error("children not rendered")
+	}
+	if !strings.Contains(body, `id="webx-toasts"`) || !strings.Contains(body, `aria-live="polite"`) {
+		t.Error("toast outlet missing")
+	}
+}
+
+func TestBase_StreamAddsLateAssetsToBody(t *testing.T) {
+	html := render(context.Background(), t, layouts.BaseProps{Title: "t", Stream: true})
+
+	head, body, _ := strings.Cut(html, "</head>")
+	if strings.Contains(head, "/chart.css") {
+		t.Error("streamed head cannot know about assets required later")
+	}
+	if strings.Count(body, `href="/chart.css"`) != 1 || strings.Count(body, `src="/chart.js"`) != 1 {
+		t.Errorf("late assets not written once at the end of body:\n%s", body)
+	}
+	if strings.Index(body, `class="chart"`) > strings.Index(body, "/chart.css") {
+		t.Error("late assets should follow the content")
+	}
+}
+
+func TestBase_ScriptNonce(t *testing.T) {
+	html := render(context.Background(), t, layouts.BaseProps{Title: "t"})
+	if strings.Contains(html, "nonce=") {
+		t.Errorf("nonce attribute rendered without CSP:\n%s", html)
+	}
+
+	wctx := &webx.WebXContext{Nonce: "abc"}
+	html = render(wctx.WithContext(context.Background()), t, layouts.BaseProps{Title: "t"})
+	if !strings.Contains(html, `src="/chart.js" nonce="abc"`) {
+		t.Errorf("script lacks the request nonce:\n%s", html)
+	}
+}
+
+func TestDashboard_NavUsesRequestCapabilities(t *testing.T) {
+	props := layouts.DashboardProps{Nav: []layouts.NavGroup{{Items: []layouts.NavItem{
+		{Label: "Invoices", Href: "/invoices", Capability: "invoices:read"},
+		{Label: "Billing", Href: "/billing", Capability: "billing:read"},
+	}}}}
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		props layouts.DashboardProps
+		want  []string
+		hide  []string
+	}{
+		{"no capabilities", context.Background(), props, []string{"Invoices", "Billing"}, nil},
+		{"from context", capability.NewContext(context.Background(), capability.Set{"invoices:*"}), props, []string{"Invoices"}, []string{"Billing"}},
+		{"props win", capability.NewContext(context.Background(), capability.Set{"invoices:*"}), withCaps(props, capability.Set{"billing:read"}), []string{"Billing"}, []string{"Invoices"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := layouts.Dashboard(tt.props).Render(tt.ctx, &sb); err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(sb.String(), s) {
+					t.Errorf("missing nav item %s", s)
+				}
+			}
+			for _, s := range tt.hide {
+				if strings.Contains(sb.String(), s) {
+					t.Errorf("nav item %s should be hidden", s)
+				}
+			}
+		})
+	}
+}
+
+func withCaps(props layouts.DashboardProps, caps capability.Set) layouts.DashboardProps {
+	props.Capabilities = caps
+	return props
+}
@@ -0,0 +1,160 @@
+package layouts
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/plaenen/webx"
+	"github.com/starfederation/datastar-go/datastar"
+)
+
+// SessionsRevokePath is the standard handler path for session revocation.
+// Mount it under your app's base path: basePath + SessionsRevokePath.
+const SessionsRevokePath = "/api/sessions/revoke"
+
+// SessionsRevokeHandler returns an http.HandlerFunc that revokes sessions of
+// the user linked to the current session and re-renders the Sessions
+// component identified by the "id" query parameter.
+//
+// The "session" query parameter selects one session by its handle; "all=1"
+// revokes every session except the caller's own. The session store must
+// implement webx.SessionLister.
+//
+//	r.Post(layouts.SessionsRevokePath, layouts.SessionsRevokeHandler())
+func SessionsRevokeHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		componentID := r.URL.Query().Get("id")
+		if componentID == "" {
+			http.Error(w, "missing id query parameter", http.StatusBadRequest)
+			return
+		}
+		handle := r.URL.Query().Get("session")
+		all := r.URL.Query().Get("all") == "1"
+		if handle == "" && !all {
+			http.Error(w, "missing session or all query parameter", http.StatusBadRequest)
+			return
+		}
+
+		wctx := webx.FromContext(r.Context())
+		lister, ok := wctx.SessionStore().(webx.SessionLister)
+		if !ok {
+			http.Error(w, webx.ErrSessionsNotListable.Error(), http.StatusNotImplemented)
+			return
+		}
+		userID, err := webx.SessionUser(r)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("session store error: %v", err), http.StatusInternalServerError)
+			return
+		}
+		if userID == "" {
+			http.Error(w, "not signed in", http.StatusUnauthorized)
+			return
+		}
+
+		var handles []string
+		if !all {
+			handles = []string{handle}
+		}
+		keep := []string{wctx.SessionID}
+		if _, err := webx.RevokeSessions(lister, userID, keep, handles...); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		sessions, err := lister.ListSessions(userID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		sse := datastar.NewSSE(w, r)
+		sse.PatchElementTempl(Sessions(SessionsProps{ID: componentID, Sessions: sessions}))
+	}
+}
+
+func (p SessionsProps) sessionsID() string {
+	if p.ID == "" {
+		return "sessions"
+	}
+	return p.ID
+}
+
+// withQuery adds params to the query string of rawURL, keeping any it
+// already has.
+func withQuery(rawURL string, params url.Values) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL + "?" + params.Encode()
+	}
+	q := u.Query()
+	for k, v := range params {
+		q[k] = v
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// describeUserAgent reduces a User-Agent header to "Browser on OS".
+func describeUserAgent(ua string) string {
+	if ua == "" {
+		return "Unknown device"
+	}
+	browser := "Unknown browser"
+	for _, b := range []struct{ token, name string }{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"curl/", "curl"},
+	} {
+		if strings.Contains(ua, b.token) {
+			browser = b.name
+			break
+		}
+	}
+	os := ""
+	for _, o := range []struct{ token, name string }{
+		{"iPhone", "iOS"},
+		{"iPad", "iPadOS"},
+		{"Android", "Android"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"CrOS", "ChromeOS"},
+		{"Linux", "Linux"},
+	} {
+		if strings.Contains(ua, o.token) {
+			os = o.name
+			break
+		}
+	}
+	if os == "" {
+		return browser
+	}
+	return browser + " on " + os
+}
+
+// relativeTime formats t relative to now ("just now", "5 minutes ago").
+func relativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return plural(int(d/time.Minute), "minute") + " ago"
+	case d < 24*time.Hour:
+		return plural(int(d/time.Hour), "hour") + " ago"
+	default:
+		return plural(int(d/(24*time.Hour)), "day") + " ago"
+	}
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
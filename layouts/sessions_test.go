@@ -0,0 +1,261 @@
+package layouts_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/plaenen/webx"
+	"github.com/plaenen/webx/layouts"
+	"github.com/plaenen/webx/session"
+	"github.com/plaenen/webx/webxtest"
+)
+
+// signIn links the test session to userID.
+func signIn(userID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := webx.SetSessionUser(r, userID); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+// login opens another session in store for userID and returns its ID.
+func login(t *testing.T, store webx.SessionStore, userID, userAgent string) string {
+	t.Helper()
+	var id string
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("User-Agent", userAgent)
+	webx.SessionMiddleware(store)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := webx.SetSessionUser(r, userID); err != nil {
+			t.Fatalf("SetSessionUser: %v", err)
+		}
+		id = webx.FromContext(r.Context()).SessionID
+	})).ServeHTTP(httptest.NewRecorder(), req)
+	return id
+}
+
+func handleOf(t *testing.T, store webx.SessionLister, userID, id string) string {
+	t.Helper()
+	sessions, err := store.ListSessions(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range sessions {
+		if s.ID == id {
+			return s.Handle()
+		}
+	}
+	t.Fatalf("session %s of %s not found", id, userID)
+	return ""
+}
+
+func sessionIDs(t *testing.T, store webx.SessionLister, userID string) []string {
+	t.Helper()
+	sessions, err := store.ListSessions(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, s := range sessions {
+		ids = append(ids, s.ID)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// unlistable hides the ListSessions method of the store it wraps.
+type unlistable struct{ webx.SessionStore }
+
+func TestSessionsRevokeHandler_BadRequests(t *testing.T) {
+	h := layouts.SessionsRevokeHandler()
+	sess := webxtest.NewSession(t)
+	sess.Serve(t, signIn("alice"), webxtest.Request{})
+
+	tests := []struct {
+		name   string
+		target string
+		want   int
+	}{
+		{"missing id", "/?session=abc", http.StatusBadRequest},
+		{"missing session and all", "/?id=sessions", http.StatusBadRequest},
+		{"all must be 1", "/?id=sessions&all=true", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := sess.Serve(t, h, webxtest.Request{Method: http.MethodPost, Target: tt.target})
+			if res.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", res.Code, tt.want, res.Body)
+			}
+		})
+	}
+}
+
+func TestSessionsRevokeHandler_NotListable(t *testing.T) {
+	store := session.NewMemoryStore(session.MemoryStoreOptions{SweepInterval: -1})
+	t.Cleanup(func() { store.Close() })
+	id := login(t, store, "alice", "Firefox")
+
+	// A GET request skips the CSRF checks, which need a token from the
+	// session; the handler does not look at the method.
+	req := webxtest.NewRequest(t, webxtest.Request{Target: "/?id=sessions&all=1"})
+	req.AddCookie(&http.Cookie{Name: "webx_session", Value: id})
+	rec := httptest.NewRecorder()
+	webx.SessionMiddleware(unlistable{store})(layouts.SessionsRevokeHandler()).ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotImplemented {
+		t.Errorf("status = %d, want %d: %s", rec.Code, http.StatusNotImplemented, rec.Body)
+	}
+}
+
+func TestSessionsRevokeHandler_NotSignedIn(t *testing.T) {
+	sess := webxtest.NewSession(t)
+	other := login(t, sess.Store, "alice", "Firefox")
+
+	res := sess.Serve(t, layouts.SessionsRevokeHandler(), webxtest.Request{
+		Method: http.MethodPost,
+		Target: "/?id=sessions&session=" + handleOf(t, sess.Store, "alice", other),
+	})
+	if res.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", res.Code, http.StatusUnauthorized)
+	}
+	if got := sessionIDs(t, sess.Store, "alice"); len(got) != 1 {
+		t.Errorf("alice's sessions = %v, want the one untouched", got)
+	}
+}
+
+func TestSessionsRevokeHandler_RevokeOne(t *testing.T) {
+	sess := webxtest.NewSession(t)
+	sess.Serve(t, signIn("alice"), webxtest.Request{})
+	phone := login(t, sess.Store, "alice", "Mozilla/5.0 (iPhone) Safari/605.1")
+	laptop := login(t, sess.Store, "alice", "Mozilla/5.0 (X11; Linux) Firefox/130.0")
+
+	res := sess.Serve(t, layouts.SessionsRevokeHandler(), webxtest.Request{
+		Method: http.MethodPost,
+		Target: "/?id=my-sessions&session=" + handleOf(t, sess.Store, "alice", phone),
+	})
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", res.Code, res.Body)
+	}
+	want := []string{laptop, sess.ID}
+	slices.Sort(want)
+	if got := sessionIDs(t, sess.Store, "alice"); !slices.Equal(got, want) {
+		t.Errorf("alice's sessions = %v, want %v", got, want)
+	}
+
+	elements := res.Elements()
+	if len(elements) != 1 {
+		t.Fatalf("got %d element patches, want 1", len(elements))
+	}
+	html := elements[0].Elements
+	if !strings.Contains(html, `id="my-sessions"`) || !strings.Contains(html, "Firefox on Linux") || strings.Contains(html, "iOS") {
+		t.Errorf("re-rendered sessions do not match the store:\n%s", html)
+	}
+}
+
+func TestSessionsRevokeHandler_OtherUsersSession(t *testing.T) {
+	sess := webxtest.NewSession(t)
+	sess.Serve(t, signIn("alice"), webxtest.Request{})
+	bob := login(t, sess.Store, "bob", "Chrome")
+
+	res := sess.Serve(t, layouts.SessionsRevokeHandler(), webxtest.Request{
+		Method: http.MethodPost,
+		Target: "/?id=sessions&session=" + handleOf(t, sess.Store, "bob", bob),
+	})
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", res.Code, res.Body)
+	}
+	if got := sessionIDs(t, sess.Store, "bob"); !slices.Equal(got, []string{bob}) {
+		t.Errorf("bob's sessions = %v, want %v: alice revoked them", got, []string{bob})
+	}
+}
+
+func TestSessionsRevokeHandler_AllButCurrent(t *testing.T) {
+	sess := webxtest.NewSession(t)
+	sess.Serve(t, signIn("alice"), webxtest.Request{})
+	login(t, sess.Store, "alice", "Firefox")
+	login(t, sess.Store, "alice", "Chrome")
+	bob := login(t, sess.Store, "bob", "Chrome")
+
+	res := sess.Serve(t, layouts.SessionsRevokeHandler(), webxtest.Request{
+		Method: http.MethodPost,
+		Target: "/?id=sessions&all=1",
+	})
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", res.Code, res.Body)
+	}
+	if got := sessionIDs(t, sess.Store, "alice"); !slices.Equal(got, []string{sess.ID}) {
+		t.Errorf("alice's sessions = %v, want only the current one %s", got, sess.ID)
+	}
+	if got := sessionIDs(t, sess.Store, "bob"); !slices.Equal(got, []string{bob}) {
+		t.Errorf("bob's sessions = %v, want untouched", got)
+	}
+	if html := res.Elements()[0].Elements; strings.Contains(html, "Sign out all other sessions") {
+		t.Errorf("only the current session is left, but revoke-all is still offered:\n%s", html)
+	}
+}
+
+func TestSessions_Render(t *testing.T) {
+	now := time.Now()
+	current := webx.SessionInfo{ID: "current", UserID: "alice", Created: now.Add(-48 * time.Hour), LastSeen: now, UserAgent: "Mozilla/5.0 (Macintosh; Mac OS X 14_0) Safari/605.1"}
+	other := webx.SessionInfo{ID: "other", UserID: "alice", Created: now.Add(-72 * time.Hour), LastSeen: now.Add(-3 * time.Hour), UserAgent: "Mozilla/5.0 (Windows NT 10.0) Chrome/130.0"}
+	wctx := &webx.WebXContext{SessionID: "current", BasePath: "/app"}
+	ctx := wctx.WithContext(context.Background())
+
+	tests := []struct {
+		name  string
+		props layouts.SessionsProps
+		want  []string
+		hide  []string
+	}{
+		{
+			name:  "empty",
+			props: layouts.SessionsProps{},
+			want:  []string{`id="sessions"`, "No active sessions."},
+			hide:  []string{"Sign out all other sessions"},
+		},
+		{
+			name:  "only current",
+			props: layouts.SessionsProps{Sessions: []webx.SessionInfo{current}},
+			want:  []string{"Safari on macOS", "This device", "just now"},
+			hide:  []string{"Sign out all other sessions", "Revoke"},
+		},
+		{
+			name:  "with others",
+			props: layouts.SessionsProps{ID: "devices", Sessions: []webx.SessionInfo{current, other}},
+			want: []string{
+				`id="devices"`, "Chrome on Windows", "3 hours ago", "Sign out all other sessions",
+				"/app/api/sessions/revoke?all=1&amp;id=devices",
+				"/app/api/sessions/revoke?id=devices&amp;session=" + other.Handle(),
+			},
+			hide: []string{"session=current", "session=other&"},
+		},
+		{
+			name:  "custom revoke URL",
+			props: layouts.SessionsProps{Sessions: []webx.SessionInfo{current, other}, RevokeURL: "/revoke?tenant=a b"},
+			want:  []string{"/revoke?all=1&amp;id=sessions&amp;tenant=a+b", "/revoke?id=sessions&amp;session=" + other.Handle() + "&amp;tenant=a+b"},
+			hide:  []string{"/app/api/sessions/revoke"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := layouts.Sessions(tt.props).Render(ctx, &sb); err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(sb.String(), s) {
+					t.Errorf("missing %s in\n%s", s, sb.String())
+				}
+			}
+			for _, s := range tt.hide {
+				if strings.Contains(sb.String(), s) {
+					t.Errorf("unexpected %s in\n%s", s, sb.String())
+				}
+			}
+		})
+	}
+}
@@ -2,7 +2,6 @@ package webx
 
 import (
 	"crypto/rand"
-	"crypto/subtle"
 	"encoding/hex"
 	"fmt"
 	"net/http"
@@ -15,19 +14,53 @@ const (
 
 // SessionMiddleware reads or creates a session cookie, then populates
 // WebXContext with the session ID and CSRF token from the store.
-func SessionMiddleware(store SessionStore) func(http.Handler) http.Handler {
+//
+// An optional SessionOptions configures the cookie and the session lifetime.
+// Sessions past their TTL or idle timeout, and IDs the store has never seen,
+// are discarded and replaced with a fresh session. Mutating requests must
+// pass the checks configured in SessionOptions.CSRF.
+func SessionMiddleware(backend SessionStore, opts ...SessionOptions) func(http.Handler) http.Handler {
+	cfg := resolveSessionOptions(opts)
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			sessionID, isNew := sessionIDFromRequest(r)
+			store := backend
+			if rs, ok := backend.(RequestSessionStore); ok {
+				var done func()
+				store, w, done = rs.Bind(w, r)
+				defer done()
+			}
+
+			now := timeNow()
+			sessionID, isNew := sessionIDFromRequest(r, cfg.CookieName)
+
+			if !isNew {
+				created, lastSeen, ok, err := loadSessionTimes(store, sessionID)
+				if err != nil {
+					http.Error(w, fmt.Sprintf("session store error: %v", err), http.StatusInternalServerError)
+					return
+				}
+				at := cfg.deadline(created, lastSeen)
+				switch {
+				case !ok || created.IsZero() || (!at.IsZero() && !now.Before(at)):
+					if err := store.Delete(sessionID); err != nil {
+						http.Error(w, fmt.Sprintf("session store error: %v", err), http.StatusInternalServerError)
+						return
+					}
+					sessionID, isNew = newSessionID(), true
+				default:
+					if err := touchSession(store, cfg, sessionID, created, lastSeen, now); err != nil {
+						http.Error(w, fmt.Sprintf("session store error: %v", err), http.StatusInternalServerError)
+						return
+					}
+				}
+			}
 
 			if isNew {
-				http.SetCookie(w, &http.Cookie{
-					Name:     sessionCookieName,
-					Value:    sessionID,
-					Path:     "/",
-					HttpOnly: true,
-					SameSite: http.SameSiteLaxMode,
-				})
+				if err := startSession(store, cfg, sessionID, r.UserAgent(), now, now); err != nil {
+					http.Error(w, fmt.Sprintf("session store error: %v", err), http.StatusInternalServerError)
+					return
+				}
+				http.SetCookie(w, cfg.cookie(sessionID))
 			}
 
 			token, err := store.Get(sessionID, csrfSessionKey)
@@ -48,11 +81,10 @@ func SessionMiddleware(store SessionStore) func(http.Handler) http.Handler {
 				}
 			}
 
-			// Validate CSRF token on mutating requests.
-			if r.Method != http.MethodGet && r.Method != http.MethodHead && r.Method != http.MethodOptions {
-				headerToken := r.Header.Get("X-CSRF-Token")
-				if subtle.ConstantTimeCompare([]byte(headerToken), []byte(token)) != 1 {
-					http.Error(w, "invalid or missing CSRF token", http.StatusForbidden)
+			// Validate CSRF defences on mutating requests.
+			if isMutating(r) {
+				if reason := cfg.CSRF.check(r, token); reason != "" {
+					cfg.CSRF.forbid(w, reason)
 					return
 				}
 			}
@@ -60,6 +92,7 @@ func SessionMiddleware(store SessionStore) func(http.Handler) http.Handler {
 			wctx := FromContext(r.Context())
 			wctx.SessionID = sessionID
 			wctx.CSRFToken = token
+			wctx.session = &sessionState{store: store, opts: cfg}
 
 			next.ServeHTTP(w, r.WithContext(wctx.WithContext(r.Context())))
 		})
@@ -68,19 +101,24 @@ func SessionMiddleware(store SessionStore) func(http.Handler) http.Handler {
 
 // sessionIDFromRequest returns the session ID from the cookie, or generates a
 // new one. The bool indicates whether the ID is new.
-func sessionIDFromRequest(r *http.Request) (string, bool) {
-	if c, err := r.Cookie(sessionCookieName); err == nil && c.Value != "" {
+func sessionIDFromRequest(r *http.Request, cookieName string) (string, bool) {
+	if c, err := r.Cookie(cookieName); err == nil && c.Value != "" {
 		return c.Value, false
 	}
+	return newSessionID(), true
+}
+
+func newSessionID() string {
 	id, err := randomHex(16)
 	if err != nil {
 		// Extremely unlikely; fall back to a zero-value ID that will still work.
-		return "0000000000000000", true
+		return "0000000000000000"
 	}
-	return id, true
+	return id
 }
 
-// SecurityHeadersMiddleware sets common security response headers.
+// SecurityHeadersMiddleware sets common security response headers. Pair it
+// with CSPMiddleware for a Content-Security-Policy.
 func SecurityHeadersMiddleware() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
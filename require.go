@@ -0,0 +1,79 @@
+package webx
+
+import (
+	"context"
+	"sync"
+)
+
+// Asset is a stylesheet or script that a component depends on. Stylesheet
+// and Script implement it.
+type Asset interface {
+	assetKey() string
+}
+
+func (s Stylesheet) assetKey() string { return "css:" + s.Href }
+func (s Script) assetKey() string     { return "js:" + s.Src }
+
+// assetRegistry collects the assets required while a page renders.
+type assetRegistry struct {
+	mu      sync.Mutex
+	emitted map[string]bool
+	pending []Asset
+}
+
+// RequireAsset declares that the component being rendered needs assets.
+// Call it from a templ code block:
+//
+//	{{ webx.RequireAsset(ctx, webx.Stylesheet{Href: "/assets/css/highlight.css"}) }}
+//
+// layouts.Base writes each asset once per page, in the head when it is
+// known before the head is written and at the end of the body otherwise
+// (when rendering with BaseProps.Stream). Requiring an asset already listed
+// in WebXContext.Stylesheets or Scripts is a no-op.
+func RequireAsset(ctx context.Context, assets ...Asset) {
+	r := &FromContext(ctx).required
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pending = append(r.pending, assets...)
+}
+
+// TakeAssets returns the stylesheets and scripts that have not been written
+// yet, deduplicated, and marks them as written. The first call includes
+// WebXContext.Stylesheets and Scripts ahead of required assets. Layouts call
+// it once for the head and again at the end of the body to pick up assets
+// required after the head was flushed.
+func (wctx *WebXContext) TakeAssets() ([]Stylesheet, []Script) {
+	r := &wctx.required
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.emitted == nil {
+		r.emitted = map[string]bool{}
+	}
+
+	candidates := make([]Asset, 0, len(wctx.Stylesheets)+len(wctx.Scripts)+len(r.pending))
+	for _, s := range wctx.Stylesheets {
+		candidates = append(candidates, s)
+	}
+	for _, s := range wctx.Scripts {
+		candidates = append(candidates, s)
+	}
+	candidates = append(candidates, r.pending...)
+	r.pending = nil
+
+	var stylesheets []Stylesheet
+	var scripts []Script
+	for _, a := range candidates {
+		key := a.assetKey()
+		if r.emitted[key] {
+			continue
+		}
+		r.emitted[key] = true
+		switch a := a.(type) {
+		case Stylesheet:
+			stylesheets = append(stylesheets, a)
+		case Script:
+			scripts = append(scripts, a)
+		}
+	}
+	return stylesheets, scripts
+}
@@ -0,0 +1,36 @@
+package webx
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestTakeAssets(t *testing.T) {
+	wctx := &WebXContext{
+		Stylesheets: []Stylesheet{{Href: "/app.css"}},
+		Scripts:     []Script{{Src: "/datastar.js"}},
+	}
+	ctx := wctx.WithContext(context.Background())
+
+	RequireAsset(ctx, Stylesheet{Href: "/chart.css"}, Script{Src: "/chart.js"}, Stylesheet{Href: "/app.css"})
+	RequireAsset(ctx, Stylesheet{Href: "/chart.css"})
+
+	css, js := wctx.TakeAssets()
+	if want := []Stylesheet{{Href: "/app.css"}, {Href: "/chart.css"}}; !reflect.DeepEqual(css, want) {
+		t.Errorf("stylesheets = %v, want %v", css, want)
+	}
+	if want := []Script{{Src: "/datastar.js"}, {Src: "/chart.js"}}; !reflect.DeepEqual(js, want) {
+		t.Errorf("scripts = %v, want %v", js, want)
+	}
+
+	RequireAsset(ctx, Script{Src: "/chart.js"}, Script{Src: "/late.js", Type: "text/javascript"})
+	css, js = wctx.TakeAssets()
+	if len(css) != 0 || !reflect.DeepEqual(js, []Script{{Src: "/late.js", Type: "text/javascript"}}) {
+		t.Errorf("second take = %v %v, want only /late.js", css, js)
+	}
+
+	if css, js := wctx.TakeAssets(); len(css)+len(js) != 0 {
+		t.Errorf("third take = %v %v, want nothing", css, js)
+	}
+}
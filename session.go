@@ -1,9 +1,292 @@
 package webx
 
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
 // SessionStore provides session-scoped key/value storage.
 // Implementations can back this with memory, NATS KV, Redis, etc.
+// The session package ships memory, file, Redis and cookie stores.
 type SessionStore interface {
 	Get(sessionID string, key string) (string, error)
 	Set(sessionID string, key string, value string) error
 	Delete(sessionID string) error
 }
+
+// ExpiringSessionStore is implemented by stores that can evict sessions on
+// their own. SessionMiddleware calls Expire whenever a session's deadline
+// moves (on creation and on every request when an idle timeout is set).
+// After the deadline a store should behave as if the session was deleted.
+type ExpiringSessionStore interface {
+	SessionStore
+	Expire(sessionID string, at time.Time) error
+}
+
+// RotatingSessionStore is implemented by stores that can move every key of a
+// session to a new ID in one step. RotateSession uses it when available;
+// otherwise the old session is deleted and a fresh, empty one is started.
+type RotatingSessionStore interface {
+	SessionStore
+	Rotate(oldID, newID string) error
+}
+
+// RequestSessionStore is implemented by stores whose data travels with the
+// request itself, such as cookie-backed stores. SessionMiddleware calls Bind
+// once per request and uses the returned store and writer for the rest of
+// that request, so the store can read incoming state from r and write
+// updated state to the response before its headers are sent. The returned
+// done func runs after the handler, for responses that never wrote a byte.
+type RequestSessionStore interface {
+	SessionStore
+	Bind(w http.ResponseWriter, r *http.Request) (store SessionStore, bw http.ResponseWriter, done func())
+}
+
+// Keys managed by SessionMiddleware inside every session. Stores that
+// implement SessionLister read them to build SessionInfo.
+const (
+	SessionCreatedKey   = "session_created"
+	SessionLastSeenKey  = "session_last_seen"
+	SessionUserAgentKey = "session_user_agent"
+)
+
+// lastSeenResolution is how stale the recorded last-seen time may get before
+// SessionMiddleware writes it again when no idle timeout forces a write.
+const lastSeenResolution = time.Minute
+
+// timeNow is swapped out in tests.
+var timeNow = time.Now
+
+// ErrNoSession is returned by RotateSession when the request did not pass
+// through SessionMiddleware.
+var ErrNoSession = errors.New("webx: no session on request context")
+
+// SessionOptions configures the session cookie and session lifetime.
+// The zero value keeps the historic behaviour: a "webx_session" cookie with
+// SameSite=Lax that never expires.
+type SessionOptions struct {
+	// CookieName defaults to "webx_session".
+	CookieName string
+	// Path defaults to "/".
+	Path string
+	// Domain is left empty (host-only cookie) by default.
+	Domain string
+	// Secure marks the cookie as HTTPS-only. Enable it in production.
+	Secure bool
+	// SameSite defaults to http.SameSiteLaxMode.
+	SameSite http.SameSite
+
+	// TTL is the absolute lifetime of a session, measured from creation.
+	// Zero means sessions never expire on age alone.
+	TTL time.Duration
+	// IdleTimeout expires a session that has not seen a request for this
+	// long. Zero disables the idle check.
+	IdleTimeout time.Duration
+
+	// CSRF configures the checks run on mutating requests.
+	CSRF CSRFOptions
+}
+
+func resolveSessionOptions(opts []SessionOptions) SessionOptions {
+	var o SessionOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if o.CookieName == "" {
+		o.CookieName = sessionCookieName
+	}
+	if o.Path == "" {
+		o.Path = "/"
+	}
+	if o.SameSite == 0 {
+		o.SameSite = http.SameSiteLaxMode
+	}
+	return o
+}
+
+// cookie builds the session cookie for the given ID.
+func (o SessionOptions) cookie(sessionID string) *http.Cookie {
+	c := &http.Cookie{
+		Name:     o.CookieName,
+		Value:    sessionID,
+		Path:     o.Path,
+		Domain:   o.Domain,
+		Secure:   o.Secure,
+		HttpOnly: true,
+		SameSite: o.SameSite,
+	}
+	if o.TTL > 0 {
+		c.MaxAge = int(o.TTL / time.Second)
+	}
+	return c
+}
+
+// deadline returns the moment a session created at created and last seen at
+// lastSeen expires, or the zero time if it never does.
+func (o SessionOptions) deadline(created, lastSeen time.Time) time.Time {
+	var at time.Time
+	if o.TTL > 0 {
+		at = created.Add(o.TTL)
+	}
+	if o.IdleTimeout > 0 {
+		idle := lastSeen.Add(o.IdleTimeout)
+		if at.IsZero() || idle.Before(at) {
+			at = idle
+		}
+	}
+	return at
+}
+
+// sessionState is attached to WebXContext by SessionMiddleware so that
+// RotateSession can reach the store and cookie settings.
+type sessionState struct {
+	store SessionStore
+	opts  SessionOptions
+}
+
+// SessionStore returns the store SessionMiddleware used for this request, or
+// nil outside the middleware. Prefer it over the store passed to the
+// middleware: for a RequestSessionStore it is the request-bound view.
+func (wctx *WebXContext) SessionStore() SessionStore {
+	if wctx.session == nil {
+		return nil
+	}
+	return wctx.session.store
+}
+
+// RotateSession re-keys the current session and issues a fresh CSRF token.
+// Call it after every privilege change (login, logout, role switch) so that
+// a session ID planted before the change is worthless afterwards.
+//
+// The new cookie is written to w and WebXContext is updated in place, so
+// anything rendered afterwards in the same request uses the new token. SSE
+// handlers must patch <meta name="csrf-token"> themselves.
+func RotateSession(w http.ResponseWriter, r *http.Request) error {
+	wctx := FromContext(r.Context())
+	state := wctx.session
+	if state == nil || wctx.SessionID == "" {
+		return ErrNoSession
+	}
+	store := state.store
+	oldID := wctx.SessionID
+	now := timeNow()
+
+	// The new ID inherits the creation time, so rotating cannot extend the
+	// absolute lifetime of the session.
+	created, _, ok, err := loadSessionTimes(store, oldID)
+	if err != nil {
+		return fmt.Errorf("loading session times: %w", err)
+	}
+	if !ok {
+		created = now
+	}
+
+	newID, err := randomHex(16)
+	if err != nil {
+		return fmt.Errorf("generating session ID: %w", err)
+	}
+
+	if rs, ok := store.(RotatingSessionStore); ok {
+		if err := rs.Rotate(oldID, newID); err != nil {
+			return fmt.Errorf("rotating session: %w", err)
+		}
+	} else if err := store.Delete(oldID); err != nil {
+		return fmt.Errorf("deleting old session: %w", err)
+	}
+
+	token, err := randomHex(16)
+	if err != nil {
+		return fmt.Errorf("generating CSRF token: %w", err)
+	}
+	if err := startSession(store, state.opts, newID, r.UserAgent(), created, now); err != nil {
+		return err
+	}
+	if err := store.Set(newID, csrfSessionKey, token); err != nil {
+		return fmt.Errorf("storing CSRF token: %w", err)
+	}
+
+	http.SetCookie(w, state.opts.cookie(newID))
+	wctx.SessionID = newID
+	wctx.CSRFToken = token
+	return nil
+}
+
+// startSession records the creation time, last-seen time and user agent of
+// a new or rotated session and schedules its eviction on stores that
+// support it.
+func startSession(store SessionStore, opts SessionOptions, sessionID, userAgent string, created, now time.Time) error {
+	if err := store.Set(sessionID, SessionCreatedKey, strconv.FormatInt(created.Unix(), 10)); err != nil {
+		return fmt.Errorf("storing session creation time: %w", err)
+	}
+	if err := store.Set(sessionID, SessionLastSeenKey, strconv.FormatInt(now.Unix(), 10)); err != nil {
+		return fmt.Errorf("storing session last-seen time: %w", err)
+	}
+	if err := store.Set(sessionID, SessionUserAgentKey, userAgent); err != nil {
+		return fmt.Errorf("storing session user agent: %w", err)
+	}
+	return expireSession(store, opts, sessionID, created, now)
+}
+
+// touchSession records activity on an existing session and pushes back its
+// idle deadline. Without an idle timeout the last-seen time is only written
+// once it is more than lastSeenResolution old.
+func touchSession(store SessionStore, opts SessionOptions, sessionID string, created, lastSeen, now time.Time) error {
+	if opts.IdleTimeout <= 0 && now.Sub(lastSeen) < lastSeenResolution {
+		return nil
+	}
+	if err := store.Set(sessionID, SessionLastSeenKey, strconv.FormatInt(now.Unix(), 10)); err != nil {
+		return fmt.Errorf("storing session last-seen time: %w", err)
+	}
+	if opts.IdleTimeout <= 0 {
+		return nil
+	}
+	return expireSession(store, opts, sessionID, created, now)
+}
+
+func expireSession(store SessionStore, opts SessionOptions, sessionID string, created, lastSeen time.Time) error {
+	es, ok := store.(ExpiringSessionStore)
+	if !ok {
+		return nil
+	}
+	at := opts.deadline(created, lastSeen)
+	if at.IsZero() {
+		return nil
+	}
+	if err := es.Expire(sessionID, at); err != nil {
+		return fmt.Errorf("scheduling session expiry: %w", err)
+	}
+	return nil
+}
+
+// loadSessionTimes reads the creation and last-seen timestamps of a session.
+// ok is false when the store has no record of the session.
+func loadSessionTimes(store SessionStore, sessionID string) (created, lastSeen time.Time, ok bool, err error) {
+	c, err := store.Get(sessionID, SessionCreatedKey)
+	if err != nil {
+		return time.Time{}, time.Time{}, false, err
+	}
+	if c == "" {
+		return time.Time{}, time.Time{}, false, nil
+	}
+	l, err := store.Get(sessionID, SessionLastSeenKey)
+	if err != nil {
+		return time.Time{}, time.Time{}, false, err
+	}
+	created = parseUnix(c)
+	lastSeen = parseUnix(l)
+	if lastSeen.IsZero() {
+		lastSeen = created
+	}
+	return created, lastSeen, true, nil
+}
+
+func parseUnix(s string) time.Time {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(n, 0)
+}
@@ -0,0 +1,373 @@
+// Package session provides webx.SessionStore implementations.
+package session
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/plaenen/webx"
+)
+
+const (
+	defaultCookieName    = "webx_session_data"
+	defaultCookieMaxSize = 4096
+	minCookieKeyLen      = 32
+)
+
+// ErrCookieTooLarge is returned by CookieStore.Set when the encoded session
+// would exceed the configured MaxSize.
+var ErrCookieTooLarge = errors.New("session: encoded cookie exceeds size limit")
+
+// CookieStoreOptions configures a CookieStore.
+type CookieStoreOptions struct {
+	// Keys are secrets of at least 32 bytes. Keys[0] encodes new cookies;
+	// every key is tried when decoding, so rotate by prepending a new key
+	// and dropping the oldest once its cookies have expired.
+	Keys [][]byte
+	// CookieName defaults to "webx_session_data".
+	CookieName string
+	// MaxSize is the maximum length of the encoded cookie value in bytes.
+	// Browsers reject cookies above ~4KB. Defaults to 4096.
+	MaxSize int
+	// Path defaults to "/".
+	Path string
+	// Domain is left empty (host-only cookie) by default.
+	Domain string
+	// Secure marks the cookie as HTTPS-only. Enable it in production.
+	Secure bool
+	// SameSite defaults to http.SameSiteLaxMode.
+	SameSite http.SameSite
+}
+
+// CookieStore is a stateless webx.SessionStore that keeps each session's
+// key/value map in an AES-GCM encrypted, HMAC-SHA256 signed cookie. Any
+// replica holding the same keys can serve any request.
+//
+// Session data is written to the response when its headers are sent. Datastar
+// SSE handlers send headers in datastar.NewSSE, so set session values before
+// opening the stream.
+type CookieStore struct {
+	opts CookieStoreOptions
+	keys []cookieKey
+}
+
+// Compile-time check that CookieStore plugs into webx.SessionMiddleware.
+var _ webx.RequestSessionStore = (*CookieStore)(nil)
+
+type cookieKey struct {
+	block cipher.AEAD
+	hash  []byte
+}
+
+// NewCookieStore returns a CookieStore for the given options.
+func NewCookieStore(opts CookieStoreOptions) (*CookieStore, error) {
+	if len(opts.Keys) == 0 {
+		return nil, errors.New("session: cookie store needs at least one key")
+	}
+	if opts.CookieName == "" {
+		opts.CookieName = defaultCookieName
+	}
+	if opts.MaxSize <= 0 {
+		opts.MaxSize = defaultCookieMaxSize
+	}
+	if opts.Path == "" {
+		opts.Path = "/"
+	}
+	if opts.SameSite == 0 {
+		opts.SameSite = http.SameSiteLaxMode
+	}
+
+	s := &CookieStore{opts: opts}
+	for i, secret := range opts.Keys {
+		if len(secret) < minCookieKeyLen {
+			return nil, fmt.Errorf("session: key %d is %d bytes, need at least %d", i, len(secret), minCookieKeyLen)
+		}
+		k, err := deriveCookieKey(secret)
+		if err != nil {
+			return nil, fmt.Errorf("session: key %d: %w", i, err)
+		}
+		s.keys = append(s.keys, k)
+	}
+	return s, nil
+}
+
+// deriveCookieKey splits one secret into independent encryption and signing
+// keys so the same bytes are never used for both.
+func deriveCookieKey(secret []byte) (cookieKey, error) {
+	derive := func(label string) []byte {
+		m := hmac.New(sha256.New, secret)
+		m.Write([]byte(label))
+		return m.Sum(nil)
+	}
+	block, err := aes.NewCipher(derive("webx session cookie encryption"))
+	if err != nil {
+		return cookieKey{}, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return cookieKey{}, err
+	}
+	return cookieKey{block: aead, hash: derive("webx session cookie signing")}, nil
+}
+
+// cookiePayload is the plaintext stored in the cookie. The session ID is
+// bound into the payload so a cookie cannot be replayed under another ID.
+type cookiePayload struct {
+	ID      string            `json:"id"`
+	Expires int64             `json:"exp,omitempty"`
+	Values  map[string]string `json:"v"`
+}
+
+// encode encrypts and signs a payload with the primary key.
+func (s *CookieStore) encode(p cookiePayload) (string, error) {
+	plain, err := json.Marshal(p)
+	if err != nil {
+		return "", fmt.Errorf("marshal session: %w", err)
+	}
+	k := s.keys[0]
+	nonce := make([]byte, k.block.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("reading random bytes: %w", err)
+	}
+	sealed := k.block.Seal(nonce, nonce, plain, []byte(s.opts.CookieName))
+	mac := hmac.New(sha256.New, k.hash)
+	mac.Write([]byte(s.opts.CookieName))
+	mac.Write(sealed)
+	value := base64.RawURLEncoding.EncodeToString(append(sealed, mac.Sum(nil)...))
+	if len(value) > s.opts.MaxSize {
+		return "", fmt.Errorf("%w: %d > %d bytes", ErrCookieTooLarge, len(value), s.opts.MaxSize)
+	}
+	return value, nil
+}
+
+// decode verifies and decrypts a cookie value, trying every key in order.
+func (s *CookieStore) decode(value string, now time.Time) (cookiePayload, bool) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(raw) < sha256.Size {
+		return cookiePayload{}, false
+	}
+	sealed, sum := raw[:len(raw)-sha256.Size], raw[len(raw)-sha256.Size:]
+	for _, k := range s.keys {
+		mac := hmac.New(sha256.New, k.hash)
+		mac.Write([]byte(s.opts.CookieName))
+		mac.Write(sealed)
+		if !hmac.Equal(mac.Sum(nil), sum) {
+			continue
+		}
+		ns := k.block.NonceSize()
+		if len(sealed) < ns {
+			return cookiePayload{}, false
+		}
+		plain, err := k.block.Open(nil, sealed[:ns], sealed[ns:], []byte(s.opts.CookieName))
+		if err != nil {
+			return cookiePayload{}, false
+		}
+		var p cookiePayload
+		if err := json.Unmarshal(plain, &p); err != nil {
+			return cookiePayload{}, false
+		}
+		if p.Expires != 0 && !now.Before(time.Unix(p.Expires, 0)) {
+			return cookiePayload{}, false
+		}
+		return p, true
+	}
+	return cookiePayload{}, false
+}
+
+// Bind implements webx.RequestSessionStore. It decodes the incoming cookie
+// and returns a store scoped to this request.
+func (s *CookieStore) Bind(w http.ResponseWriter, r *http.Request) (webx.SessionStore, http.ResponseWriter, func()) {
+	rs := &requestCookieStore{parent: s}
+	if c, err := r.Cookie(s.opts.CookieName); err == nil {
+		if p, ok := s.decode(c.Value, time.Now()); ok {
+			rs.payload = p
+		}
+	}
+	cw := &cookieWriter{ResponseWriter: w, store: rs}
+	return rs, cw, cw.commit
+}
+
+// Get always returns an empty value: a CookieStore only holds session data
+// inside a request bound by webx.SessionMiddleware.
+func (s *CookieStore) Get(sessionID, key string) (string, error) { return "", nil }
+
+// Set fails outside a bound request; see Bind.
+func (s *CookieStore) Set(sessionID, key, value string) error {
+	return errors.New("session: cookie store used outside webx.SessionMiddleware")
+}
+
+// Delete is a no-op outside a bound request; see Bind.
+func (s *CookieStore) Delete(sessionID string) error { return nil }
+
+// requestCookieStore is the per-request view returned by Bind.
+type requestCookieStore struct {
+	parent *CookieStore
+
+	mu      sync.Mutex
+	payload cookiePayload
+	encoded string // latest encoding of payload, valid when dirty
+	dirty   bool
+	deleted bool
+}
+
+var (
+	_ webx.ExpiringSessionStore = (*requestCookieStore)(nil)
+	_ webx.RotatingSessionStore = (*requestCookieStore)(nil)
+)
+
+func (s *requestCookieStore) Get(sessionID, key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.payload.ID != sessionID {
+		return "", nil
+	}
+	if s.payload.Expires != 0 && !time.Now().Before(time.Unix(s.payload.Expires, 0)) {
+		return "", nil
+	}
+	return s.payload.Values[key], nil
+}
+
+func (s *requestCookieStore) Set(sessionID, key, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	next := s.clone()
+	if next.ID != sessionID {
+		next = cookiePayload{ID: sessionID}
+	}
+	if next.Values == nil {
+		next.Values = map[string]string{}
+	}
+	next.Values[key] = value
+	return s.replace(next)
+}
+
+func (s *requestCookieStore) Delete(sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.payload.ID != sessionID {
+		return nil
+	}
+	s.payload = cookiePayload{}
+	s.dirty, s.deleted = true, true
+	return nil
+}
+
+func (s *requestCookieStore) Expire(sessionID string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.payload.ID != sessionID {
+		return nil
+	}
+	next := s.clone()
+	next.Expires = at.Unix()
+	return s.replace(next)
+}
+
+func (s *requestCookieStore) Rotate(oldID, newID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.payload.ID != oldID {
+		return nil
+	}
+	next := s.clone()
+	next.ID = newID
+	return s.replace(next)
+}
+
+func (s *requestCookieStore) clone() cookiePayload {
+	p := s.payload
+	p.Values = make(map[string]string, len(s.payload.Values))
+	for k, v := range s.payload.Values {
+		p.Values[k] = v
+	}
+	return p
+}
+
+// replace encodes next and adopts it only if it fits in the size limit.
+func (s *requestCookieStore) replace(next cookiePayload) error {
+	encoded, err := s.parent.encode(next)
+	if err != nil {
+		return err
+	}
+	s.payload, s.encoded = next, encoded
+	s.dirty, s.deleted = true, false
+	return nil
+}
+
+// cookie returns the Set-Cookie value for the pending state, or nil when the
+// session was not modified.
+func (s *requestCookieStore) cookie() *http.Cookie {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.dirty {
+		return nil
+	}
+	o := s.parent.opts
+	c := &http.Cookie{
+		Name:     o.CookieName,
+		Value:    s.encoded,
+		Path:     o.Path,
+		Domain:   o.Domain,
+		Secure:   o.Secure,
+		HttpOnly: true,
+		SameSite: o.SameSite,
+	}
+	switch {
+	case s.deleted:
+		c.Value, c.MaxAge = "", -1
+	case s.payload.Expires != 0:
+		c.Expires = time.Unix(s.payload.Expires, 0)
+	}
+	return c
+}
+
+// cookieWriter adds the session cookie right before the response headers
+// are sent.
+type cookieWriter struct {
+	http.ResponseWriter
+	store     *requestCookieStore
+	committed bool
+}
+
+func (w *cookieWriter) commit() {
+	if w.committed {
+		return
+	}
+	w.committed = true
+	if c := w.store.cookie(); c != nil {
+		http.SetCookie(w.ResponseWriter, c)
+	}
+}
+
+func (w *cookieWriter) WriteHeader(code int) {
+	w.commit()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *cookieWriter) Write(b []byte) (int, error) {
+	w.commit()
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush lets SSE streams flush through the wrapper.
+func (w *cookieWriter) Flush() {
+	w.commit()
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (w *cookieWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
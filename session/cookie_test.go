@@ -0,0 +1,221 @@
+package session_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/plaenen/webx"
+	"github.com/plaenen/webx/session"
+	"github.com/starfederation/datastar-go/datastar"
+)
+
+var (
+	keyA = bytes.Repeat([]byte("a"), 32)
+	keyB = bytes.Repeat([]byte("b"), 32)
+)
+
+func newCookieStore(t *testing.T, opts session.CookieStoreOptions) *session.CookieStore {
+	t.Helper()
+	s, err := session.NewCookieStore(opts)
+	if err != nil {
+		t.Fatalf("NewCookieStore: %v", err)
+	}
+	return s
+}
+
+// roundTrip runs a request through SessionMiddleware with the given cookies
+// and returns the response cookies by name.
+func roundTrip(t *testing.T, store webx.SessionStore, cookies map[string]string, h http.HandlerFunc) (map[string]*http.Cookie, *httptest.ResponseRecorder) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for name, value := range cookies {
+		req.AddCookie(&http.Cookie{Name: name, Value: value})
+	}
+	rec := httptest.NewRecorder()
+	if h == nil {
+		h = func(http.ResponseWriter, *http.Request) {}
+	}
+	webx.SessionMiddleware(store)(h).ServeHTTP(rec, req)
+
+	out := map[string]*http.Cookie{}
+	for _, c := range rec.Result().Cookies() {
+		out[c.Name] = c
+	}
+	return out, rec
+}
+
+// jar merges response cookies into the cookies sent with the next request.
+func jar(into map[string]string, from map[string]*http.Cookie) map[string]string {
+	for name, c := range from {
+		if c.MaxAge < 0 {
+			delete(into, name)
+			continue
+		}
+		into[name] = c.Value
+	}
+	return into
+}
+
+func TestNewCookieStore_Validation(t *testing.T) {
+	if _, err := session.NewCookieStore(session.CookieStoreOptions{}); err == nil {
+		t.Error("expected error without keys")
+	}
+	if _, err := session.NewCookieStore(session.CookieStoreOptions{Keys: [][]byte{[]byte("short")}}); err == nil {
+		t.Error("expected error for short key")
+	}
+}
+
+func TestCookieStore_RoundTrip(t *testing.T) {
+	store := newCookieStore(t, session.CookieStoreOptions{Keys: [][]byte{keyA}})
+
+	var sessionID, token string
+	first, _ := roundTrip(t, store, nil, func(w http.ResponseWriter, r *http.Request) {
+		wctx := webx.FromContext(r.Context())
+		sessionID, token = wctx.SessionID, wctx.CSRFToken
+	})
+	data, ok := first["webx_session_data"]
+	if !ok {
+		t.Fatal("expected data cookie")
+	}
+	if strings.Contains(data.Value, token) {
+		t.Fatal("cookie value must be encrypted")
+	}
+
+	cookies := jar(map[string]string{}, first)
+	roundTrip(t, store, cookies, func(w http.ResponseWriter, r *http.Request) {
+		wctx := webx.FromContext(r.Context())
+		if wctx.SessionID != sessionID || wctx.CSRFToken != token {
+			t.Errorf("session not restored: got %q/%q", wctx.SessionID, wctx.CSRFToken)
+		}
+	})
+}
+
+func TestCookieStore_Tampered(t *testing.T) {
+	store := newCookieStore(t, session.CookieStoreOptions{Keys: [][]byte{keyA}})
+	var token string
+	first, _ := roundTrip(t, store, nil, func(w http.ResponseWriter, r *http.Request) {
+		token = webx.FromContext(r.Context()).CSRFToken
+	})
+	cookies := jar(map[string]string{}, first)
+	v := []byte(cookies["webx_session_data"])
+	v[10] ^= 1
+	cookies["webx_session_data"] = string(v)
+
+	roundTrip(t, store, cookies, func(w http.ResponseWriter, r *http.Request) {
+		if webx.FromContext(r.Context()).CSRFToken == token {
+			t.Error("tampered cookie must not be accepted")
+		}
+	})
+}
+
+func TestCookieStore_KeyRotation(t *testing.T) {
+	old := newCookieStore(t, session.CookieStoreOptions{Keys: [][]byte{keyA}})
+	var token string
+	first, _ := roundTrip(t, old, nil, func(w http.ResponseWriter, r *http.Request) {
+		token = webx.FromContext(r.Context()).CSRFToken
+	})
+	cookies := jar(map[string]string{}, first)
+
+	rotated := newCookieStore(t, session.CookieStoreOptions{Keys: [][]byte{keyB, keyA}})
+	roundTrip(t, rotated, cookies, func(w http.ResponseWriter, r *http.Request) {
+		if webx.FromContext(r.Context()).CSRFToken != token {
+			t.Error("old key must still decode")
+		}
+	})
+
+	dropped := newCookieStore(t, session.CookieStoreOptions{Keys: [][]byte{keyB}})
+	roundTrip(t, dropped, cookies, func(w http.ResponseWriter, r *http.Request) {
+		if webx.FromContext(r.Context()).CSRFToken == token {
+			t.Error("dropped key must not decode")
+		}
+	})
+}
+
+func TestCookieStore_SizeLimit(t *testing.T) {
+	store := newCookieStore(t, session.CookieStoreOptions{Keys: [][]byte{keyA}, MaxSize: 600})
+	var setErr error
+	roundTrip(t, store, nil, func(w http.ResponseWriter, r *http.Request) {
+		wctx := webx.FromContext(r.Context())
+		setErr = wctx.SessionStore().Set(wctx.SessionID, "blob", strings.Repeat("x", 1000))
+	})
+	if !errors.Is(setErr, session.ErrCookieTooLarge) {
+		t.Errorf("Set err = %v, want ErrCookieTooLarge", setErr)
+	}
+}
+
+func TestCookieStore_FormTokens(t *testing.T) {
+	store := newCookieStore(t, session.CookieStoreOptions{Keys: [][]byte{keyA}})
+	cookies := map[string]string{}
+	for i := range 200 {
+		var token string
+		out, _ := roundTrip(t, store, cookies, func(w http.ResponseWriter, r *http.Request) {
+			var err error
+			// Render a form twice but submit only one of them.
+			if _, err = webx.FormToken(r.Context(), "delete"); err == nil {
+				token, err = webx.FormToken(r.Context(), "delete")
+			}
+			if err != nil {
+				t.Fatalf("request %d: FormToken: %v", i, err)
+			}
+		})
+		cookies = jar(cookies, out)
+		out, _ = roundTrip(t, store, cookies, func(w http.ResponseWriter, r *http.Request) {
+			r.Header.Set(webx.FormTokenHeader, token)
+			if err := webx.ConsumeFormToken(r, "delete"); err != nil {
+				t.Fatalf("request %d: ConsumeFormToken: %v", i, err)
+			}
+		})
+		cookies = jar(cookies, out)
+	}
+	if n := len(cookies["webx_session_data"]); n > 4096 {
+		t.Errorf("session cookie is %d bytes after 400 form tokens", n)
+	}
+}
+
+func TestCookieStore_SSE(t *testing.T) {
+	store := newCookieStore(t, session.CookieStoreOptions{Keys: [][]byte{keyA}})
+	cookies, rec := roundTrip(t, store, nil, func(w http.ResponseWriter, r *http.Request) {
+		sse := datastar.NewSSE(w, r)
+		sse.PatchElements(`<div id="x"></div>`)
+	})
+	if _, ok := cookies["webx_session_data"]; !ok {
+		t.Error("cookie must be set before the SSE stream starts")
+	}
+	if !strings.Contains(rec.Body.String(), "datastar-patch-elements") {
+		t.Errorf("expected SSE body, got %q", rec.Body.String())
+	}
+}
+
+func TestCookieStore_RotateSession(t *testing.T) {
+	store := newCookieStore(t, session.CookieStoreOptions{Keys: [][]byte{keyA}})
+	var oldID string
+	first, _ := roundTrip(t, store, nil, func(w http.ResponseWriter, r *http.Request) {
+		wctx := webx.FromContext(r.Context())
+		oldID = wctx.SessionID
+		wctx.SessionStore().Set(oldID, "user", "alice")
+	})
+	cookies := jar(map[string]string{}, first)
+
+	var newID string
+	second, _ := roundTrip(t, store, cookies, func(w http.ResponseWriter, r *http.Request) {
+		if err := webx.RotateSession(w, r); err != nil {
+			t.Fatalf("RotateSession: %v", err)
+		}
+		newID = webx.FromContext(r.Context()).SessionID
+	})
+	cookies = jar(cookies, second)
+
+	roundTrip(t, store, cookies, func(w http.ResponseWriter, r *http.Request) {
+		wctx := webx.FromContext(r.Context())
+		if wctx.SessionID != newID || newID == oldID {
+			t.Fatalf("session ID = %q, want rotated %q", wctx.SessionID, newID)
+		}
+		if v, _ := wctx.SessionStore().Get(newID, "user"); v != "alice" {
+			t.Errorf("user = %q, want alice", v)
+		}
+	})
+}
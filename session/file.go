@@ -0,0 +1,253 @@
+package session
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/plaenen/webx"
+)
+
+const fileSessionExt = ".json"
+
+// FileStoreOptions configures a FileStore.
+type FileStoreOptions struct {
+	// SweepInterval is how often expired session files are deleted.
+	// Defaults to DefaultSweepInterval. Negative disables the sweeper.
+	SweepInterval time.Duration
+}
+
+// FileStore is a webx.SessionStore that persists each session as a JSON file
+// in a directory, so sessions survive restarts without an external service.
+// Writes go through a temp file and rename, so a crash never leaves a
+// half-written session. A directory must only be used by one process.
+type FileStore struct {
+	dir     string
+	mu      sync.Mutex
+	sweeper *sweeper
+}
+
+// Compile-time check that FileStore implements the optional session interfaces.
+var (
+	_ webx.ExpiringSessionStore = (*FileStore)(nil)
+	_ webx.RotatingSessionStore = (*FileStore)(nil)
+	_ webx.SessionLister        = (*FileStore)(nil)
+)
+
+// fileSession is the on-disk representation of one session.
+type fileSession struct {
+	ID      string            `json:"id"`
+	Expires int64             `json:"expires,omitempty"` // unix milliseconds
+	Values  map[string]string `json:"values"`
+}
+
+// NewFileStore returns a FileStore rooted at dir, creating it if needed.
+// Call Close to stop its sweeper.
+func NewFileStore(dir string, opts ...FileStoreOptions) (*FileStore, error) {
+	var o FileStoreOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("session: create store dir: %w", err)
+	}
+	s := &FileStore{dir: dir}
+	s.sweeper = startSweeper(o.SweepInterval, func() { _ = s.Sweep() })
+	return s, nil
+}
+
+// path maps a session ID to its file. IDs come from cookies, so they are
+// hashed rather than trusted as file names.
+func (s *FileStore) path(sessionID string) string {
+	sum := sha256.Sum256([]byte(sessionID))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+fileSessionExt)
+}
+
+// load reads a session file. A missing or expired session yields nil.
+func (s *FileStore) load(sessionID string, now time.Time) (*fileSession, error) {
+	data, err := os.ReadFile(s.path(sessionID))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("session: read %s: %w", sessionID, err)
+	}
+	var sess fileSession
+	if err := json.Unmarshal(data, &sess); err != nil {
+		return nil, fmt.Errorf("session: decode %s: %w", sessionID, err)
+	}
+	if sess.expired(now) {
+		return nil, nil
+	}
+	return &sess, nil
+}
+
+func (s *FileStore) save(sess *fileSession) error {
+	data, err := json.Marshal(sess)
+	if err != nil {
+		return fmt.Errorf("session: encode %s: %w", sess.ID, err)
+	}
+	tmp, err := os.CreateTemp(s.dir, "tmp-*")
+	if err != nil {
+		return fmt.Errorf("session: write %s: %w", sess.ID, err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("session: write %s: %w", sess.ID, err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("session: write %s: %w", sess.ID, err)
+	}
+	if err := os.Rename(tmp.Name(), s.path(sess.ID)); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("session: write %s: %w", sess.ID, err)
+	}
+	return nil
+}
+
+func (s *FileStore) Get(sessionID string, key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, err := s.load(sessionID, time.Now())
+	if err != nil || sess == nil {
+		return "", err
+	}
+	return sess.Values[key], nil
+}
+
+func (s *FileStore) Set(sessionID string, key string, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, err := s.load(sessionID, time.Now())
+	if err != nil {
+		return err
+	}
+	if sess == nil {
+		sess = &fileSession{ID: sessionID, Values: map[string]string{}}
+	}
+	sess.Values[key] = value
+	return s.save(sess)
+}
+
+func (s *FileStore) Delete(sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.Remove(s.path(sessionID)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("session: delete %s: %w", sessionID, err)
+	}
+	return nil
+}
+
+// Expire sets the moment the session is evicted.
+func (s *FileStore) Expire(sessionID string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, err := s.load(sessionID, time.Now())
+	if err != nil || sess == nil {
+		return err
+	}
+	sess.Expires = at.UnixMilli()
+	return s.save(sess)
+}
+
+// Rotate moves every key of oldID to newID.
+func (s *FileStore) Rotate(oldID, newID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, err := s.load(oldID, time.Now())
+	if err != nil || sess == nil {
+		return err
+	}
+	sess.ID = newID
+	if err := s.save(sess); err != nil {
+		return err
+	}
+	if err := os.Remove(s.path(oldID)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("session: delete %s: %w", oldID, err)
+	}
+	return nil
+}
+
+// ListSessions returns the unexpired sessions linked to userID. It reads
+// every session file, so it is linear in the number of stored sessions.
+func (s *FileStore) ListSessions(userID string) ([]webx.SessionInfo, error) {
+	if userID == "" {
+		return nil, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []webx.SessionInfo
+	err := s.walk(func(_ string, sess *fileSession, expired bool) {
+		if !expired && sess.Values[webx.SessionUserKey] == userID {
+			out = append(out, webx.SessionInfoFromValues(sess.ID, sess.Values))
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("session: list: %w", err)
+	}
+	webx.SortSessions(out)
+	return out, nil
+}
+
+// Sweep deletes every expired session file. It runs periodically in the
+// background; call it directly to force a sweep.
+func (s *FileStore) Sweep() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.walk(func(path string, sess *fileSession, expired bool) {
+		if sess == nil || expired {
+			os.Remove(path)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("session: sweep: %w", err)
+	}
+	return nil
+}
+
+// walk calls fn for every session file. sess is nil for unreadable files.
+// The caller must hold s.mu.
+func (s *FileStore) walk(fn func(path string, sess *fileSession, expired bool)) error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), fileSessionExt) {
+			continue
+		}
+		p := filepath.Join(s.dir, e.Name())
+		data, err := os.ReadFile(p)
+		if err != nil {
+			continue
+		}
+		var sess fileSession
+		if json.Unmarshal(data, &sess) != nil {
+			fn(p, nil, false)
+			continue
+		}
+		fn(p, &sess, sess.expired(now))
+	}
+	return nil
+}
+
+// Close stops the background sweeper.
+func (s *FileStore) Close() error {
+	s.sweeper.stop()
+	return nil
+}
+
+func (f *fileSession) expired(now time.Time) bool {
+	return f.Expires != 0 && now.UnixMilli() >= f.Expires
+}
@@ -0,0 +1,65 @@
+package session_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/plaenen/webx/session"
+	"github.com/plaenen/webx/session/sessiontest"
+)
+
+func newFileStore(t *testing.T, dir string) *session.FileStore {
+	t.Helper()
+	store, err := session.NewFileStore(dir, session.FileStoreOptions{SweepInterval: -1})
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestFileStore_Conformance(t *testing.T) {
+	sessiontest.Conformance(t, newFileStore(t, t.TempDir()))
+}
+
+func TestFileStore_SurvivesReopen(t *testing.T) {
+	dir := t.TempDir()
+	newFileStore(t, dir).Set("abc", "k", "v")
+
+	if v, _ := newFileStore(t, dir).Get("abc", "k"); v != "v" {
+		t.Errorf("Get after reopen = %q, want v", v)
+	}
+}
+
+func TestFileStore_HostileIDStaysInDir(t *testing.T) {
+	dir := t.TempDir()
+	store := newFileStore(t, dir)
+	if err := store.Set("../../escape", "k", "v"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Fatalf("expected exactly one file in store dir, got %d", len(entries))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "..", "..", "escape")); err == nil {
+		t.Error("session ID escaped the store directory")
+	}
+}
+
+func TestFileStore_Sweep(t *testing.T) {
+	dir := t.TempDir()
+	store := newFileStore(t, dir)
+	store.Set("a", "k", "v")
+	store.Set("b", "k", "v")
+	store.Expire("a", time.Now().Add(-time.Second))
+
+	if err := store.Sweep(); err != nil {
+		t.Fatalf("Sweep: %v", err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("files after sweep = %d, want 1", len(entries))
+	}
+}
@@ -0,0 +1,81 @@
+package session_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/plaenen/webx"
+	"github.com/plaenen/webx/session"
+)
+
+// login opens a session for userID from the given user agent and returns
+// its ID.
+func login(t *testing.T, store webx.SessionStore, userID, userAgent string) string {
+	t.Helper()
+	var id string
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("User-Agent", userAgent)
+	webx.SessionMiddleware(store)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := webx.SetSessionUser(r, userID); err != nil {
+			t.Fatalf("SetSessionUser: %v", err)
+		}
+		id = webx.FromContext(r.Context()).SessionID
+	})).ServeHTTP(httptest.NewRecorder(), req)
+	return id
+}
+
+func TestListUserSessions(t *testing.T) {
+	store := session.NewMemoryStore()
+	t.Cleanup(func() { store.Close() })
+	login(t, store, "alice", "Firefox")
+	login(t, store, "bob", "Chrome")
+	current := login(t, store, "alice", "Safari")
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "webx_session", Value: current})
+	webx.SessionMiddleware(store)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessions, err := webx.ListUserSessions(r)
+		if err != nil {
+			t.Fatalf("ListUserSessions: %v", err)
+		}
+		if len(sessions) != 2 {
+			t.Fatalf("got %d sessions, want 2", len(sessions))
+		}
+		for _, s := range sessions {
+			if s.UserID != "alice" || (s.UserAgent != "Firefox" && s.UserAgent != "Safari") {
+				t.Errorf("unexpected session %+v", s)
+			}
+		}
+	})).ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestRevokeSessions(t *testing.T) {
+	store := session.NewMemoryStore()
+	t.Cleanup(func() { store.Close() })
+	a := login(t, store, "alice", "A")
+	b := login(t, store, "alice", "B")
+	c := login(t, store, "alice", "C")
+
+	sessions, _ := store.ListSessions("alice")
+	var handleB string
+	for _, s := range sessions {
+		if s.ID == b {
+			handleB = s.Handle()
+		}
+	}
+	if n, err := webx.RevokeSessions(store, "alice", nil, handleB); err != nil || n != 1 {
+		t.Fatalf("revoke by handle: n=%d err=%v", n, err)
+	}
+	if n, err := webx.RevokeSessions(store, "alice", []string{a}); err != nil || n != 1 {
+		t.Fatalf("revoke all but current: n=%d err=%v", n, err)
+	}
+
+	left, _ := store.ListSessions("alice")
+	if len(left) != 1 || left[0].ID != a {
+		t.Errorf("remaining = %v, want only %s", left, a)
+	}
+	if v, _ := store.Get(c, webx.SessionUserKey); v != "" {
+		t.Error("revoked session still readable")
+	}
+}
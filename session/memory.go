@@ -0,0 +1,186 @@
+package session
+
+import (
+	"sync"
+	"time"
+
+	"github.com/plaenen/webx"
+)
+
+// DefaultSweepInterval is how often stores drop expired sessions when no
+// interval is configured.
+const DefaultSweepInterval = time.Minute
+
+// MemoryStoreOptions configures a MemoryStore.
+type MemoryStoreOptions struct {
+	// SweepInterval is how often expired sessions are removed from memory.
+	// Defaults to DefaultSweepInterval. Negative disables the sweeper;
+	// expired sessions are then still hidden from Get.
+	SweepInterval time.Duration
+}
+
+// MemoryStore is an in-memory webx.SessionStore. Sessions are evicted once
+// their deadline passes, both lazily on read and by a background sweeper.
+// Suitable for development and single-instance deployments.
+type MemoryStore struct {
+	mu       sync.RWMutex
+	sessions map[string]*memSession
+	sweeper  *sweeper
+}
+
+type memSession struct {
+	values  map[string]string
+	expires time.Time
+}
+
+// Compile-time check that MemoryStore implements the optional session interfaces.
+var (
+	_ webx.ExpiringSessionStore = (*MemoryStore)(nil)
+	_ webx.RotatingSessionStore = (*MemoryStore)(nil)
+	_ webx.SessionLister        = (*MemoryStore)(nil)
+)
+
+// NewMemoryStore returns a ready-to-use in-memory session store. Call Close
+// to stop its sweeper.
+func NewMemoryStore(opts ...MemoryStoreOptions) *MemoryStore {
+	var o MemoryStoreOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	s := &MemoryStore{sessions: make(map[string]*memSession)}
+	s.sweeper = startSweeper(o.SweepInterval, s.Sweep)
+	return s
+}
+
+func (s *MemoryStore) Get(sessionID string, key string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	sess, ok := s.sessions[sessionID]
+	if !ok || sess.expired(time.Now()) {
+		return "", nil
+	}
+	return sess.values[key], nil
+}
+
+func (s *MemoryStore) Set(sessionID string, key string, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, ok := s.sessions[sessionID]
+	if !ok || sess.expired(time.Now()) {
+		sess = &memSession{values: make(map[string]string)}
+		s.sessions[sessionID] = sess
+	}
+	sess.values[key] = value
+	return nil
+}
+
+func (s *MemoryStore) Delete(sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, sessionID)
+	return nil
+}
+
+// Expire sets the moment the session is evicted.
+func (s *MemoryStore) Expire(sessionID string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sess, ok := s.sessions[sessionID]; ok {
+		sess.expires = at
+	}
+	return nil
+}
+
+// Rotate moves every key of oldID to newID.
+func (s *MemoryStore) Rotate(oldID, newID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sess, ok := s.sessions[oldID]; ok {
+		s.sessions[newID] = sess
+		delete(s.sessions, oldID)
+	}
+	return nil
+}
+
+// ListSessions returns the unexpired sessions linked to userID.
+func (s *MemoryStore) ListSessions(userID string) ([]webx.SessionInfo, error) {
+	if userID == "" {
+		return nil, nil
+	}
+	now := time.Now()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var out []webx.SessionInfo
+	for id, sess := range s.sessions {
+		if sess.expired(now) || sess.values[webx.SessionUserKey] != userID {
+			continue
+		}
+		out = append(out, webx.SessionInfoFromValues(id, sess.values))
+	}
+	webx.SortSessions(out)
+	return out, nil
+}
+
+// Sweep removes every expired session. It runs periodically in the
+// background; call it directly to force a sweep.
+func (s *MemoryStore) Sweep() {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, sess := range s.sessions {
+		if sess.expired(now) {
+			delete(s.sessions, id)
+		}
+	}
+}
+
+// Len returns the number of sessions currently held, including expired
+// sessions not yet swept.
+func (s *MemoryStore) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.sessions)
+}
+
+// Close stops the background sweeper.
+func (s *MemoryStore) Close() error {
+	s.sweeper.stop()
+	return nil
+}
+
+func (m *memSession) expired(now time.Time) bool {
+	return !m.expires.IsZero() && !now.Before(m.expires)
+}
+
+// sweeper calls fn on a fixed interval until stopped.
+type sweeper struct {
+	done chan struct{}
+	once sync.Once
+}
+
+func startSweeper(interval time.Duration, fn func()) *sweeper {
+	sw := &sweeper{done: make(chan struct{})}
+	if interval == 0 {
+		interval = DefaultSweepInterval
+	}
+	if interval < 0 {
+		return sw
+	}
+	go func() {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C:
+				fn()
+			case <-sw.done:
+				return
+			}
+		}
+	}()
+	return sw
+}
+
+func (sw *sweeper) stop() {
+	sw.once.Do(func() { close(sw.done) })
+}
@@ -0,0 +1,32 @@
+package session_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/plaenen/webx/session"
+	"github.com/plaenen/webx/session/sessiontest"
+)
+
+func TestMemoryStore_Conformance(t *testing.T) {
+	store := session.NewMemoryStore()
+	t.Cleanup(func() { store.Close() })
+	sessiontest.Conformance(t, store)
+}
+
+func TestMemoryStore_Sweep(t *testing.T) {
+	store := session.NewMemoryStore(session.MemoryStoreOptions{SweepInterval: 10 * time.Millisecond})
+	t.Cleanup(func() { store.Close() })
+
+	store.Set("a", "k", "v")
+	store.Set("b", "k", "v")
+	store.Expire("a", time.Now().Add(-time.Second))
+
+	deadline := time.Now().Add(time.Second)
+	for store.Len() != 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Len = %d after sweep interval, want 1", store.Len())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
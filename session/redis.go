@@ -0,0 +1,341 @@
+package session
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/plaenen/webx"
+)
+
+// RedisOptions configures a RedisStore.
+type RedisOptions struct {
+	// Addr is the host:port of the server. Defaults to "localhost:6379".
+	Addr string
+	// Password is sent with AUTH when set.
+	Password string
+	// DB is selected with SELECT when non-zero.
+	DB int
+	// Prefix namespaces session keys. Defaults to "webx:session:".
+	Prefix string
+	// Timeout bounds connecting and each command round trip.
+	// Defaults to 5s.
+	Timeout time.Duration
+	// PoolSize is the maximum number of idle connections kept. Defaults to 8.
+	PoolSize int
+}
+
+// RedisStore is a webx.SessionStore for any server speaking the Redis
+// protocol (Redis, Valkey, KeyDB, DragonflyDB). Each session is a hash, and
+// expiry is delegated to the server with PEXPIREAT, so no sweeper is needed.
+// A set per user indexes sessions for ListSessions; members whose session
+// has expired are pruned when the set is listed.
+type RedisStore struct {
+	opts RedisOptions
+	idle chan *redisConn
+}
+
+// Compile-time check that RedisStore implements the optional session interfaces.
+var (
+	_ webx.ExpiringSessionStore = (*RedisStore)(nil)
+	_ webx.RotatingSessionStore = (*RedisStore)(nil)
+	_ webx.SessionLister        = (*RedisStore)(nil)
+)
+
+// NewRedisStore returns a RedisStore. Connections are dialled lazily, so a
+// server outage surfaces as errors from Get/Set rather than here.
+func NewRedisStore(opts RedisOptions) *RedisStore {
+	if opts.Addr == "" {
+		opts.Addr = "localhost:6379"
+	}
+	if opts.Prefix == "" {
+		opts.Prefix = "webx:session:"
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+	if opts.PoolSize <= 0 {
+		opts.PoolSize = 8
+	}
+	return &RedisStore{opts: opts, idle: make(chan *redisConn, opts.PoolSize)}
+}
+
+func (s *RedisStore) key(sessionID string) string {
+	return s.opts.Prefix + sessionID
+}
+
+// userKey names the set indexing a user's sessions. It lives outside Prefix
+// so it can never collide with a session ID.
+func (s *RedisStore) userKey(userID string) string {
+	return strings.TrimSuffix(s.opts.Prefix, ":") + "-user:" + userID
+}
+
+// user returns the user linked to a session, or "".
+func (s *RedisStore) user(sessionID string) (string, error) {
+	return s.Get(sessionID, webx.SessionUserKey)
+}
+
+func (s *RedisStore) Get(sessionID string, key string) (string, error) {
+	v, err := s.do("HGET", s.key(sessionID), key)
+	if err != nil {
+		return "", err
+	}
+	str, _ := v.(string)
+	return str, nil
+}
+
+func (s *RedisStore) Set(sessionID string, key string, value string) error {
+	if key != webx.SessionUserKey {
+		_, err := s.do("HSET", s.key(sessionID), key, value)
+		return err
+	}
+	prev, err := s.user(sessionID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.do("HSET", s.key(sessionID), key, value); err != nil {
+		return err
+	}
+	if prev != "" && prev != value {
+		if _, err := s.do("SREM", s.userKey(prev), sessionID); err != nil {
+			return err
+		}
+	}
+	if value != "" {
+		_, err = s.do("SADD", s.userKey(value), sessionID)
+	}
+	return err
+}
+
+func (s *RedisStore) Delete(sessionID string) error {
+	userID, err := s.user(sessionID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.do("DEL", s.key(sessionID)); err != nil {
+		return err
+	}
+	if userID != "" {
+		_, err = s.do("SREM", s.userKey(userID), sessionID)
+	}
+	return err
+}
+
+// Expire sets the moment the server evicts the session.
+func (s *RedisStore) Expire(sessionID string, at time.Time) error {
+	_, err := s.do("PEXPIREAT", s.key(sessionID), strconv.FormatInt(at.UnixMilli(), 10))
+	return err
+}
+
+// Rotate renames the session hash, keeping its TTL.
+func (s *RedisStore) Rotate(oldID, newID string) error {
+	_, err := s.do("RENAME", s.key(oldID), s.key(newID))
+	var rerr redisError
+	if errors.As(err, &rerr) && strings.Contains(string(rerr), "no such key") {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	userID, err := s.user(newID)
+	if err != nil || userID == "" {
+		return err
+	}
+	if _, err := s.do("SREM", s.userKey(userID), oldID); err != nil {
+		return err
+	}
+	_, err = s.do("SADD", s.userKey(userID), newID)
+	return err
+}
+
+// ListSessions returns the unexpired sessions linked to userID.
+func (s *RedisStore) ListSessions(userID string) ([]webx.SessionInfo, error) {
+	if userID == "" {
+		return nil, nil
+	}
+	reply, err := s.do("SMEMBERS", s.userKey(userID))
+	if err != nil {
+		return nil, err
+	}
+	members, _ := reply.([]any)
+	var out []webx.SessionInfo
+	for _, m := range members {
+		id, _ := m.(string)
+		reply, err := s.do("HGETALL", s.key(id))
+		if err != nil {
+			return nil, err
+		}
+		fields, _ := reply.([]any)
+		values := make(map[string]string, len(fields)/2)
+		for i := 0; i+1 < len(fields); i += 2 {
+			k, _ := fields[i].(string)
+			v, _ := fields[i+1].(string)
+			values[k] = v
+		}
+		if values[webx.SessionUserKey] != userID {
+			// Expired or re-assigned since it was indexed.
+			if _, err := s.do("SREM", s.userKey(userID), id); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		out = append(out, webx.SessionInfoFromValues(id, values))
+	}
+	webx.SortSessions(out)
+	return out, nil
+}
+
+// Close closes all idle connections.
+func (s *RedisStore) Close() error {
+	for {
+		select {
+		case c := <-s.idle:
+			c.conn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+// do runs one command on a pooled connection.
+func (s *RedisStore) do(args ...string) (any, error) {
+	c, err := s.get()
+	if err != nil {
+		return nil, fmt.Errorf("session: redis %s: %w", args[0], err)
+	}
+	v, err := c.do(s.opts.Timeout, args...)
+	var rerr redisError
+	if err != nil && !errors.As(err, &rerr) {
+		// Protocol or network error: the connection state is unknown.
+		c.conn.Close()
+		return nil, fmt.Errorf("session: redis %s: %w", args[0], err)
+	}
+	s.put(c)
+	if err != nil {
+		return nil, fmt.Errorf("session: redis %s: %w", args[0], err)
+	}
+	return v, nil
+}
+
+func (s *RedisStore) get() (*redisConn, error) {
+	select {
+	case c := <-s.idle:
+		return c, nil
+	default:
+	}
+	conn, err := net.DialTimeout("tcp", s.opts.Addr, s.opts.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	c := &redisConn{conn: conn, r: bufio.NewReader(conn)}
+	if s.opts.Password != "" {
+		if _, err := c.do(s.opts.Timeout, "AUTH", s.opts.Password); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	if s.opts.DB != 0 {
+		if _, err := c.do(s.opts.Timeout, "SELECT", strconv.Itoa(s.opts.DB)); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (s *RedisStore) put(c *redisConn) {
+	select {
+	case s.idle <- c:
+	default:
+		c.conn.Close()
+	}
+}
+
+// redisError is an error reply sent by the server.
+type redisError string
+
+func (e redisError) Error() string { return string(e) }
+
+// redisConn is a single RESP connection. It is not safe for concurrent use;
+// the pool hands each connection to one caller at a time.
+type redisConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func (c *redisConn) do(timeout time.Duration, args ...string) (any, error) {
+	if err := c.conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return nil, err
+	}
+	if _, err := c.conn.Write(encodeRESP(args)); err != nil {
+		return nil, err
+	}
+	return readRESP(c.r)
+}
+
+// encodeRESP encodes a command as a RESP array of bulk strings.
+func encodeRESP(args []string) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, a := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(a), a)
+	}
+	return []byte(b.String())
+}
+
+// readRESP reads one RESP2 reply. Bulk strings decode to string (nil when
+// absent), integers to int64 and arrays to []any. Error replies are returned
+// as redisError.
+func readRESP(r *bufio.Reader) (any, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("empty reply")
+	}
+	body := line[1:]
+	switch line[0] {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, redisError(body)
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]any, n)
+		for i := range items {
+			if items[i], err = readRESP(r); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("unexpected reply %q", line)
+}
@@ -0,0 +1,22 @@
+package session_test
+
+import (
+	"testing"
+
+	"github.com/plaenen/webx/session"
+	"github.com/plaenen/webx/session/sessiontest"
+)
+
+func TestRedisStore_Conformance(t *testing.T) {
+	fake := sessiontest.NewFakeRedis(t)
+	store := session.NewRedisStore(session.RedisOptions{Addr: fake.Addr(), Password: "secret", DB: 2})
+	t.Cleanup(func() { store.Close() })
+	sessiontest.Conformance(t, store)
+}
+
+func TestRedisStore_Unreachable(t *testing.T) {
+	store := session.NewRedisStore(session.RedisOptions{Addr: "127.0.0.1:1"})
+	if _, err := store.Get("id", "k"); err == nil {
+		t.Error("expected error for unreachable server")
+	}
+}
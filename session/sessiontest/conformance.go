@@ -0,0 +1,315 @@
+// Package sessiontest provides a conformance suite for webx.SessionStore
+// implementations and an in-process fake Redis server for tests.
+package sessiontest
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/plaenen/webx"
+)
+
+// Conformance runs the behaviour every webx.SessionStore must share against
+// store. Optional interfaces (webx.ExpiringSessionStore,
+// webx.RotatingSessionStore, webx.SessionLister) are exercised when store
+// implements them.
+//
+//	func TestMyStore(t *testing.T) {
+//	    sessiontest.Conformance(t, mystore.New())
+//	}
+//
+// Each subtest uses fresh random session IDs, so store may hold other data.
+func Conformance(t *testing.T, store webx.SessionStore) {
+	t.Helper()
+
+	t.Run("GetMissing", func(t *testing.T) {
+		id := newID()
+		assertGet(t, store, id, "missing", "")
+	})
+
+	t.Run("SetGet", func(t *testing.T) {
+		id := newID()
+		mustSet(t, store, id, "k", "v")
+		assertGet(t, store, id, "k", "v")
+	})
+
+	t.Run("Overwrite", func(t *testing.T) {
+		id := newID()
+		mustSet(t, store, id, "k", "first")
+		mustSet(t, store, id, "k", "second")
+		assertGet(t, store, id, "k", "second")
+	})
+
+	t.Run("ValuesRoundTrip", func(t *testing.T) {
+		id := newID()
+		values := []string{"", " ", "with\nnewline", "quote'\"", "unicode ✓ ü", "colon:separated:value", "\r\n$3\r\nfoo"}
+		for i, v := range values {
+			mustSet(t, store, id, fmt.Sprintf("k%d", i), v)
+		}
+		for i, v := range values {
+			assertGet(t, store, id, fmt.Sprintf("k%d", i), v)
+		}
+	})
+
+	t.Run("SessionsIsolated", func(t *testing.T) {
+		a, b := newID(), newID()
+		mustSet(t, store, a, "k", "a")
+		mustSet(t, store, b, "k", "b")
+		assertGet(t, store, a, "k", "a")
+		assertGet(t, store, b, "k", "b")
+	})
+
+	t.Run("PrefixIDsIsolated", func(t *testing.T) {
+		a := newID()
+		b := a + ":x"
+		mustSet(t, store, a, "k", "a")
+		mustSet(t, store, b, "k", "b")
+		if err := store.Delete(a); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		assertGet(t, store, b, "k", "b")
+	})
+
+	t.Run("DeleteRemovesAllKeys", func(t *testing.T) {
+		id := newID()
+		mustSet(t, store, id, "a", "1")
+		mustSet(t, store, id, "b", "2")
+		if err := store.Delete(id); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		assertGet(t, store, id, "a", "")
+		assertGet(t, store, id, "b", "")
+	})
+
+	t.Run("DeleteUnknown", func(t *testing.T) {
+		if err := store.Delete(newID()); err != nil {
+			t.Errorf("Delete of unknown session: %v", err)
+		}
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		id := newID()
+		var wg sync.WaitGroup
+		for i := range 16 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				key := fmt.Sprintf("k%d", i)
+				if err := store.Set(id, key, key); err != nil {
+					t.Errorf("Set: %v", err)
+					return
+				}
+				if _, err := store.Get(id, key); err != nil {
+					t.Errorf("Get: %v", err)
+				}
+			}()
+		}
+		wg.Wait()
+		for i := range 16 {
+			key := fmt.Sprintf("k%d", i)
+			assertGet(t, store, id, key, key)
+		}
+	})
+
+	if es, ok := store.(webx.ExpiringSessionStore); ok {
+		t.Run("ExpireFuture", func(t *testing.T) {
+			id := newID()
+			mustSet(t, es, id, "k", "v")
+			if err := es.Expire(id, time.Now().Add(time.Hour)); err != nil {
+				t.Fatalf("Expire: %v", err)
+			}
+			assertGet(t, es, id, "k", "v")
+		})
+
+		t.Run("ExpirePast", func(t *testing.T) {
+			id := newID()
+			mustSet(t, es, id, "k", "v")
+			if err := es.Expire(id, time.Now().Add(-time.Second)); err != nil {
+				t.Fatalf("Expire: %v", err)
+			}
+			assertGet(t, es, id, "k", "")
+		})
+
+		t.Run("ExpireElapses", func(t *testing.T) {
+			id := newID()
+			mustSet(t, es, id, "k", "v")
+			if err := es.Expire(id, time.Now().Add(50*time.Millisecond)); err != nil {
+				t.Fatalf("Expire: %v", err)
+			}
+			time.Sleep(100 * time.Millisecond)
+			assertGet(t, es, id, "k", "")
+		})
+
+		t.Run("ExpireUnknown", func(t *testing.T) {
+			if err := es.Expire(newID(), time.Now().Add(time.Hour)); err != nil {
+				t.Errorf("Expire of unknown session: %v", err)
+			}
+		})
+	}
+
+	if rs, ok := store.(webx.RotatingSessionStore); ok {
+		t.Run("Rotate", func(t *testing.T) {
+			oldID, newIDv := newID(), newID()
+			mustSet(t, rs, oldID, "a", "1")
+			mustSet(t, rs, oldID, "b", "2")
+			if err := rs.Rotate(oldID, newIDv); err != nil {
+				t.Fatalf("Rotate: %v", err)
+			}
+			assertGet(t, rs, oldID, "a", "")
+			assertGet(t, rs, newIDv, "a", "1")
+			assertGet(t, rs, newIDv, "b", "2")
+		})
+
+		t.Run("RotateUnknown", func(t *testing.T) {
+			if err := rs.Rotate(newID(), newID()); err != nil {
+				t.Errorf("Rotate of unknown session: %v", err)
+			}
+		})
+
+		if es, ok := store.(webx.ExpiringSessionStore); ok {
+			t.Run("RotateKeepsExpiry", func(t *testing.T) {
+				oldID, newIDv := newID(), newID()
+				mustSet(t, rs, oldID, "k", "v")
+				if err := es.Expire(oldID, time.Now().Add(50*time.Millisecond)); err != nil {
+					t.Fatalf("Expire: %v", err)
+				}
+				if err := rs.Rotate(oldID, newIDv); err != nil {
+					t.Fatalf("Rotate: %v", err)
+				}
+				time.Sleep(100 * time.Millisecond)
+				assertGet(t, rs, newIDv, "k", "")
+			})
+		}
+	}
+
+	if ls, ok := store.(webx.SessionLister); ok {
+		conformLister(t, ls)
+	}
+}
+
+func conformLister(t *testing.T, ls webx.SessionLister) {
+	t.Run("ListSessions", func(t *testing.T) {
+		user, other := newID(), newID()
+		older, newer, foreign := newID(), newID(), newID()
+		now := time.Now().Unix()
+		seed(t, ls, older, user, now-100, now-50, "Firefox")
+		seed(t, ls, newer, user, now-10, now-1, "Safari")
+		seed(t, ls, foreign, other, now, now, "Chrome")
+		mustSet(t, ls, newID(), "unrelated", "x")
+
+		got := mustList(t, ls, user)
+		if len(got) != 2 {
+			t.Fatalf("ListSessions returned %d sessions, want 2", len(got))
+		}
+		if got[0].ID != newer || got[1].ID != older {
+			t.Errorf("order = [%s %s], want most recently seen first", got[0].ID, got[1].ID)
+		}
+		if got[0].UserID != user || got[0].UserAgent != "Safari" || got[0].LastSeen.Unix() != now-1 || got[0].Created.Unix() != now-10 {
+			t.Errorf("metadata not reported: %+v", got[0])
+		}
+	})
+
+	t.Run("ListSessionsEmptyUser", func(t *testing.T) {
+		if got := mustList(t, ls, ""); len(got) != 0 {
+			t.Errorf("ListSessions(\"\") = %d sessions, want 0", len(got))
+		}
+	})
+
+	t.Run("ListSessionsAfterDelete", func(t *testing.T) {
+		user, a, b := newID(), newID(), newID()
+		seed(t, ls, a, user, 1, 1, "")
+		seed(t, ls, b, user, 1, 2, "")
+		if err := ls.Delete(a); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		got := mustList(t, ls, user)
+		if len(got) != 1 || got[0].ID != b {
+			t.Errorf("ListSessions after Delete = %v, want only %s", got, b)
+		}
+	})
+
+	t.Run("ListSessionsUserChanged", func(t *testing.T) {
+		first, second, id := newID(), newID(), newID()
+		seed(t, ls, id, first, 1, 1, "")
+		mustSet(t, ls, id, webx.SessionUserKey, second)
+		if got := mustList(t, ls, first); len(got) != 0 {
+			t.Errorf("previous user still lists %d sessions", len(got))
+		}
+		if got := mustList(t, ls, second); len(got) != 1 {
+			t.Errorf("new user lists %d sessions, want 1", len(got))
+		}
+	})
+
+	if es, ok := ls.(webx.ExpiringSessionStore); ok {
+		t.Run("ListSessionsSkipsExpired", func(t *testing.T) {
+			user, live, dead := newID(), newID(), newID()
+			seed(t, ls, live, user, 1, 1, "")
+			seed(t, ls, dead, user, 1, 1, "")
+			if err := es.Expire(dead, time.Now().Add(-time.Second)); err != nil {
+				t.Fatalf("Expire: %v", err)
+			}
+			got := mustList(t, ls, user)
+			if len(got) != 1 || got[0].ID != live {
+				t.Errorf("ListSessions = %v, want only %s", got, live)
+			}
+		})
+	}
+
+	if rs, ok := ls.(webx.RotatingSessionStore); ok {
+		t.Run("ListSessionsAfterRotate", func(t *testing.T) {
+			user, oldID, rotated := newID(), newID(), newID()
+			seed(t, ls, oldID, user, 1, 1, "")
+			if err := rs.Rotate(oldID, rotated); err != nil {
+				t.Fatalf("Rotate: %v", err)
+			}
+			got := mustList(t, ls, user)
+			if len(got) != 1 || got[0].ID != rotated {
+				t.Errorf("ListSessions after Rotate = %v, want only %s", got, rotated)
+			}
+		})
+	}
+}
+
+// seed writes the keys SessionMiddleware and SetSessionUser would write.
+func seed(t *testing.T, store webx.SessionStore, id, user string, created, lastSeen int64, userAgent string) {
+	t.Helper()
+	mustSet(t, store, id, webx.SessionCreatedKey, strconv.FormatInt(created, 10))
+	mustSet(t, store, id, webx.SessionLastSeenKey, strconv.FormatInt(lastSeen, 10))
+	mustSet(t, store, id, webx.SessionUserAgentKey, userAgent)
+	mustSet(t, store, id, webx.SessionUserKey, user)
+}
+
+func mustList(t *testing.T, ls webx.SessionLister, user string) []webx.SessionInfo {
+	t.Helper()
+	got, err := ls.ListSessions(user)
+	if err != nil {
+		t.Fatalf("ListSessions: %v", err)
+	}
+	return got
+}
+
+func newID() string {
+	return rand.Text()
+}
+
+func mustSet(t *testing.T, store webx.SessionStore, id, key, value string) {
+	t.Helper()
+	if err := store.Set(id, key, value); err != nil {
+		t.Fatalf("Set(%q, %q): %v", id, key, err)
+	}
+}
+
+func assertGet(t *testing.T, store webx.SessionStore, id, key, want string) {
+	t.Helper()
+	got, err := store.Get(id, key)
+	if err != nil {
+		t.Fatalf("Get(%q, %q): %v", id, key, err)
+	}
+	if got != want {
+		t.Errorf("Get(%q, %q) = %q, want %q", id, key, got, want)
+	}
+}
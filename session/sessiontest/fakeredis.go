@@ -0,0 +1,260 @@
+package sessiontest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// FakeRedis is an in-process server speaking the subset of the Redis
+// protocol used by session stores: PING, AUTH, SELECT, HGET, HGETALL, HSET,
+// DEL, PEXPIREAT, RENAME, SADD, SREM and SMEMBERS. Hashes and sets live in
+// memory and expire like they would in Redis.
+type FakeRedis struct {
+	ln net.Listener
+
+	mu     sync.Mutex
+	hashes map[string]map[string]string
+	sets   map[string]map[string]struct{}
+	expiry map[string]time.Time
+}
+
+// NewFakeRedis starts a FakeRedis on a random local port and stops it when
+// the test ends.
+func NewFakeRedis(t testing.TB) *FakeRedis {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("fake redis: listen: %v", err)
+	}
+	f := &FakeRedis{
+		ln:     ln,
+		hashes: map[string]map[string]string{},
+		sets:   map[string]map[string]struct{}{},
+		expiry: map[string]time.Time{},
+	}
+	go f.serve()
+	t.Cleanup(func() { ln.Close() })
+	return f
+}
+
+// Addr returns the host:port to connect to.
+func (f *FakeRedis) Addr() string {
+	return f.ln.Addr().String()
+}
+
+func (f *FakeRedis) serve() {
+	for {
+		conn, err := f.ln.Accept()
+		if err != nil {
+			return
+		}
+		go f.handle(conn)
+	}
+}
+
+func (f *FakeRedis) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		f.exec(w, args)
+		if err := w.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// readCommand reads one RESP array of bulk strings.
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if !strings.HasPrefix(line, "*") {
+		return nil, fmt.Errorf("expected array, got %q", line)
+	}
+	n, err := strconv.Atoi(line[1:])
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		hdr, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSuffix(hdr, "\r\n")[1:])
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func (f *FakeRedis) exec(w *bufio.Writer, args []string) {
+	if len(args) == 0 {
+		writeError(w, "ERR empty command")
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.evict(time.Now())
+
+	cmd := strings.ToUpper(args[0])
+	argc := map[string]int{
+		"PING": 1, "AUTH": 2, "SELECT": 2, "HGET": 3, "HGETALL": 2, "PEXPIREAT": 3,
+		"RENAME": 3, "SMEMBERS": 2,
+	}
+	if want, ok := argc[cmd]; ok && len(args) != want {
+		writeError(w, fmt.Sprintf("ERR wrong number of arguments for '%s' command", strings.ToLower(cmd)))
+		return
+	}
+
+	switch cmd {
+	case "PING", "AUTH", "SELECT":
+		writeSimple(w, "OK")
+	case "HGET":
+		v, ok := f.hashes[args[1]][args[2]]
+		if !ok {
+			writeNil(w)
+			return
+		}
+		writeBulk(w, v)
+	case "HGETALL":
+		h := f.hashes[args[1]]
+		fmt.Fprintf(w, "*%d\r\n", len(h)*2)
+		for k, v := range h {
+			writeBulk(w, k)
+			writeBulk(w, v)
+		}
+	case "HSET":
+		if len(args) < 4 || len(args)%2 != 0 {
+			writeError(w, "ERR wrong number of arguments for 'hset' command")
+			return
+		}
+		h := f.hashes[args[1]]
+		if h == nil {
+			h = map[string]string{}
+			f.hashes[args[1]] = h
+		}
+		added := 0
+		for i := 2; i < len(args); i += 2 {
+			if _, ok := h[args[i]]; !ok {
+				added++
+			}
+			h[args[i]] = args[i+1]
+		}
+		writeInt(w, added)
+	case "DEL":
+		n := 0
+		for _, key := range args[1:] {
+			if f.exists(key) {
+				n++
+				f.remove(key)
+			}
+		}
+		writeInt(w, n)
+	case "PEXPIREAT":
+		ms, err := strconv.ParseInt(args[2], 10, 64)
+		if err != nil {
+			writeError(w, "ERR value is not an integer or out of range")
+			return
+		}
+		if !f.exists(args[1]) {
+			writeInt(w, 0)
+			return
+		}
+		f.expiry[args[1]] = time.UnixMilli(ms)
+		f.evict(time.Now())
+		writeInt(w, 1)
+	case "RENAME":
+		if !f.exists(args[1]) {
+			writeError(w, "ERR no such key")
+			return
+		}
+		f.remove(args[2])
+		if h, ok := f.hashes[args[1]]; ok {
+			f.hashes[args[2]] = h
+		}
+		if set, ok := f.sets[args[1]]; ok {
+			f.sets[args[2]] = set
+		}
+		if at, ok := f.expiry[args[1]]; ok {
+			f.expiry[args[2]] = at
+		}
+		f.remove(args[1])
+		writeSimple(w, "OK")
+	case "SADD", "SREM":
+		if len(args) < 3 {
+			writeError(w, fmt.Sprintf("ERR wrong number of arguments for '%s' command", strings.ToLower(cmd)))
+			return
+		}
+		set := f.sets[args[1]]
+		if set == nil {
+			set = map[string]struct{}{}
+			f.sets[args[1]] = set
+		}
+		n := 0
+		for _, m := range args[2:] {
+			_, had := set[m]
+			if cmd == "SADD" && !had {
+				set[m] = struct{}{}
+				n++
+			}
+			if cmd == "SREM" && had {
+				delete(set, m)
+				n++
+			}
+		}
+		writeInt(w, n)
+	case "SMEMBERS":
+		set := f.sets[args[1]]
+		fmt.Fprintf(w, "*%d\r\n", len(set))
+		for m := range set {
+			writeBulk(w, m)
+		}
+	default:
+		writeError(w, fmt.Sprintf("ERR unknown command '%s'", args[0]))
+	}
+}
+
+func (f *FakeRedis) exists(key string) bool {
+	return len(f.hashes[key]) > 0 || len(f.sets[key]) > 0
+}
+
+func (f *FakeRedis) remove(key string) {
+	delete(f.hashes, key)
+	delete(f.sets, key)
+	delete(f.expiry, key)
+}
+
+func (f *FakeRedis) evict(now time.Time) {
+	for key, at := range f.expiry {
+		if !now.Before(at) {
+			f.remove(key)
+		}
+	}
+}
+
+func writeSimple(w *bufio.Writer, s string) { fmt.Fprintf(w, "+%s\r\n", s) }
+func writeError(w *bufio.Writer, s string)  { fmt.Fprintf(w, "-%s\r\n", s) }
+func writeInt(w *bufio.Writer, n int)       { fmt.Fprintf(w, ":%d\r\n", n) }
+func writeNil(w *bufio.Writer)              { w.WriteString("$-1\r\n") }
+func writeBulk(w *bufio.Writer, s string)   { fmt.Fprintf(w, "$%d\r\n%s\r\n", len(s), s) }
@@ -0,0 +1,134 @@
+package webx
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// SessionUserKey links a session to a user ID. Set it with SetSessionUser
+// after login so SessionLister stores can find the user's sessions.
+const SessionUserKey = "session_user"
+
+// SessionInfo describes one active session for display and revocation.
+type SessionInfo struct {
+	ID        string
+	UserID    string
+	Created   time.Time
+	LastSeen  time.Time
+	UserAgent string
+}
+
+// Handle returns an opaque identifier for the session that is safe to embed
+// in HTML. The raw ID is a bearer credential and must never leave the server.
+func (si SessionInfo) Handle() string {
+	sum := sha256.Sum256([]byte(si.ID))
+	return hex.EncodeToString(sum[:12])
+}
+
+// SessionInfoFromValues builds a SessionInfo from a session's raw key/value
+// map. Store implementations use it so every store reports the same fields.
+func SessionInfoFromValues(sessionID string, values map[string]string) SessionInfo {
+	created := parseUnix(values[SessionCreatedKey])
+	lastSeen := parseUnix(values[SessionLastSeenKey])
+	if lastSeen.IsZero() {
+		lastSeen = created
+	}
+	return SessionInfo{
+		ID:        sessionID,
+		UserID:    values[SessionUserKey],
+		Created:   created,
+		LastSeen:  lastSeen,
+		UserAgent: values[SessionUserAgentKey],
+	}
+}
+
+// SessionLister is implemented by stores that can enumerate the live
+// sessions of a user, enabling "where am I logged in" pages and forced
+// logout. Stateless stores such as cookie stores cannot implement it.
+type SessionLister interface {
+	SessionStore
+	// ListSessions returns the unexpired sessions whose SessionUserKey is
+	// userID, most recently seen first.
+	ListSessions(userID string) ([]SessionInfo, error)
+}
+
+// ErrSessionsNotListable is returned when the session store does not
+// implement SessionLister.
+var ErrSessionsNotListable = errors.New("webx: session store cannot list sessions")
+
+// SetSessionUser links the current session to userID. Call RotateSession
+// first when this marks a login.
+func SetSessionUser(r *http.Request, userID string) error {
+	wctx := FromContext(r.Context())
+	store := wctx.SessionStore()
+	if store == nil {
+		return ErrNoSession
+	}
+	return store.Set(wctx.SessionID, SessionUserKey, userID)
+}
+
+// SessionUser returns the user ID linked to the current session, or "" for
+// anonymous sessions.
+func SessionUser(r *http.Request) (string, error) {
+	wctx := FromContext(r.Context())
+	store := wctx.SessionStore()
+	if store == nil {
+		return "", ErrNoSession
+	}
+	return store.Get(wctx.SessionID, SessionUserKey)
+}
+
+// ListUserSessions lists the sessions of the user linked to the current
+// session.
+func ListUserSessions(r *http.Request) ([]SessionInfo, error) {
+	wctx := FromContext(r.Context())
+	lister, ok := wctx.SessionStore().(SessionLister)
+	if !ok {
+		return nil, ErrSessionsNotListable
+	}
+	userID, err := SessionUser(r)
+	if err != nil {
+		return nil, err
+	}
+	if userID == "" {
+		return nil, nil
+	}
+	return lister.ListSessions(userID)
+}
+
+// RevokeSessions deletes every session of userID whose handle is in handles.
+// With no handles it deletes all of the user's sessions except those whose
+// ID is in keep (typically the caller's own session). It returns the number
+// of sessions revoked.
+func RevokeSessions(lister SessionLister, userID string, keep []string, handles ...string) (int, error) {
+	sessions, err := lister.ListSessions(userID)
+	if err != nil {
+		return 0, fmt.Errorf("listing sessions: %w", err)
+	}
+	n := 0
+	for _, s := range sessions {
+		if slices.Contains(keep, s.ID) {
+			continue
+		}
+		if len(handles) > 0 && !slices.Contains(handles, s.Handle()) {
+			continue
+		}
+		if err := lister.Delete(s.ID); err != nil {
+			return n, fmt.Errorf("revoking session: %w", err)
+		}
+		n++
+	}
+	return n, nil
+}
+
+// SortSessions orders sessions most recently seen first.
+func SortSessions(sessions []SessionInfo) {
+	slices.SortFunc(sessions, func(a, b SessionInfo) int {
+		return b.LastSeen.Compare(a.LastSeen)
+	})
+}
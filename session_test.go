@@ -0,0 +1,274 @@
+package webx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mapStore is a minimal SessionStore for tests. It implements neither of the
+// optional interfaces so the fallback paths are exercised.
+type mapStore struct {
+	mu   sync.Mutex
+	data map[string]map[string]string
+}
+
+func newMapStore() *mapStore {
+	return &mapStore{data: map[string]map[string]string{}}
+}
+
+func (s *mapStore) Get(sessionID, key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data[sessionID][key], nil
+}
+
+func (s *mapStore) Set(sessionID, key, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data[sessionID] == nil {
+		s.data[sessionID] = map[string]string{}
+	}
+	s.data[sessionID][key] = value
+	return nil
+}
+
+func (s *mapStore) Delete(sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data, sessionID)
+	return nil
+}
+
+// rotatingStore adds RotatingSessionStore on top of mapStore.
+type rotatingStore struct{ *mapStore }
+
+func (s rotatingStore) Rotate(oldID, newID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[newID] = s.data[oldID]
+	delete(s.data, oldID)
+	return nil
+}
+
+func withClock(t *testing.T, now *time.Time) {
+	t.Helper()
+	orig := timeNow
+	timeNow = func() time.Time { return *now }
+	t.Cleanup(func() { timeNow = orig })
+}
+
+// serve runs one request through SessionMiddleware and returns the recorder
+// and the WebXContext the handler saw.
+func serve(t *testing.T, mw func(http.Handler) http.Handler, req *http.Request, h func(http.ResponseWriter, *http.Request)) (*httptest.ResponseRecorder, *WebXContext) {
+	t.Helper()
+	var seen *WebXContext
+	rec := httptest.NewRecorder()
+	mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = FromContext(r.Context())
+		if h != nil {
+			h(w, r)
+		}
+	})).ServeHTTP(rec, req)
+	return rec, seen
+}
+
+func sessionCookie(t *testing.T, rec *httptest.ResponseRecorder, name string) *http.Cookie {
+	t.Helper()
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func TestSessionMiddleware_NewSessionCookie(t *testing.T) {
+	mw := SessionMiddleware(newMapStore(), SessionOptions{
+		Secure:   true,
+		Domain:   "example.com",
+		SameSite: http.SameSiteStrictMode,
+		TTL:      time.Hour,
+	})
+	rec, wctx := serve(t, mw, httptest.NewRequest(http.MethodGet, "/", nil), nil)
+
+	c := sessionCookie(t, rec, sessionCookieName)
+	if c == nil {
+		t.Fatal("expected session cookie")
+	}
+	if !c.Secure || !c.HttpOnly || c.Domain != "example.com" || c.SameSite != http.SameSiteStrictMode {
+		t.Errorf("cookie attributes not applied: %+v", c)
+	}
+	if c.MaxAge != 3600 {
+		t.Errorf("MaxAge = %d, want 3600", c.MaxAge)
+	}
+	if wctx.SessionID != c.Value || wctx.CSRFToken == "" {
+		t.Errorf("context not populated: %+v", wctx)
+	}
+}
+
+func TestSessionMiddleware_UnknownIDReplaced(t *testing.T) {
+	mw := SessionMiddleware(newMapStore())
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: "attacker-chosen"})
+
+	rec, wctx := serve(t, mw, req, nil)
+	if wctx.SessionID == "attacker-chosen" {
+		t.Fatal("unknown session ID must not be adopted")
+	}
+	if c := sessionCookie(t, rec, sessionCookieName); c == nil || c.Value != wctx.SessionID {
+		t.Error("expected a replacement cookie")
+	}
+}
+
+func TestSessionMiddleware_Expiry(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    SessionOptions
+		advance []time.Duration // time between consecutive requests
+		expired bool
+	}{
+		{"within ttl", SessionOptions{TTL: time.Hour}, []time.Duration{30 * time.Minute}, false},
+		{"past ttl", SessionOptions{TTL: time.Hour}, []time.Duration{time.Hour}, true},
+		{"within idle", SessionOptions{IdleTimeout: 10 * time.Minute}, []time.Duration{5 * time.Minute, 5 * time.Minute, 5 * time.Minute}, false},
+		{"past idle", SessionOptions{IdleTimeout: 10 * time.Minute}, []time.Duration{11 * time.Minute}, true},
+		{"ttl beats activity", SessionOptions{TTL: 20 * time.Minute, IdleTimeout: 10 * time.Minute}, []time.Duration{9 * time.Minute, 9 * time.Minute, 9 * time.Minute}, true},
+		{"no limits", SessionOptions{}, []time.Duration{365 * 24 * time.Hour}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Unix(1_700_000_000, 0)
+			withClock(t, &now)
+			mw := SessionMiddleware(newMapStore(), tt.opts)
+
+			_, first := serve(t, mw, httptest.NewRequest(http.MethodGet, "/", nil), nil)
+			id := first.SessionID
+
+			var last *WebXContext
+			for _, d := range tt.advance {
+				now = now.Add(d)
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: id})
+				_, last = serve(t, mw, req, nil)
+				if last.SessionID != id {
+					break
+				}
+			}
+
+			if got := last.SessionID != id; got != tt.expired {
+				t.Errorf("expired = %v, want %v", got, tt.expired)
+			}
+		})
+	}
+}
+
+func TestSessionMiddleware_CSRF(t *testing.T) {
+	mw := SessionMiddleware(newMapStore())
+	_, first := serve(t, mw, httptest.NewRequest(http.MethodGet, "/", nil), nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: first.SessionID})
+	rec, _ := serve(t, mw, req, nil)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("missing token: status = %d, want 403", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", nil)
+	req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: first.SessionID})
+	req.Header.Set("X-CSRF-Token", first.CSRFToken)
+	rec, _ = serve(t, mw, req, nil)
+	if rec.Code != http.StatusOK {
+		t.Errorf("valid token: status = %d, want 200", rec.Code)
+	}
+}
+
+func TestRotateSession(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		store     SessionStore
+		keepsData bool
+	}{
+		{"fallback", newMapStore(), false},
+		{"rotating store", rotatingStore{newMapStore()}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mw := SessionMiddleware(tc.store, SessionOptions{CookieName: "sid"})
+			_, first := serve(t, mw, httptest.NewRequest(http.MethodGet, "/", nil), nil)
+			oldID, oldToken := first.SessionID, first.CSRFToken
+			if err := tc.store.Set(oldID, "user", "alice"); err != nil {
+				t.Fatal(err)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/login", nil)
+			req.AddCookie(&http.Cookie{Name: "sid", Value: oldID})
+			rec, wctx := serve(t, mw, req, func(w http.ResponseWriter, r *http.Request) {
+				if err := RotateSession(w, r); err != nil {
+					t.Fatalf("RotateSession: %v", err)
+				}
+			})
+
+			if wctx.SessionID == oldID || wctx.CSRFToken == oldToken {
+				t.Fatal("expected new session ID and CSRF token")
+			}
+			c := sessionCookie(t, rec, "sid")
+			if c == nil || c.Value != wctx.SessionID {
+				t.Fatalf("expected rotated cookie, got %+v", c)
+			}
+			if v, _ := tc.store.Get(oldID, "user"); v != "" {
+				t.Error("old session must be gone")
+			}
+			if v, _ := tc.store.Get(wctx.SessionID, "user"); (v == "alice") != tc.keepsData {
+				t.Errorf("user after rotation = %q, keepsData = %v", v, tc.keepsData)
+			}
+
+			// The old ID is now unknown and must be replaced.
+			req = httptest.NewRequest(http.MethodGet, "/", nil)
+			req.AddCookie(&http.Cookie{Name: "sid", Value: oldID})
+			if _, again := serve(t, mw, req, nil); again.SessionID == oldID {
+				t.Error("old session ID accepted after rotation")
+			}
+		})
+	}
+}
+
+func TestRotateSession_KeepsAbsoluteLifetime(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	withClock(t, &now)
+	mw := SessionMiddleware(newMapStore(), SessionOptions{TTL: time.Hour})
+	_, first := serve(t, mw, httptest.NewRequest(http.MethodGet, "/", nil), nil)
+
+	// Rotate every half hour, as a user switching roles would.
+	id := first.SessionID
+	for range 2 {
+		now = now.Add(30*time.Minute - time.Second)
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: id})
+		_, wctx := serve(t, mw, req, func(w http.ResponseWriter, r *http.Request) {
+			if err := RotateSession(w, r); err != nil {
+				t.Fatalf("RotateSession: %v", err)
+			}
+		})
+		if wctx.SessionID == id {
+			t.Fatal("session was not rotated")
+		}
+		id = wctx.SessionID
+	}
+
+	now = now.Add(2 * time.Second)
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: id})
+	if _, wctx := serve(t, mw, req, nil); wctx.SessionID == id {
+		t.Error("rotated session outlived the TTL of the original one")
+	}
+}
+
+func TestRotateSession_NoMiddleware(t *testing.T) {
+	err := RotateSession(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if err == nil || !strings.Contains(err.Error(), "no session") {
+		t.Errorf("err = %v, want ErrNoSession", err)
+	}
+}
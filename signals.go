@@ -0,0 +1,260 @@
+package webx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/a-h/templ"
+	"github.com/starfederation/datastar-go/datastar"
+)
+
+// DefaultMaxSignalBytes is the request body limit applied by SignalHandler
+// when SignalHandlerOptions.MaxBytes is zero.
+const DefaultMaxSignalBytes = 1 << 20
+
+// ErrMissingSignals is returned by ReadSignals when the request carries no
+// signals for the component.
+var ErrMissingSignals = errors.New("webx: missing signals for component")
+
+// SignalID returns the namespace a component's signals live under: its ID
+// with hyphens replaced by underscores, as done by utils.Signals.
+func SignalID(componentID string) string {
+	return strings.ReplaceAll(componentID, "-", "_")
+}
+
+// ReadSignals decodes the signals of one component into dest. Datastar sends
+// every signal on the page, namespaced by component:
+//
+//	{"login_form": {"email": "...", "password": "..."}, "other": {...}}
+//
+// so ReadSignals(r, "login-form", &signals) fills signals from the
+// "login_form" object. It returns an error wrapping ErrMissingSignals when
+// that object is absent.
+func ReadSignals(r *http.Request, componentID string, dest any) error {
+	var wrapper map[string]json.RawMessage
+	if err := datastar.ReadSignals(r, &wrapper); err != nil {
+		return fmt.Errorf("read signals: %w", err)
+	}
+	id := SignalID(componentID)
+	raw, ok := wrapper[id]
+	if !ok {
+		return fmt.Errorf("%w %q", ErrMissingSignals, id)
+	}
+	if err := json.Unmarshal(raw, dest); err != nil {
+		return fmt.Errorf("decode signals for %q: %w", id, err)
+	}
+	return nil
+}
+
+// SignalError is an error with a status and a message that is safe to show
+// to the user. Return one from a SignalFunc to control what the client sees;
+// any other error is logged and reported as a generic failure.
+type SignalError struct {
+	Status  int
+	Message string
+}
+
+func (e *SignalError) Error() string { return e.Message }
+
+// SignalErrorf returns a *SignalError with a formatted message.
+//
+//	return webx.SignalErrorf(http.StatusUnprocessableEntity, "amount must be positive")
+func SignalErrorf(status int, format string, args ...any) error {
+	return &SignalError{Status: status, Message: fmt.Sprintf(format, args...)}
+}
+
+// SignalFunc handles one request carrying the signals of component id,
+// decoded into in. It answers through sse.
+type SignalFunc[T any] func(ctx context.Context, id string, in T, sse *SignalSSE) error
+
+// SignalHandlerOptions configures SignalHandler.
+type SignalHandlerOptions struct {
+	// ID is the component ID the handler serves. When empty, it is read from
+	// the "id" query parameter, so one endpoint can serve many instances.
+	ID string
+	// MaxBytes limits the request body. Defaults to DefaultMaxSignalBytes;
+	// negative disables the limit.
+	MaxBytes int64
+	// OnError reports an error returned by the SignalFunc. Defaults to
+	// ReportSignalError.
+	OnError func(sse *SignalSSE, err error)
+}
+
+func resolveSignalHandlerOptions(opts []SignalHandlerOptions) SignalHandlerOptions {
+	var o SignalHandlerOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if o.MaxBytes == 0 {
+		o.MaxBytes = DefaultMaxSignalBytes
+	}
+	if o.OnError == nil {
+		o.OnError = ReportSignalError
+	}
+	return o
+}
+
+// SignalHandler returns an http.HandlerFunc that reads the signals of one
+// component into a T and passes them to fn:
+//
+//	type searchSignals struct {
+//	    Query string `json:"query"`
+//	}
+//
+//	r.Get("/api/search", webx.SignalHandler(func(ctx context.Context, id string, in searchSignals, sse *webx.SignalSSE) error {
+//	    results, err := search(ctx, in.Query)
+//	    if err != nil {
+//	        return err
+//	    }
+//	    return sse.PatchElementTempl(Results(id, results))
+//	}))
+//
+// Requests without a component ID or without signals for it are rejected
+// with 400, and bodies over the limit with 413, before fn runs. Errors
+// returned by fn go to SignalHandlerOptions.OnError.
+//
+// The request body is buffered, so fn can still read the request through
+// sse.Request(), for example with ReadSignals for a second component.
+func SignalHandler[T any](fn SignalFunc[T], opts ...SignalHandlerOptions) http.HandlerFunc {
+	o := resolveSignalHandlerOptions(opts)
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := o.ID
+		if id == "" {
+			id = r.URL.Query().Get("id")
+		}
+		if id == "" {
+			http.Error(w, "missing id query parameter", http.StatusBadRequest)
+			return
+		}
+
+		body, err := bufferBody(w, r, o.MaxBytes)
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, fmt.Sprintf("read signals: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		var in T
+		err = ReadSignals(r, id, &in)
+		if body != nil {
+			r.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		if errors.Is(err, ErrMissingSignals) {
+			http.Error(w, fmt.Sprintf("missing signals for %q", SignalID(id)), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		sse := &SignalSSE{ID: id, w: w, r: r}
+		if err := fn(r.Context(), id, in, sse); err != nil {
+			o.OnError(sse, err)
+		}
+	}
+}
+
+// bufferBody reads the request body up to limit and replaces it with an
+// in-memory copy. It returns nil for requests without a body, whose signals
+// travel in the query string.
+func bufferBody(w http.ResponseWriter, r *http.Request, limit int64) ([]byte, error) {
+	if r.Method == http.MethodGet || r.Body == nil || r.Body == http.NoBody {
+		return nil, nil
+	}
+	var src io.Reader = r.Body
+	if limit > 0 {
+		src = http.MaxBytesReader(w, r.Body, limit)
+	}
+	body, err := io.ReadAll(src)
+	if err != nil {
+		return nil, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// ReportSignalError is the default SignalHandlerOptions.OnError. A
+// *SignalError is shown as is; other errors are logged and shown as a
+// generic failure. Before the response has started the error is sent as a
+// plain HTTP error; after that it is patched into the component's "error"
+// signal. Errors caused by the client going away are ignored.
+func ReportSignalError(sse *SignalSSE, err error) {
+	ctx := sse.r.Context()
+	if ctx.Err() != nil {
+		return
+	}
+	var se *SignalError
+	if !errors.As(err, &se) {
+		slog.ErrorContext(ctx, "webx: signal handler failed", "id", sse.ID, "path", sse.r.URL.Path, "error", err)
+		se = &SignalError{Status: http.StatusInternalServerError, Message: "Something went wrong"}
+	}
+	if !sse.Started() {
+		http.Error(sse.w, se.Message, se.Status)
+		return
+	}
+	sse.PatchSignals(map[string]any{"error": se.Message})
+}
+
+// SignalSSE answers a SignalHandler request. The SSE stream is opened by
+// the first patch, so a SignalFunc that fails before patching anything still
+// gets a proper HTTP error status.
+type SignalSSE struct {
+	// ID is the component ID as rendered, with hyphens.
+	ID string
+
+	w   http.ResponseWriter
+	r   *http.Request
+	sse *datastar.ServerSentEventGenerator
+}
+
+// Request returns the request being handled.
+func (s *SignalSSE) Request() *http.Request { return s.r }
+
+// Started reports whether the SSE stream has been opened.
+func (s *SignalSSE) Started() bool { return s.sse != nil }
+
+// Generator opens the SSE stream if needed and returns it, for patches not
+// covered by the helpers.
+func (s *SignalSSE) Generator() *datastar.ServerSentEventGenerator {
+	if s.sse == nil {
+		s.sse = datastar.NewSSE(s.w, s.r)
+	}
+	return s.sse
+}
+
+// PatchSignals patches signals inside the component's namespace, so
+//
+//	sse.PatchSignals(map[string]any{"valid": true})
+//
+// on component "email-input" sends {"email_input": {"valid": true}}.
+func (s *SignalSSE) PatchSignals(signals any, opts ...datastar.PatchSignalsOption) error {
+	return s.Generator().MarshalAndPatchSignals(map[string]any{SignalID(s.ID): signals}, opts...)
+}
+
+// PatchElements patches pre-rendered HTML.
+func (s *SignalSSE) PatchElements(elements string, opts ...datastar.PatchElementOption) error {
+	return s.Generator().PatchElements(elements, opts...)
+}
+
+// PatchElementTempl renders c with the request context and patches it.
+func (s *SignalSSE) PatchElementTempl(c templ.Component, opts ...datastar.PatchElementOption) error {
+	return s.Generator().PatchElementTempl(c, opts...)
+}
+
+// PatchInner replaces the contents of the component's sub-element whose ID
+// is the component ID followed by suffix, such as "-preview".
+func (s *SignalSSE) PatchInner(suffix, elements string) error {
+	return s.PatchElements(elements, datastar.WithSelectorID(s.ID+suffix), datastar.WithModeInner())
+}
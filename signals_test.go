@@ -0,0 +1,120 @@
+package webx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type echoSignals struct {
+	Value string `json:"value"`
+}
+
+func echoHandler(opts ...SignalHandlerOptions) http.HandlerFunc {
+	return SignalHandler(func(_ context.Context, id string, in echoSignals, sse *SignalSSE) error {
+		switch in.Value {
+		case "bad":
+			return SignalErrorf(http.StatusUnprocessableEntity, "value %q is bad", in.Value)
+		case "boom":
+			return errors.New("database exploded")
+		case "late":
+			if err := sse.PatchSignals(map[string]any{"step": 1}); err != nil {
+				return err
+			}
+			return SignalErrorf(http.StatusConflict, "changed meanwhile")
+		}
+		return sse.PatchSignals(map[string]any{"echo": id + ":" + in.Value})
+	}, opts...)
+}
+
+func getSignals(target, signals string) *http.Request {
+	return httptest.NewRequest(http.MethodGet, target+"&datastar="+url.QueryEscape(signals), nil)
+}
+
+func TestSignalHandler(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *http.Request
+		code int
+		body string
+	}{
+		{"get", getSignals("/?id=my-input", `{"my_input":{"value":"hi"}}`), http.StatusOK, `{"my_input":{"echo":"my-input:hi"}}`},
+		{"post", httptest.NewRequest(http.MethodPost, "/?id=x", strings.NewReader(`{"x":{"value":"p"}}`)), http.StatusOK, `{"x":{"echo":"x:p"}}`},
+		{"missing id", getSignals("/?", `{}`), http.StatusBadRequest, "missing id query parameter"},
+		{"missing signals", getSignals("/?id=my-input", `{"other":{}}`), http.StatusBadRequest, `missing signals for "my_input"`},
+		{"malformed", getSignals("/?id=x", `{"x":`), http.StatusBadRequest, "read signals"},
+		{"signal error", getSignals("/?id=x", `{"x":{"value":"bad"}}`), http.StatusUnprocessableEntity, `value "bad" is bad`},
+		{"internal error hidden", getSignals("/?id=x", `{"x":{"value":"boom"}}`), http.StatusInternalServerError, "Something went wrong"},
+		{"error after stream started", getSignals("/?id=x", `{"x":{"value":"late"}}`), http.StatusOK, `{"x":{"error":"changed meanwhile"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			echoHandler()(rec, tt.req)
+			if rec.Code != tt.code {
+				t.Errorf("status = %d, want %d (%s)", rec.Code, tt.code, rec.Body.String())
+			}
+			if !strings.Contains(rec.Body.String(), tt.body) {
+				t.Errorf("body = %q, want it to contain %q", rec.Body.String(), tt.body)
+			}
+			if strings.Contains(rec.Body.String(), "exploded") {
+				t.Error("internal error leaked to the client")
+			}
+		})
+	}
+}
+
+func TestSignalHandler_FixedID(t *testing.T) {
+	rec := httptest.NewRecorder()
+	echoHandler(SignalHandlerOptions{ID: "cal-1"})(rec, getSignals("/?id=ignored", `{"cal_1":{"value":"v"}}`))
+	if !strings.Contains(rec.Body.String(), `{"cal_1":{"echo":"cal-1:v"}}`) {
+		t.Errorf("body = %q", rec.Body.String())
+	}
+}
+
+func TestSignalHandler_BodyLimit(t *testing.T) {
+	big := `{"x":{"value":"` + strings.Repeat("a", 100) + `"}}`
+	rec := httptest.NewRecorder()
+	echoHandler(SignalHandlerOptions{MaxBytes: 50})(rec, httptest.NewRequest(http.MethodPost, "/?id=x", strings.NewReader(big)))
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want 413", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	echoHandler(SignalHandlerOptions{MaxBytes: -1})(rec, httptest.NewRequest(http.MethodPost, "/?id=x", strings.NewReader(big)))
+	if rec.Code != http.StatusOK {
+		t.Errorf("unlimited: status = %d, want 200", rec.Code)
+	}
+}
+
+func TestSignalHandler_BodyReadableAgain(t *testing.T) {
+	var got string
+	h := SignalHandler(func(_ context.Context, _ string, _ echoSignals, sse *SignalSSE) error {
+		var other echoSignals
+		if err := ReadSignals(sse.Request(), "other", &other); err != nil {
+			return err
+		}
+		got = other.Value
+		return nil
+	})
+	body := `{"x":{"value":"a"},"other":{"value":"b"}}`
+	h(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/?id=x", strings.NewReader(body)))
+	if got != "b" {
+		t.Errorf("second read = %q, want %q", got, "b")
+	}
+}
+
+func TestSignalHandler_OnError(t *testing.T) {
+	var reported error
+	h := echoHandler(SignalHandlerOptions{OnError: func(_ *SignalSSE, err error) {
+		reported = err
+	}})
+	h(httptest.NewRecorder(), getSignals("/?id=x", `{"x":{"value":"boom"}}`))
+	if reported == nil || reported.Error() != "database exploded" {
+		t.Errorf("OnError got %v", reported)
+	}
+}
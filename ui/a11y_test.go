@@ -0,0 +1,67 @@
+package ui_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/plaenen/webx/a11y"
+	"github.com/plaenen/webx/ui/accordion"
+	"github.com/plaenen/webx/ui/calendar"
+	"github.com/plaenen/webx/ui/drawer"
+	"github.com/plaenen/webx/ui/dropdown"
+	"github.com/plaenen/webx/ui/markdown"
+	"github.com/plaenen/webx/ui/modal"
+	"github.com/plaenen/webx/ui/tab"
+)
+
+// nest renders parent with children as its { children... }.
+func nest(parent templ.Component, children ...templ.Component) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		return parent.Render(templ.WithChildren(ctx, templ.Join(children...)), w)
+	})
+}
+
+func text(s string) templ.Component { return templ.Raw(templ.EscapeString(s)) }
+
+// TestAccessibility checks the interactive components composed the way an
+// application uses them, with their labels and content filled in.
+func TestAccessibility(t *testing.T) {
+	tests := map[string]templ.Component{
+		"modal": templ.Join(
+			nest(modal.OpenButton("confirm"), text("Delete")),
+			nest(modal.Modal(modal.Props{ID: "confirm"}),
+				nest(modal.Box(), text("Delete this item?"),
+					nest(modal.Action(), nest(modal.CloseButton("confirm"), text("Cancel")))),
+				modal.Backdrop("confirm"),
+			),
+		),
+		"dropdown": nest(dropdown.Dropdown(dropdown.Props{ID: "menu"}),
+			nest(dropdown.Trigger(dropdown.TriggerProps{DropdownID: "menu"}), text("Options")),
+			nest(dropdown.Content(dropdown.ContentProps{DropdownID: "menu"}),
+				templ.Raw(`<li><a href="/profile">Profile</a></li><li><button type="button">Sign out</button></li>`)),
+		),
+		"drawer": nest(drawer.Drawer(drawer.Props{ID: "nav"}),
+			nest(drawer.Content(), drawer.ToggleButton("nav")),
+			nest(drawer.Side(drawer.SideProps{ID: "nav"}), templ.Raw(`<a href="/">Home</a>`)),
+		),
+		"tabs": nest(tab.Tabs(),
+			nest(tab.Tab(tab.TabProps{Href: "/a", Active: true}), text("Overview")),
+			nest(tab.Tab(tab.TabProps{Href: "/b"}), text("Settings")),
+		),
+		"accordion": nest(accordion.Accordion(accordion.Props{ID: "faq"}),
+			nest(accordion.Item(accordion.ItemProps{AccordionID: "faq", Value: "a", Title: "Shipping"}), text("Two days.")),
+		),
+		"calendar": calendar.Calendar(calendar.Props{
+			ID: "due", Year: 2001, Month: time.February, Selected: "2001-02-14",
+		}),
+		"markdown": markdown.MarkdownInput(markdown.InputProps{ID: "body", Name: "body"}),
+	}
+	for name, c := range tests {
+		t.Run(name, func(t *testing.T) {
+			a11y.AssertComponent(t, c, a11y.Options{Source: name})
+		})
+	}
+}
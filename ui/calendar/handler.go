@@ -1,12 +1,11 @@
 package calendar
 
 import (
-	"fmt"
+	"context"
 	"net/http"
-	"strings"
 	"time"
 
-	"github.com/starfederation/datastar-go/datastar"
+	"github.com/plaenen/webx"
 )
 
 // NavigatePath is the standard handler path for calendar navigation.
@@ -25,76 +24,59 @@ type navigateSignals struct {
 // month navigation for a calendar component. The calendarID must match
 // the ID used when rendering the Calendar component so that
 // PatchElementTempl can morph the correct DOM node.
+//
+// A calendar rendered with SyncURL must add "sync=1" to the navigation URL,
+// so the re-rendered calendar keeps mirroring its selection in the query
+// string.
 func NavigateHandler(calendarID string, mode Mode) http.HandlerFunc {
-	return handleNavigate(calendarID, mode)
+	return webx.SignalHandler(func(_ context.Context, id string, in navigateSignals, sse *webx.SignalSSE) error {
+		return navigate(id, mode, syncURL(sse.Request()), in, sse)
+	}, webx.SignalHandlerOptions{ID: calendarID})
 }
 
 // NavigateHandlerFromQuery returns an http.HandlerFunc that reads the
-// calendar ID and mode from query parameters "id" and "mode". This is
-// useful when a single endpoint serves multiple calendar instances.
+// calendar ID, mode and SyncURL flag from query parameters "id", "mode" and
+// "sync". This is useful when a single endpoint serves multiple calendar
+// instances.
 func NavigateHandlerFromQuery() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		calendarID := r.URL.Query().Get("id")
-		if calendarID == "" {
-			http.Error(w, "missing id query parameter", http.StatusBadRequest)
-			return
-		}
-
+	return webx.SignalHandler(func(_ context.Context, id string, in navigateSignals, sse *webx.SignalSSE) error {
 		mode := ModeSingle
-		if r.URL.Query().Get("mode") == "range" {
+		if sse.Request().URL.Query().Get("mode") == "range" {
 			mode = ModeRange
 		}
-
-		handleNavigate(calendarID, mode)(w, r)
-	}
+		return navigate(id, mode, syncURL(sse.Request()), in, sse)
+	})
 }
 
-func handleNavigate(calendarID string, mode Mode) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		// Signals arrive namespaced: {"cal_id": {"year": ..., "month": ..., "direction": ...}}
-		wrapper := map[string]navigateSignals{}
-		if err := datastar.ReadSignals(r, &wrapper); err != nil {
-			http.Error(w, fmt.Sprintf("read signals: %v", err), http.StatusBadRequest)
-			return
-		}
-
-		// Extract the namespaced signals using sanitized ID (hyphens → underscores).
-		sanitizedID := strings.ReplaceAll(calendarID, "-", "_")
-		store, ok := wrapper[sanitizedID]
-		if !ok {
-			http.Error(w, fmt.Sprintf("missing signals for %q", sanitizedID), http.StatusBadRequest)
-			return
-		}
-
-		// Compute new month/year.
-		t := time.Date(store.Year, time.Month(store.Month), 1, 0, 0, 0, 0, time.UTC)
-		t = t.AddDate(0, store.Direction, 0)
-		newYear := t.Year()
-		newMonth := t.Month()
-
-		// Build the calendar props for re-rendering.
-		props := Props{
-			ID:       calendarID,
-			Year:     newYear,
-			Month:    newMonth,
-			Selected: store.Selected,
-			Mode:     mode,
-		}
+// syncURL reports whether the navigation URL carries the SyncURL flag.
+func syncURL(r *http.Request) bool {
+	return r.URL.Query().Get("sync") == "1"
+}
 
-		// Create SSE writer and send the patched element + signals.
-		sse := datastar.NewSSE(w, r)
+func navigate(calendarID string, mode Mode, sync bool, in navigateSignals, sse *webx.SignalSSE) error {
+	// Compute new month/year.
+	t := time.Date(in.Year, time.Month(in.Month), 1, 0, 0, 0, 0, time.UTC)
+	t = t.AddDate(0, in.Direction, 0)
+	newYear := t.Year()
+	newMonth := t.Month()
 
-		if err := sse.PatchElementTempl(Calendar(props)); err != nil {
-			return
-		}
+	// Build the calendar props for re-rendering.
+	props := Props{
+		ID:       calendarID,
+		Year:     newYear,
+		Month:    newMonth,
+		Selected: in.Selected,
+		Mode:     mode,
+		SyncURL:  sync,
+	}
 
-		// Patch the signals so the client knows the new year/month.
-		updatedSignals := map[string]any{
-			sanitizedID: map[string]any{
-				"year":  newYear,
-				"month": int(newMonth),
-			},
-		}
-		sse.MarshalAndPatchSignals(updatedSignals)
+	if err := sse.PatchElementTempl(Calendar(props)); err != nil {
+		return err
 	}
+
+	// Patch the signals so the client knows the new year/month.
+	return sse.PatchSignals(map[string]any{
+		"year":  newYear,
+		"month": int(newMonth),
+	})
 }
@@ -0,0 +1,51 @@
+package calendar_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/plaenen/webx"
+	"github.com/plaenen/webx/ui/calendar"
+	"github.com/plaenen/webx/webxtest"
+)
+
+func TestNavigate_KeepsSyncURL(t *testing.T) {
+	pro := func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := (&webx.WebXContext{DatastarPro: true}).WithContext(r.Context())
+			h.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+	tests := []struct {
+		name    string
+		handler http.Handler
+		target  string
+		want    bool
+	}{
+		{"fixed handler with sync", calendar.NavigateHandler("due-date", calendar.ModeSingle), "/?sync=1", true},
+		{"fixed handler without sync", calendar.NavigateHandler("due-date", calendar.ModeSingle), "/", false},
+		{"query handler with sync", calendar.NavigateHandlerFromQuery(), "/?mode=range&sync=1", true},
+		{"query handler without sync", calendar.NavigateHandlerFromQuery(), "/?mode=range", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := webxtest.Serve(t, pro(tt.handler), webxtest.Request{
+				Method:  http.MethodPost,
+				Target:  tt.target,
+				ID:      "due-date",
+				Signals: map[string]any{"year": 2025, "month": 3, "direction": 1},
+			})
+			if res.Code != http.StatusOK {
+				t.Fatalf("status = %d: %s", res.Code, res.Body)
+			}
+			els := res.Elements()
+			if len(els) != 1 {
+				t.Fatalf("elements = %+v", els)
+			}
+			if got := strings.Contains(els[0].Elements, "data-query-string"); got != tt.want {
+				t.Errorf("data-query-string present = %v, want %v:\n%s", got, tt.want, els[0].Elements)
+			}
+		})
+	}
+}
@@ -145,7 +145,7 @@ func Form(props Props) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templ.RenderAttributes(ctx, templ_7745c5c3_Buffer, ds.On("submit__prevent", submitAction))
+		templ_7745c5c3_Err = templ.RenderAttributes(ctx, templ_7745c5c3_Buffer, ds.On("submit", submitAction, ds.Prevent))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -1,10 +1,13 @@
 package form
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
-	"strings"
 
+	"github.com/plaenen/webx"
+	"github.com/plaenen/webx/validators"
 	"github.com/starfederation/datastar-go/datastar"
 )
 
@@ -16,6 +19,17 @@ type FieldError struct {
 	Message string
 }
 
+// Check returns the FieldError for field when result is invalid, and nil
+// otherwise, for appending to the errors of a SubmitFunc:
+//
+//	errs = append(errs, form.Check("email_error", validators.Email(signals.Email, false).Result())...)
+func Check(field string, result validators.Result) []FieldError {
+	if result.Valid {
+		return nil
+	}
+	return []FieldError{{Field: field, Message: result.Error}}
+}
+
 // SubmitFunc processes a form submission.
 // It receives the form ID and the raw request, and returns field errors.
 // Return nil or empty slice for success.
@@ -29,45 +43,27 @@ type SubmitFunc func(formID string, r *http.Request) []FieldError
 //
 //	r.Post("/api/auth/login", form.Handler(loginHandler, loginSuccess))
 func Handler(validate SubmitFunc, onSuccess func(formID string, sse *datastar.ServerSentEventGenerator)) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		formID := r.URL.Query().Get("id")
-		if formID == "" {
-			http.Error(w, "missing id query parameter", http.StatusBadRequest)
-			return
-		}
-
-		// Validate BEFORE creating the SSE — ReadSignals reads the
-		// request body which is consumed once NewSSE flushes headers.
-		errors := validate(formID, r)
+	// The signals are only checked for presence here; validate decodes them
+	// into its own struct with ReadSignals.
+	return webx.SignalHandler(func(_ context.Context, formID string, _ json.RawMessage, sse *webx.SignalSSE) error {
+		errors := validate(formID, sse.Request())
 
-		sanitizedID := strings.ReplaceAll(formID, "-", "_")
-		sse := datastar.NewSSE(w, r)
-
-		if len(errors) > 0 {
-			// Patch field errors and clear submitting
-			patch := map[string]any{
-				"submitting": false,
-			}
-			for _, e := range errors {
-				patch[e.Field] = e.Message
-			}
-			sse.MarshalAndPatchSignals(map[string]any{
-				sanitizedID: patch,
-			})
-			return
+		// Clear submitting and patch field errors, if any
+		patch := map[string]any{
+			"submitting": false,
+		}
+		for _, e := range errors {
+			patch[e.Field] = e.Message
+		}
+		if err := sse.PatchSignals(patch); err != nil {
+			return err
 		}
 
-		// Clear submitting on success
-		sse.MarshalAndPatchSignals(map[string]any{
-			sanitizedID: map[string]any{
-				"submitting": false,
-			},
-		})
-
-		if onSuccess != nil {
-			onSuccess(formID, sse)
+		if len(errors) == 0 && onSuccess != nil {
+			onSuccess(formID, sse.Generator())
 		}
-	}
+		return nil
+	})
 }
 
 // ReadSignals reads the form's namespaced signals from the request.
@@ -80,9 +76,7 @@ func Handler(validate SubmitFunc, onSuccess func(formID string, sse *datastar.Se
 //	var signals LoginSignals
 //	if err := form.ReadSignals("login", r, &signals); err != nil { ... }
 func ReadSignals(formID string, r *http.Request, dest any) error {
-	sanitizedID := strings.ReplaceAll(formID, "-", "_")
-	wrapper := map[string]any{sanitizedID: dest}
-	if err := datastar.ReadSignals(r, &wrapper); err != nil {
+	if err := webx.ReadSignals(r, formID, dest); err != nil {
 		return fmt.Errorf("read form signals: %w", err)
 	}
 	return nil
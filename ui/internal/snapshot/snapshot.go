@@ -0,0 +1,353 @@
+// Package snapshot renders templ components to normalized HTML and compares
+// the result with golden files, so markup and class-merge changes show up
+// as reviewable diffs.
+//
+// Golden files are rewritten with the -update flag:
+//
+//	go test ./ui -run TestSnapshots -update
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/plaenen/webx"
+)
+
+var update = flag.Bool("update", false, "rewrite snapshot golden files")
+
+// Case is one rendering of a component.
+type Case struct {
+	Name      string
+	Component templ.Component
+}
+
+// One returns a single case named "default".
+func One(c templ.Component) []Case {
+	return []Case{{Name: "default", Component: c}}
+}
+
+// Variadic adapts a component taking optional props, such as
+// button.Button, to Matrix.
+func Variadic[P any](fn func(...P) templ.Component) func(P) templ.Component {
+	return func(p P) templ.Component { return fn(p) }
+}
+
+// Matrix returns the cases for a component taking props P: base itself,
+// then base with each enum field set to every constant declared for its
+// type, then base with each bool field set. Axes are varied one at a time,
+// not combined, which keeps the golden files readable while still covering
+// every class each prop can contribute.
+//
+// Enum fields are fields of a named string or integer type whose constants
+// are declared in the type's package, such as button.Variant.
+func Matrix[P any](render func(P) templ.Component, base P) []Case {
+	cases := []Case{{Name: "default", Component: render(base)}}
+	bv := reflect.ValueOf(base)
+	bt := bv.Type()
+	for i := range bt.NumField() {
+		f := bt.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		if f.Type.Kind() == reflect.Bool {
+			if bv.Field(i).Bool() {
+				continue
+			}
+			p := reflect.New(bt).Elem()
+			p.Set(bv)
+			p.Field(i).SetBool(true)
+			cases = append(cases, Case{Name: f.Name, Component: render(p.Interface().(P))})
+			continue
+		}
+		for _, c := range enumConstants(f.Type) {
+			if c.value.Equal(bv.Field(i)) {
+				continue
+			}
+			p := reflect.New(bt).Elem()
+			p.Set(bv)
+			p.Field(i).Set(c.value)
+			cases = append(cases, Case{Name: f.Name + "=" + c.name, Component: render(p.Interface().(P))})
+		}
+	}
+	return cases
+}
+
+// Context is the context components are rendered with: a fixed
+// WebXContext, so CSRF tokens and nonces are stable.
+func Context() context.Context {
+	wctx := &webx.WebXContext{
+		CSRFToken: "test-csrf-token",
+		SessionID: "test-session",
+		Nonce:     "test-nonce",
+	}
+	return wctx.WithContext(context.Background())
+}
+
+// Children is passed to every component, so slots show up in snapshots.
+var Children = templ.Raw(`<span>child</span>`)
+
+// Render renders c with Context and Children and returns normalized HTML.
+func Render(t testing.TB, c templ.Component) string {
+	t.Helper()
+	var buf bytes.Buffer
+	ctx := templ.WithChildren(Context(), Children)
+	if err := c.Render(ctx, &buf); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	return Normalize(buf.String())
+}
+
+// randomID matches IDs from utils.RandomID.
+var randomID = regexp.MustCompile(`id-[A-Z2-7]{26}`)
+
+// Normalize replaces random IDs with numbered placeholders, keeping
+// references to the same ID consistent, and puts each tag on its own line.
+func Normalize(html string) string {
+	seen := map[string]string{}
+	html = randomID.ReplaceAllStringFunc(html, func(id string) string {
+		if _, ok := seen[id]; !ok {
+			seen[id] = fmt.Sprintf("id-RANDOM-%d", len(seen)+1)
+		}
+		return seen[id]
+	})
+	return strings.TrimSpace(strings.ReplaceAll(html, "><", ">\n<"))
+}
+
+// Golden compares the rendered cases with the golden file at path, or
+// rewrites it when -update is set.
+func Golden(t *testing.T, path string, cases []Case) {
+	t.Helper()
+	var buf strings.Builder
+	for i, c := range cases {
+		if i > 0 {
+			buf.WriteString("\n\n")
+		}
+		fmt.Fprintf(&buf, "<!-- %s -->\n%s", c.Name, Render(t, c.Component))
+	}
+	buf.WriteString("\n")
+	got := buf.String()
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run with -update to create it)", err)
+	}
+	if got != string(want) {
+		t.Errorf("%s is out of date (run with -update to accept):\n%s", path, firstDiff(string(want), got))
+	}
+}
+
+// firstDiff describes the first line that differs between want and got.
+func firstDiff(want, got string) string {
+	wl, gl := strings.Split(want, "\n"), strings.Split(got, "\n")
+	for i := range max(len(wl), len(gl)) {
+		var w, g string
+		if i < len(wl) {
+			w = wl[i]
+		}
+		if i < len(gl) {
+			g = gl[i]
+		}
+		if w != g {
+			return fmt.Sprintf("line %d:\n- %s\n+ %s", i+1, w, g)
+		}
+	}
+	return ""
+}
+
+// Exported returns the exported templ components declared in the .templ
+// files under dir, as "package.Name", so tests can check that every
+// component has snapshots.
+func Exported(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*", "*.templ"))
+	if err != nil {
+		return nil, err
+	}
+	decl := regexp.MustCompile(`(?m)^templ ([A-Z]\w*)\(`)
+	var names []string
+	for _, f := range files {
+		src, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		pkg := filepath.Base(filepath.Dir(f))
+		for _, m := range decl.FindAllStringSubmatch(string(src), -1) {
+			names = append(names, pkg+"."+m[1])
+		}
+	}
+	return names, nil
+}
+
+type constant struct {
+	name  string
+	value reflect.Value
+}
+
+var (
+	constMu    sync.Mutex
+	constCache = map[reflect.Type][]constant{}
+)
+
+// enumConstants returns the constants declared for a named string or
+// integer type, in declaration order, by parsing its package source.
+func enumConstants(typ reflect.Type) []constant {
+	switch typ.Kind() {
+	case reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+	default:
+		return nil
+	}
+	if typ.Name() == "" || typ.PkgPath() == "" {
+		return nil
+	}
+	constMu.Lock()
+	defer constMu.Unlock()
+	if cs, ok := constCache[typ]; ok {
+		return cs
+	}
+	var cs []constant
+	if dir, ok := packageDir(typ.PkgPath()); ok {
+		cs = parseConstants(dir, typ)
+	}
+	constCache[typ] = cs
+	return cs
+}
+
+// parseConstants collects "Name Type = literal" and "Name Type = iota"
+// constants, including implicit repetitions in the same block.
+func parseConstants(dir string, typ reflect.Type) []constant {
+	files, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	var cs []constant
+	fset := token.NewFileSet()
+	for _, path := range files {
+		f, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			continue
+		}
+		for _, d := range f.Decls {
+			gd, ok := d.(*ast.GenDecl)
+			if !ok || gd.Tok != token.CONST {
+				continue
+			}
+			inType, usesIota := false, false
+			for i, spec := range gd.Specs {
+				vs := spec.(*ast.ValueSpec)
+				switch {
+				case vs.Type != nil:
+					id, ok := vs.Type.(*ast.Ident)
+					inType = ok && id.Name == typ.Name()
+					usesIota = false
+				case len(vs.Values) > 0:
+					// An untyped constant ends the run of implicit repetitions.
+					inType = false
+				}
+				if !inType || len(vs.Names) != 1 {
+					continue
+				}
+				v := reflect.New(typ).Elem()
+				switch {
+				case len(vs.Values) == 1 && isIota(vs.Values[0]):
+					usesIota = true
+					v.SetInt(int64(i))
+				case len(vs.Values) == 1:
+					lit, ok := vs.Values[0].(*ast.BasicLit)
+					if !ok || !setLiteral(v, lit) {
+						continue
+					}
+				case usesIota:
+					v.SetInt(int64(i))
+				default:
+					continue
+				}
+				cs = append(cs, constant{name: vs.Names[0].Name, value: v})
+			}
+		}
+	}
+	return cs
+}
+
+func isIota(e ast.Expr) bool {
+	id, ok := e.(*ast.Ident)
+	return ok && id.Name == "iota"
+}
+
+func setLiteral(v reflect.Value, lit *ast.BasicLit) bool {
+	switch {
+	case lit.Kind == token.STRING && v.Kind() == reflect.String:
+		s, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return false
+		}
+		v.SetString(s)
+	case lit.Kind == token.INT && v.CanInt():
+		n, err := strconv.ParseInt(lit.Value, 0, 64)
+		if err != nil {
+			return false
+		}
+		v.SetInt(n)
+	default:
+		return false
+	}
+	return true
+}
+
+// packageDir maps an import path inside this module to its directory.
+func packageDir(pkgPath string) (string, bool) {
+	mod := findModule()
+	if mod.dir == "" {
+		return "", false
+	}
+	rel, ok := strings.CutPrefix(pkgPath, mod.path)
+	if !ok {
+		return "", false
+	}
+	return filepath.Join(mod.dir, filepath.FromSlash(rel)), true
+}
+
+type module struct{ dir, path string }
+
+// findModule locates the enclosing go.mod from the working directory.
+var findModule = sync.OnceValue(func() module {
+	dir, err := os.Getwd()
+	if err != nil {
+		return module{}
+	}
+	for {
+		data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+		if err == nil {
+			for line := range strings.Lines(string(data)) {
+				if path, ok := strings.CutPrefix(line, "module "); ok {
+					return module{dir: dir, path: strings.TrimSpace(path)}
+				}
+			}
+			return module{}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return module{}
+		}
+		dir = parent
+	}
+})
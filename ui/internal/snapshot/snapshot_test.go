@@ -0,0 +1,55 @@
+package snapshot
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/plaenen/webx/utils"
+)
+
+func TestNormalize(t *testing.T) {
+	a, b := utils.RandomID(), utils.RandomID()
+	got := Normalize(`<div id="` + a + `"><label for="` + a + `"></label><p id="` + b + `"></p></div>`)
+	want := "<div id=\"id-RANDOM-1\">\n<label for=\"id-RANDOM-1\">\n</label>\n<p id=\"id-RANDOM-2\">\n</p>\n</div>"
+	if got != want {
+		t.Errorf("Normalize =\n%s\nwant\n%s", got, want)
+	}
+}
+
+type (
+	color string
+	mode  int
+)
+
+const (
+	colorNone color = ""
+	colorRed  color = "red"
+	colorBlue color = "blue"
+	untyped         = "ignored"
+)
+
+const (
+	modeA mode = iota
+	modeB
+	modeC
+)
+
+func TestEnumConstants(t *testing.T) {
+	names := func(typ reflect.Type) []string {
+		var out []string
+		for _, c := range enumConstants(typ) {
+			out = append(out, c.name+"="+c.value.String())
+		}
+		return out
+	}
+	if got, want := names(reflect.TypeFor[color]()), []string{"colorNone=", "colorRed=red", "colorBlue=blue"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("color constants = %v, want %v", got, want)
+	}
+	var modes []int64
+	for _, c := range enumConstants(reflect.TypeFor[mode]()) {
+		modes = append(modes, c.value.Int())
+	}
+	if want := []int64{0, 1, 2}; !reflect.DeepEqual(modes, want) {
+		t.Errorf("mode constants = %v, want %v", modes, want)
+	}
+}
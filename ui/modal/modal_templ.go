@@ -305,7 +305,8 @@ func Action(props ...ActionProps) templ.Component {
 }
 
 // Backdrop renders a click-outside overlay that closes the modal.
-// Must be placed as a sibling of modal-box inside the modal div.
+// Must be placed as a sibling of modal-box inside the modal div. It is
+// hidden from assistive technology; keyboard users close with CloseButton.
 func Backdrop(modalID string) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
@@ -328,7 +329,7 @@ func Backdrop(modalID string) templ.Component {
 		}
 		ctx = templ.ClearChildren(ctx)
 		signals := utils.Signals(modalID, ModalSignals{})
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 19, "<div class=\"modal-backdrop\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 19, "<div class=\"modal-backdrop\" aria-hidden=\"true\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -1,11 +1,10 @@
 package moneyinput
 
 import (
-	"fmt"
+	"context"
 	"net/http"
-	"strings"
 
-	"github.com/starfederation/datastar-go/datastar"
+	"github.com/plaenen/webx"
 )
 
 type decimalHandlerSignals struct {
@@ -29,27 +28,8 @@ type moneyHandlerSignals struct {
 //
 //	r.Get("/api/parse/decimal", moneyinput.DecimalHandler())
 func DecimalHandler() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		componentID := r.URL.Query().Get("id")
-		if componentID == "" {
-			http.Error(w, "missing id query parameter", http.StatusBadRequest)
-			return
-		}
-
-		sanitizedID := strings.ReplaceAll(componentID, "-", "_")
-		wrapper := map[string]decimalHandlerSignals{}
-		if err := datastar.ReadSignals(r, &wrapper); err != nil {
-			http.Error(w, fmt.Sprintf("read signals: %v", err), http.StatusBadRequest)
-			return
-		}
-
-		store, ok := wrapper[sanitizedID]
-		if !ok {
-			http.Error(w, fmt.Sprintf("missing signals for %q", sanitizedID), http.StatusBadRequest)
-			return
-		}
-
-		result := ParseAmount(store.Value)
+	return webx.SignalHandler(func(_ context.Context, _ string, in decimalHandlerSignals, sse *webx.SignalSSE) error {
+		result := ParseAmount(in.Value)
 
 		patch := map[string]any{
 			"amount": "",
@@ -57,15 +37,11 @@ func DecimalHandler() http.HandlerFunc {
 		}
 		if !result.Valid {
 			patch["error"] = result.Error
-		} else if store.Value != "" {
+		} else if in.Value != "" {
 			patch["amount"] = FormatAmount(result.Value)
 		}
-
-		sse := datastar.NewSSE(w, r)
-		sse.MarshalAndPatchSignals(map[string]any{
-			sanitizedID: patch,
-		})
-	}
+		return sse.PatchSignals(patch)
+	})
 }
 
 // MoneyHandler returns an http.HandlerFunc that parses a money value
@@ -78,27 +54,8 @@ func DecimalHandler() http.HandlerFunc {
 //
 //	r.Get("/api/parse/money", moneyinput.MoneyHandler("USD", "EUR"))
 func MoneyHandler(allowedCurrencies ...string) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		componentID := r.URL.Query().Get("id")
-		if componentID == "" {
-			http.Error(w, "missing id query parameter", http.StatusBadRequest)
-			return
-		}
-
-		sanitizedID := strings.ReplaceAll(componentID, "-", "_")
-		wrapper := map[string]moneyHandlerSignals{}
-		if err := datastar.ReadSignals(r, &wrapper); err != nil {
-			http.Error(w, fmt.Sprintf("read signals: %v", err), http.StatusBadRequest)
-			return
-		}
-
-		store, ok := wrapper[sanitizedID]
-		if !ok {
-			http.Error(w, fmt.Sprintf("missing signals for %q", sanitizedID), http.StatusBadRequest)
-			return
-		}
-
-		result := ParseMoney(store.Value, allowedCurrencies)
+	return webx.SignalHandler(func(_ context.Context, _ string, in moneyHandlerSignals, sse *webx.SignalSSE) error {
+		result := ParseMoney(in.Value, allowedCurrencies)
 
 		patch := map[string]any{
 			"amount":   "",
@@ -107,14 +64,10 @@ func MoneyHandler(allowedCurrencies ...string) http.HandlerFunc {
 		}
 		if !result.Valid {
 			patch["error"] = result.Error
-		} else if store.Value != "" {
+		} else if in.Value != "" {
 			patch["amount"] = FormatAmount(result.Value)
 			patch["currency"] = result.Currency
 		}
-
-		sse := datastar.NewSSE(w, r)
-		sse.MarshalAndPatchSignals(map[string]any{
-			sanitizedID: patch,
-		})
-	}
+		return sse.PatchSignals(patch)
+	})
 }
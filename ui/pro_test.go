@@ -0,0 +1,67 @@
+package ui_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/plaenen/webx"
+	"github.com/plaenen/webx/ui/accordion"
+	"github.com/plaenen/webx/ui/calendar"
+	"github.com/plaenen/webx/ui/themecontroller"
+)
+
+// TestProEnhancements checks that Pro-only attributes are rendered when
+// the page loads Datastar Pro and left out otherwise.
+func TestProEnhancements(t *testing.T) {
+	tests := []struct {
+		name      string
+		component templ.Component
+		want      string
+	}{
+		{
+			"themecontroller.Toggle",
+			themecontroller.Toggle(themecontroller.ToggleProps{ID: "theme", Theme: "dark", Persist: true}),
+			`data-persist:theme="{include: /^theme\.(theme)$/}"`,
+		},
+		{
+			"themecontroller.ButtonGroup",
+			themecontroller.ButtonGroup(themecontroller.ButtonGroupProps{ID: "theme", Themes: themes, Persist: true}),
+			`data-persist:theme="{include: /^theme\.(theme)$/}"`,
+		},
+		{
+			"accordion.Accordion",
+			accordion.Accordion(accordion.Props{ID: "faq", Persist: true}),
+			`data-persist:faq__session="{include: /^faq\.(active)$/}"`,
+		},
+		{
+			"calendar.Calendar",
+			calendar.Calendar(calendar.Props{ID: "due-date", Year: 2025, Month: time.March, SyncURL: true}),
+			`data-query-string__filter="{include: /^due_date\./}"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderWith(t, &webx.WebXContext{DatastarPro: true}, tt.component); !strings.Contains(got, tt.want) {
+				t.Errorf("Pro render is missing %s:\n%s", tt.want, got)
+			}
+			got := renderWith(t, &webx.WebXContext{}, tt.component)
+			for _, attr := range []string{"data-persist", "data-query-string"} {
+				if strings.Contains(got, attr) {
+					t.Errorf("open-source render contains %s:\n%s", attr, got)
+				}
+			}
+		})
+	}
+}
+
+func renderWith(t *testing.T, wctx *webx.WebXContext, c templ.Component) string {
+	t.Helper()
+	var b strings.Builder
+	if err := c.Render(wctx.WithContext(context.Background()), &b); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	return b.String()
+}
@@ -0,0 +1,322 @@
+package ui_test
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/plaenen/webx"
+	"github.com/plaenen/webx/ui/accordion"
+	"github.com/plaenen/webx/ui/alert"
+	"github.com/plaenen/webx/ui/avatar"
+	"github.com/plaenen/webx/ui/badge"
+	"github.com/plaenen/webx/ui/breadcrumbs"
+	"github.com/plaenen/webx/ui/button"
+	"github.com/plaenen/webx/ui/calendar"
+	"github.com/plaenen/webx/ui/card"
+	"github.com/plaenen/webx/ui/carousel"
+	"github.com/plaenen/webx/ui/chat"
+	"github.com/plaenen/webx/ui/dock"
+	"github.com/plaenen/webx/ui/drawer"
+	"github.com/plaenen/webx/ui/dropdown"
+	"github.com/plaenen/webx/ui/fab"
+	"github.com/plaenen/webx/ui/fieldset"
+	"github.com/plaenen/webx/ui/fileinput"
+	"github.com/plaenen/webx/ui/fileupload"
+	"github.com/plaenen/webx/ui/filter"
+	"github.com/plaenen/webx/ui/footer"
+	"github.com/plaenen/webx/ui/form"
+	"github.com/plaenen/webx/ui/hovergallery"
+	"github.com/plaenen/webx/ui/indicator"
+	"github.com/plaenen/webx/ui/internal/snapshot"
+	"github.com/plaenen/webx/ui/join"
+	"github.com/plaenen/webx/ui/kbd"
+	"github.com/plaenen/webx/ui/label"
+	"github.com/plaenen/webx/ui/link"
+	"github.com/plaenen/webx/ui/list"
+	"github.com/plaenen/webx/ui/loading"
+	"github.com/plaenen/webx/ui/markdown"
+	"github.com/plaenen/webx/ui/menu"
+	"github.com/plaenen/webx/ui/mockupcode"
+	"github.com/plaenen/webx/ui/modal"
+	"github.com/plaenen/webx/ui/money"
+	"github.com/plaenen/webx/ui/moneyinput"
+	"github.com/plaenen/webx/ui/navbar"
+	"github.com/plaenen/webx/ui/pagination"
+	"github.com/plaenen/webx/ui/progress"
+	"github.com/plaenen/webx/ui/radialprogress"
+	"github.com/plaenen/webx/ui/radio"
+	"github.com/plaenen/webx/ui/rangeinput"
+	"github.com/plaenen/webx/ui/rating"
+	"github.com/plaenen/webx/ui/selectinput"
+	"github.com/plaenen/webx/ui/separator"
+	"github.com/plaenen/webx/ui/skeleton"
+	"github.com/plaenen/webx/ui/stack"
+	"github.com/plaenen/webx/ui/stat"
+	"github.com/plaenen/webx/ui/status"
+	"github.com/plaenen/webx/ui/steps"
+	"github.com/plaenen/webx/ui/tab"
+	"github.com/plaenen/webx/ui/table"
+	"github.com/plaenen/webx/ui/textarea"
+	"github.com/plaenen/webx/ui/textrotate"
+	"github.com/plaenen/webx/ui/themecontroller"
+	"github.com/plaenen/webx/ui/timeline"
+	"github.com/plaenen/webx/ui/toast"
+	"github.com/plaenen/webx/ui/toggle"
+	"github.com/plaenen/webx/ui/tooltip"
+	"github.com/plaenen/webx/ui/validator"
+)
+
+// optional builds the matrix for a component whose props are optional.
+func optional[P any](fn func(...P) templ.Component, base ...P) []snapshot.Case {
+	var p P
+	if len(base) > 0 {
+		p = base[0]
+	}
+	return snapshot.Matrix(snapshot.Variadic(fn), p)
+}
+
+// components lists every exported component in ui/* by "package.Name".
+// TestSnapshotsCoverEveryComponent fails when one is missing.
+var components = map[string][]snapshot.Case{
+	"accordion.Accordion": snapshot.Matrix(accordion.Accordion, accordion.Props{ID: "faq", DefaultValue: "one"}),
+	"accordion.Item":      snapshot.Matrix(accordion.Item, accordion.ItemProps{AccordionID: "faq", Value: "one", Title: "Question"}),
+
+	"alert.Alert": optional(alert.Alert),
+
+	"avatar.Avatar": optional(avatar.Avatar, avatar.Props{Src: "/me.png", Alt: "Me"}),
+	"avatar.Group":  optional(avatar.Group),
+
+	"badge.Badge": optional(badge.Badge),
+
+	"breadcrumbs.Breadcrumbs": optional(breadcrumbs.Breadcrumbs),
+	"breadcrumbs.Item":        optional(breadcrumbs.Item, breadcrumbs.ItemProps{Href: "/docs"}),
+
+	"button.Button": optional(button.Button, button.Props{OnClick: "$count++"}),
+
+	"calendar.Calendar": snapshot.Matrix(calendar.Calendar, calendar.Props{
+		ID: "due", Year: 2001, Month: time.February, Selected: "2001-02-14", RangeStart: "2001-02-05", RangeEnd: "2001-02-09",
+	}),
+
+	"card.Actions": optional(card.Actions),
+	"card.Body":    optional(card.Body),
+	"card.Card":    optional(card.Card),
+	"card.Title":   optional(card.Title),
+
+	"carousel.Carousel": optional(carousel.Carousel),
+	"carousel.Item":     optional(carousel.Item),
+
+	"chat.Bubble": optional(chat.Bubble),
+	"chat.Chat":   snapshot.Matrix(chat.Chat, chat.Props{}),
+	"chat.Footer": optional(chat.Footer),
+	"chat.Header": optional(chat.Header),
+	"chat.Image":  optional(chat.Image),
+
+	"dock.Dock":  optional(dock.Dock),
+	"dock.Item":  optional(dock.Item),
+	"dock.Label": optional(dock.Label),
+
+	"drawer.Content":      optional(drawer.Content),
+	"drawer.Drawer":       snapshot.Matrix(drawer.Drawer, drawer.Props{ID: "nav"}),
+	"drawer.Side":         snapshot.Matrix(drawer.Side, drawer.SideProps{ID: "nav"}),
+	"drawer.ToggleButton": snapshot.One(drawer.ToggleButton("nav")),
+
+	"dropdown.Content":  snapshot.Matrix(dropdown.Content, dropdown.ContentProps{DropdownID: "menu"}),
+	"dropdown.Dropdown": snapshot.Matrix(dropdown.Dropdown, dropdown.Props{ID: "menu"}),
+	"dropdown.Trigger":  snapshot.Matrix(dropdown.Trigger, dropdown.TriggerProps{DropdownID: "menu"}),
+
+	"fab.Close":      optional(fab.Close),
+	"fab.Fab":        optional(fab.Fab),
+	"fab.MainAction": optional(fab.MainAction),
+
+	"fieldset.Fieldset": optional(fieldset.Fieldset),
+	"fieldset.Label":    optional(fieldset.Label),
+	"fieldset.Legend":   optional(fieldset.Legend),
+
+	"fileinput.FileInput": optional(fileinput.FileInput, fileinput.Props{Name: "attachment"}),
+
+	"fileupload.FileUpload": snapshot.Matrix(fileupload.FileUpload, fileupload.Props{
+		ID: "docs", Accept: "image/*", UploadURL: "/api/upload", RemoveURL: "/api/upload/remove",
+	}),
+
+	"filter.Filter": optional(filter.Filter),
+	"filter.Radio":  snapshot.Matrix(filter.Radio, filter.RadioProps{Name: "os", Label: "Linux"}),
+	"filter.Reset":  optional(filter.Reset),
+
+	"footer.Footer": optional(footer.Footer),
+	"footer.Title":  optional(footer.Title),
+
+	"form.Description": optional(form.Description),
+	"form.Error":       snapshot.One(form.Error("$login.email_error")),
+	"form.ErrorStatic": snapshot.One(form.ErrorStatic("email_error")),
+	"form.Field":       optional(form.Field),
+	"form.Form": snapshot.Matrix(form.Form, form.Props{
+		ID: "login", Action: "/api/login", Signals: map[string]string{"email": ""},
+	}),
+	"form.FormError": snapshot.One(form.FormError("login")),
+	"form.Label":     optional(form.Label),
+	"form.Submit":    snapshot.Matrix(form.Submit, form.SubmitProps{FormID: "login"}),
+	"form.Success":   snapshot.One(form.Success("$login.success")),
+
+	"hovergallery.HoverGallery": optional(hovergallery.HoverGallery),
+
+	"indicator.Indicator": optional(indicator.Indicator),
+	"indicator.Item":      optional(indicator.Item),
+
+	"join.Item": optional(join.Item),
+	"join.Join": optional(join.Join),
+
+	"kbd.Kbd": optional(kbd.Kbd),
+
+	"label.Floating": optional(label.Floating),
+	"label.Label":    optional(label.Label),
+
+	"link.Link": optional(link.Link, link.Props{Href: "/about"}),
+
+	"list.Header": optional(list.Header),
+	"list.List":   optional(list.List),
+	"list.Row":    optional(list.Row),
+
+	"loading.Loading": optional(loading.Loading),
+
+	"markdown.Markdown": snapshot.Matrix(markdown.Markdown, markdown.Props{ID: "readme", Content: "# Title\n\nSome *text*."}),
+	"markdown.MarkdownInput": snapshot.Matrix(markdown.MarkdownInput, markdown.InputProps{
+		ID: "body", Name: "body", Value: "**hi**", PreviewURL: "/api/preview/markdown",
+	}),
+
+	"menu.Item":  optional(menu.Item, menu.ItemProps{Href: "/home"}),
+	"menu.Menu":  optional(menu.Menu),
+	"menu.Title": optional(menu.Title),
+
+	"mockupcode.Line":       optional(mockupcode.Line, mockupcode.LineProps{Prefix: "$"}),
+	"mockupcode.MockupCode": optional(mockupcode.MockupCode),
+
+	"modal.Action":      optional(modal.Action),
+	"modal.Backdrop":    snapshot.One(modal.Backdrop("confirm")),
+	"modal.Box":         optional(modal.Box),
+	"modal.CloseButton": snapshot.One(modal.CloseButton("confirm")),
+	"modal.Modal":       snapshot.Matrix(modal.Modal, modal.Props{ID: "confirm"}),
+	"modal.OpenButton":  snapshot.One(modal.OpenButton("confirm")),
+
+	"money.Money": snapshot.Matrix(money.Money, money.Props{Amount: 1234.5, Currency: "EUR"}),
+
+	"moneyinput.DecimalInput": snapshot.Matrix(moneyinput.DecimalInput, moneyinput.DecimalProps{
+		ID: "amount", Name: "amount", ParseURL: "/api/parse/decimal",
+	}),
+	"moneyinput.MoneyInput": snapshot.Matrix(moneyinput.MoneyInput, moneyinput.MoneyProps{
+		ID: "price", Name: "price", ParseURL: "/api/parse/money",
+	}),
+
+	"navbar.Center": optional(navbar.Center),
+	"navbar.End":    optional(navbar.End),
+	"navbar.Navbar": optional(navbar.Navbar),
+	"navbar.Start":  optional(navbar.Start),
+
+	"pagination.Button":     optional(pagination.Button),
+	"pagination.Pagination": optional(pagination.Pagination),
+
+	"progress.Progress": optional(progress.Progress, progress.Props{Value: 40, Max: 100}),
+
+	"radialprogress.RadialProgress": optional(radialprogress.RadialProgress, radialprogress.Props{Value: 70}),
+
+	"radio.Radio": optional(radio.Radio, radio.Props{Name: "plan"}),
+
+	"rangeinput.Range": optional(rangeinput.Range, rangeinput.Props{Name: "volume", Max: 100, Value: 25}),
+
+	"rating.HalfStar": snapshot.Matrix(rating.HalfStar, rating.HalfStarProps{Name: "score", Label1: "0.5", Label2: "1", Checked: 2}),
+	"rating.Rating":   optional(rating.Rating),
+	"rating.Star":     snapshot.Matrix(rating.Star, rating.StarProps{Name: "score", Label: "1 star"}),
+
+	"selectinput.Select": optional(selectinput.Select, selectinput.Props{Name: "country"}),
+
+	"separator.Separator": optional(separator.Separator),
+
+	"skeleton.Skeleton": optional(skeleton.Skeleton),
+
+	"stack.Stack": optional(stack.Stack),
+
+	"stat.Actions": optional(stat.Actions),
+	"stat.Desc":    optional(stat.Desc),
+	"stat.Figure":  optional(stat.Figure),
+	"stat.Stat":    optional(stat.Stat),
+	"stat.Stats":   optional(stat.Stats),
+	"stat.Title":   optional(stat.Title),
+	"stat.Value":   optional(stat.Value),
+
+	"status.Status": optional(status.Status),
+
+	"steps.Icon":  optional(steps.Icon),
+	"steps.Step":  optional(steps.Step),
+	"steps.Steps": optional(steps.Steps),
+
+	"tab.Content":  optional(tab.Content),
+	"tab.RadioTab": snapshot.Matrix(tab.RadioTab, tab.RadioTabProps{Name: "tabs", Label: "Tab 1"}),
+	"tab.Tab":      optional(tab.Tab),
+	"tab.Tabs":     optional(tab.Tabs),
+
+	"table.Table": optional(table.Table),
+
+	"textarea.Textarea": optional(textarea.Textarea, textarea.Props{Name: "bio", Placeholder: "Bio"}),
+
+	"textrotate.TextRotate": optional(textrotate.TextRotate),
+
+	"themecontroller.ButtonGroup": snapshot.Matrix(themecontroller.ButtonGroup, themecontroller.ButtonGroupProps{
+		ID: "theme-buttons", Default: "light", Themes: themes,
+	}),
+	"themecontroller.RadioGroup": snapshot.Matrix(themecontroller.RadioGroup, themecontroller.RadioGroupProps{
+		ID: "theme-radios", Default: "light", Themes: themes,
+	}),
+	"themecontroller.Toggle": snapshot.Matrix(themecontroller.Toggle, themecontroller.ToggleProps{ID: "theme", Theme: "dark"}),
+
+	"timeline.End":      optional(timeline.End),
+	"timeline.Hr":       snapshot.One(timeline.Hr("bg-primary")),
+	"timeline.Item":     optional(timeline.Item),
+	"timeline.Middle":   optional(timeline.Middle),
+	"timeline.Start":    optional(timeline.Start),
+	"timeline.Timeline": optional(timeline.Timeline),
+
+	"toast.Message": snapshot.One(toast.Message(webx.FlashMessage{Level: webx.FlashSuccess, Text: "Saved"}, 3*time.Second)),
+	"toast.Outlet":  optional(toast.Outlet),
+	"toast.Toast":   optional(toast.Toast),
+
+	"toggle.Toggle": optional(toggle.Toggle, toggle.Props{Name: "notify"}),
+
+	"tooltip.Content": optional(tooltip.Content),
+	"tooltip.Tooltip": optional(tooltip.Tooltip, tooltip.Props{Tip: "Hello"}),
+
+	"validator.Input": snapshot.Matrix(validator.Input, validator.InputProps{
+		ID: "email", Name: "email", ValidateURL: "/api/validate/email", HintText: "We never share it",
+	}),
+	"validator.SuccessHint": snapshot.One(validator.SuccessHint("email")),
+}
+
+var themes = []themecontroller.ThemeOption{{Value: "light", Label: "Light"}, {Value: "dark", Label: "Dark"}}
+
+func TestSnapshots(t *testing.T) {
+	for name, cases := range components {
+		t.Run(name, func(t *testing.T) {
+			pkg, component, _ := strings.Cut(name, ".")
+			snapshot.Golden(t, filepath.Join("testdata", "snapshots", pkg, component+".html"), cases)
+		})
+	}
+}
+
+func TestSnapshotsCoverEveryComponent(t *testing.T) {
+	exported, err := snapshot.Exported(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range exported {
+		if _, ok := components[name]; !ok {
+			t.Errorf("%s has no snapshot cases; add it to components", name)
+		}
+	}
+	for name := range components {
+		if !slices.Contains(exported, name) {
+			t.Errorf("components lists %s, which is not an exported templ component", name)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package toast
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/plaenen/webx"
+	"github.com/starfederation/datastar-go/datastar"
+)
+
+// DefaultOutletID is the element ID of the flash message outlet.
+const DefaultOutletID = "webx-toasts"
+
+// DefaultDuration is how long a flash message is shown.
+const DefaultDuration = 5 * time.Second
+
+func resolveOutletProps(props []OutletProps) OutletProps {
+	var p OutletProps
+	if len(props) > 0 {
+		p = props[0]
+	}
+	if p.ID == "" {
+		p.ID = DefaultOutletID
+	}
+	if p.Horizontal == HorizontalDefault {
+		p.Horizontal = HorizontalEnd
+	}
+	if p.Vertical == VerticalDefault {
+		p.Vertical = VerticalTop
+	}
+	if p.Duration == 0 {
+		p.Duration = DefaultDuration
+	}
+	return p
+}
+
+func levelClass(level webx.FlashLevel) string {
+	switch level {
+	case webx.FlashSuccess:
+		return "alert-success"
+	case webx.FlashWarning:
+		return "alert-warning"
+	case webx.FlashError:
+		return "alert-error"
+	default:
+		return "alert-info"
+	}
+}
+
+// Push appends msg to the outlet on the current page over SSE. Only ID and
+// Duration of props are used.
+//
+//	sse := datastar.NewSSE(w, r)
+//	toast.Push(sse, webx.FlashMessage{Level: webx.FlashSuccess, Text: "Saved"})
+func Push(sse *datastar.ServerSentEventGenerator, msg webx.FlashMessage, props ...OutletProps) error {
+	p := resolveOutletProps(props)
+	if err := sse.PatchElementTempl(
+		Message(msg, p.Duration),
+		datastar.WithSelectorID(p.ID),
+		datastar.WithModeAppend(),
+	); err != nil {
+		return fmt.Errorf("pushing toast: %w", err)
+	}
+	return nil
+}
+
+// PushFlashes delivers the messages queued with webx.Flash during this
+// request over SSE instead of waiting for the next page render.
+func PushFlashes(ctx context.Context, sse *datastar.ServerSentEventGenerator, props ...OutletProps) error {
+	msgs, err := webx.Flashes(ctx)
+	if err != nil {
+		return err
+	}
+	for _, msg := range msgs {
+		if err := Push(sse, msg, props...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,18 +1,16 @@
 package validator
 
 import (
-	"fmt"
+	"context"
 	"net/http"
-	"strings"
 
-	"github.com/starfederation/datastar-go/datastar"
+	"github.com/plaenen/webx"
+	"github.com/plaenen/webx/validators"
 )
 
-// Result holds the outcome of a validation check.
-type Result struct {
-	Valid bool
-	Error string
-}
+// Result holds the outcome of a validation check. It is the Result of the
+// validators package, so their results can be returned as they are.
+type Result = validators.Result
 
 // ValidateFunc validates a string value and returns a Result.
 type ValidateFunc func(value string) Result
@@ -27,37 +25,13 @@ type ValidateFunc func(value string) Result
 //
 // The component references this path via the ValidateURL prop.
 func Handler(fn ValidateFunc) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		componentID := r.URL.Query().Get("id")
-		if componentID == "" {
-			http.Error(w, "missing id query parameter", http.StatusBadRequest)
-			return
-		}
-
-		// Signals arrive namespaced: {"component_id": {"value": "...", ...}}
-		sanitizedID := strings.ReplaceAll(componentID, "-", "_")
-		wrapper := map[string]inputSignals{}
-		if err := datastar.ReadSignals(r, &wrapper); err != nil {
-			http.Error(w, fmt.Sprintf("read signals: %v", err), http.StatusBadRequest)
-			return
-		}
-
-		store, ok := wrapper[sanitizedID]
-		if !ok {
-			http.Error(w, fmt.Sprintf("missing signals for %q", sanitizedID), http.StatusBadRequest)
-			return
-		}
-
-		result := fn(store.Value)
-
-		sse := datastar.NewSSE(w, r)
-		sse.MarshalAndPatchSignals(map[string]any{
-			sanitizedID: map[string]any{
-				"valid": result.Valid,
-				"error": result.Error,
-			},
+	return webx.SignalHandler(func(_ context.Context, _ string, in inputSignals, sse *webx.SignalSSE) error {
+		result := fn(in.Value)
+		return sse.PatchSignals(map[string]any{
+			"valid": result.Valid,
+			"error": result.Error,
 		})
-	}
+	})
 }
 
 // inputSignals is the signal shape sent by the client.
@@ -3,6 +3,8 @@ package utils
 import (
 	"fmt"
 	"strings"
+
+	"github.com/plaenen/webx/ds"
 )
 
 // DataClass builds data-class attribute values: JS objects mapping
@@ -35,7 +37,7 @@ func (d *DataClass) Build() string {
 
 	parts := make([]string, len(d.classes))
 	for i, entry := range d.classes {
-		parts[i] = fmt.Sprintf("'%s': %s", entry.className, entry.condition)
+		parts[i] = fmt.Sprintf("%s: %s", ds.Str(entry.className), entry.condition)
 	}
 
 	return "{" + strings.Join(parts, ", ") + "}"
@@ -3,7 +3,10 @@ package utils
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"strings"
+
+	"github.com/plaenen/webx/ds"
 )
 
 // SignalManager provides a structured way to manage Datastar signals.
@@ -48,27 +51,32 @@ func (sm *SignalManager) Toggle(property string) string {
 	return fmt.Sprintf("%s = !%s", ref, ref)
 }
 
-// Set returns an assignment expression for a signal property.
+// Set returns an assignment expression for a signal property. value is
+// used as-is; encode literals with ds.Str or ds.Num, or use SetString.
 func (sm *SignalManager) Set(property, value string) string {
 	return fmt.Sprintf("%s = %s", sm.Signal(property), value)
 }
 
-// SetString returns an assignment expression with proper JS string quoting.
+// SetString returns an assignment of value, encoded as a JS string literal
+// with ds.Str.
 func (sm *SignalManager) SetString(property, value string) string {
-	return fmt.Sprintf("%s = '%s'", sm.Signal(property), value)
+	return fmt.Sprintf("%s = %s", sm.Signal(property), ds.Str(value))
 }
 
-// Equals returns a strict equality comparison expression.
+// Equals returns a strict equality comparison with value, encoded as a JS
+// string literal.
 func (sm *SignalManager) Equals(property, value string) string {
-	return fmt.Sprintf("%s === '%s'", sm.Signal(property), value)
+	return fmt.Sprintf("%s === %s", sm.Signal(property), ds.Str(value))
 }
 
-// NotEquals returns a strict inequality comparison expression.
+// NotEquals returns a strict inequality comparison with value, encoded as
+// a JS string literal.
 func (sm *SignalManager) NotEquals(property, value string) string {
-	return fmt.Sprintf("%s !== '%s'", sm.Signal(property), value)
+	return fmt.Sprintf("%s !== %s", sm.Signal(property), ds.Str(value))
 }
 
 // Conditional returns a ternary expression based on a signal property.
+// Both branches are used as-is; encode literals with ds.Str.
 func (sm *SignalManager) Conditional(property, trueValue, falseValue string) string {
 	return fmt.Sprintf("%s ? %s : %s", sm.Signal(property), trueValue, falseValue)
 }
@@ -77,3 +85,18 @@ func (sm *SignalManager) Conditional(property, trueValue, falseValue string) str
 func (sm *SignalManager) ConditionalAction(condition, property, value string) string {
 	return fmt.Sprintf("%s ? (%s) : void 0", condition, sm.Set(property, value))
 }
+
+// Filter returns a ds.Filter selecting the named properties of this
+// component, or all of its signals when none are named. Use it to scope
+// Pro attributes such as ds.Persist to one component.
+func (sm *SignalManager) Filter(properties ...string) ds.Filter {
+	prefix := "^" + regexp.QuoteMeta(sm.ID) + `\.`
+	if len(properties) == 0 {
+		return ds.Filter{Include: prefix}
+	}
+	quoted := make([]string, len(properties))
+	for i, p := range properties {
+		quoted[i] = regexp.QuoteMeta(p)
+	}
+	return ds.Filter{Include: prefix + "(" + strings.Join(quoted, "|") + ")$"}
+}
@@ -1,19 +1,33 @@
 package utils
 
 import (
-	"fmt"
-
-	"crypto/rand"
+	"strings"
 
 	"github.com/a-h/templ"
+	"github.com/plaenen/webx/idgen"
 
 	twmerge "github.com/Oudwins/tailwind-merge-go"
 )
 
 // TwMerge combines Tailwind classes and resolves conflicts.
 // Example: "bg-red-500 hover:bg-blue-500", "bg-green-500" → "hover:bg-blue-500 bg-green-500"
+//
+// The surviving classes keep their input order. twmerge returns them in map
+// order, which would make rendered HTML differ from run to run.
 func TwMerge(classes ...string) string {
-	return twmerge.Merge(classes...)
+	merged := strings.Fields(twmerge.Merge(classes...))
+	kept := make(map[string]int, len(merged))
+	for _, c := range merged {
+		kept[c]++
+	}
+	ordered := merged[:0]
+	for _, c := range strings.Fields(strings.Join(classes, " ")) {
+		if kept[c] > 0 {
+			kept[c]--
+			ordered = append(ordered, c)
+		}
+	}
+	return strings.Join(ordered, " ")
 }
 
 // If returns value if condition is true, otherwise an empty value of type T.
@@ -49,6 +63,9 @@ func MergeAttributes(attrs ...templ.Attributes) templ.Attributes {
 
 // RandomID generates a random ID string.
 // Example: RandomID() → "id-1a2b3c"
+//
+// The characters come from idgen.Text, so installing a deterministic
+// generator with idgen.SetDefault makes rendered IDs reproducible.
 func RandomID() string {
-	return fmt.Sprintf("id-%s", rand.Text())
+	return "id-" + idgen.Text()
 }
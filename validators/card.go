@@ -0,0 +1,128 @@
+package validators
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// CardBrand is a payment card network, detected from the number's prefix.
+type CardBrand string
+
+const (
+	CardUnknown    CardBrand = ""
+	CardVisa       CardBrand = "visa"
+	CardMastercard CardBrand = "mastercard"
+	CardAmex       CardBrand = "amex"
+	CardDiscover   CardBrand = "discover"
+	CardDiners     CardBrand = "diners"
+	CardJCB        CardBrand = "jcb"
+	CardUnionPay   CardBrand = "unionpay"
+	CardMaestro    CardBrand = "maestro"
+)
+
+// cardRange is a range of number prefixes, all of the same length, that
+// belongs to a brand.
+type cardRange struct {
+	brand    CardBrand
+	from, to int   // inclusive prefix range
+	lengths  []int // valid number lengths
+}
+
+// cardRanges are checked in order; the first match wins, so narrower
+// ranges come before the broader ones they overlap.
+var cardRanges = []cardRange{
+	{CardAmex, 34, 34, []int{15}},
+	{CardAmex, 37, 37, []int{15}},
+	{CardDiners, 300, 305, []int{14, 15, 16, 17, 18, 19}},
+	{CardDiners, 36, 36, []int{14, 15, 16, 17, 18, 19}},
+	{CardDiners, 38, 39, []int{14, 15, 16, 17, 18, 19}},
+	{CardJCB, 3528, 3589, []int{16, 17, 18, 19}},
+	{CardVisa, 4, 4, []int{13, 16, 19}},
+	{CardMastercard, 51, 55, []int{16}},
+	{CardMastercard, 2221, 2720, []int{16}},
+	{CardDiscover, 6011, 6011, []int{16, 17, 18, 19}},
+	{CardDiscover, 644, 649, []int{16, 17, 18, 19}},
+	{CardDiscover, 65, 65, []int{16, 17, 18, 19}},
+	{CardUnionPay, 62, 62, []int{16, 17, 18, 19}},
+	{CardMaestro, 6304, 6304, []int{12, 13, 14, 15, 16, 17, 18, 19}},
+	{CardMaestro, 6759, 6759, []int{12, 13, 14, 15, 16, 17, 18, 19}},
+	{CardMaestro, 676770, 676770, []int{12, 13, 14, 15, 16, 17, 18, 19}},
+	{CardMaestro, 676774, 676774, []int{12, 13, 14, 15, 16, 17, 18, 19}},
+	{CardMaestro, 50, 50, []int{12, 13, 14, 15, 16, 17, 18, 19}},
+	{CardMaestro, 56, 58, []int{12, 13, 14, 15, 16, 17, 18, 19}},
+}
+
+// CardResult holds the result of card number validation.
+type CardResult struct {
+	Result
+	Number string    // The digits of the number, without separators
+	Brand  CardBrand // The detected brand, or CardUnknown
+	Last4  string    // The last four digits, for display
+}
+
+// CreditCard validates a payment card number with the Luhn checksum and
+// detects its brand. Spaces and hyphens between digit groups are allowed.
+// Numbers of a known brand must have one of the brand's lengths; others
+// must have 12 to 19 digits.
+func CreditCard(value string) CardResult {
+	if value == "" {
+		return CardResult{Result: valid}
+	}
+
+	number := strings.NewReplacer(" ", "", "-", "").Replace(value)
+	if number == "" || strings.Trim(number, "0123456789") != "" {
+		return CardResult{Result: invalid("Card number must contain only digits")}
+	}
+
+	brand, lengths := cardBrand(number)
+	if lengths == nil {
+		lengths = []int{12, 13, 14, 15, 16, 17, 18, 19}
+	}
+	if !slices.Contains(lengths, len(number)) {
+		return CardResult{Result: invalid("Invalid card number length"), Brand: brand}
+	}
+	if !luhn(number) {
+		return CardResult{Result: invalid("Invalid card number"), Brand: brand}
+	}
+
+	return CardResult{
+		Result: valid,
+		Number: number,
+		Brand:  brand,
+		Last4:  number[len(number)-4:],
+	}
+}
+
+// cardBrand returns the brand of number and its valid lengths, or
+// CardUnknown and nil.
+func cardBrand(number string) (CardBrand, []int) {
+	for _, r := range cardRanges {
+		n := len(strconv.Itoa(r.from))
+		if len(number) < n {
+			continue
+		}
+		prefix, _ := strconv.Atoi(number[:n])
+		if prefix >= r.from && prefix <= r.to {
+			return r.brand, r.lengths
+		}
+	}
+	return CardUnknown, nil
+}
+
+// luhn reports whether the digits in number pass the Luhn checksum.
+func luhn(number string) bool {
+	sum := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		d := int(number[i] - '0')
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
@@ -0,0 +1,55 @@
+package validators
+
+import "testing"
+
+func TestCreditCard(t *testing.T) {
+	tests := []struct {
+		name   string
+		number string
+		valid  bool
+		errMsg string
+		brand  CardBrand
+	}{
+		{name: "visa", number: "4111 1111 1111 1111", valid: true, brand: CardVisa},
+		{name: "visa 13 digits", number: "4222222222222", valid: true, brand: CardVisa},
+		{name: "mastercard", number: "5555-5555-5555-4444", valid: true, brand: CardMastercard},
+		{name: "mastercard 2-series", number: "2223003122003222", valid: true, brand: CardMastercard},
+		{name: "amex", number: "3782 822463 10005", valid: true, brand: CardAmex},
+		{name: "discover", number: "6011111111111117", valid: true, brand: CardDiscover},
+		{name: "diners", number: "30569309025904", valid: true, brand: CardDiners},
+		{name: "jcb", number: "3530111333300000", valid: true, brand: CardJCB},
+		{name: "unionpay", number: "6200000000000005", valid: true, brand: CardUnionPay},
+		{name: "maestro", number: "6759649826438453", valid: true, brand: CardMaestro},
+		{name: "unknown brand", number: "9999999999999995", valid: true, brand: CardUnknown},
+		{name: "empty is valid", number: "", valid: true},
+
+		{name: "luhn failure", number: "4111111111111112", errMsg: "Invalid card number", brand: CardVisa},
+		{name: "amex length", number: "3782822463100051", errMsg: "Invalid card number length", brand: CardAmex},
+		{name: "too short", number: "42424242", errMsg: "Invalid card number length", brand: CardVisa},
+		{name: "letters", number: "4111 1111 1111 111a", errMsg: "Card number must contain only digits"},
+		{name: "separators only", number: " - ", errMsg: "Card number must contain only digits"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := CreditCard(tt.number)
+
+			if result.Valid != tt.valid {
+				t.Errorf("CreditCard(%q) valid = %v, want %v", tt.number, result.Valid, tt.valid)
+			}
+			if !tt.valid && result.Error != tt.errMsg {
+				t.Errorf("CreditCard(%q) error = %q, want %q", tt.number, result.Error, tt.errMsg)
+			}
+			if result.Brand != tt.brand {
+				t.Errorf("CreditCard(%q) brand = %q, want %q", tt.number, result.Brand, tt.brand)
+			}
+		})
+	}
+}
+
+func TestCreditCard_Parts(t *testing.T) {
+	result := CreditCard("4111 1111 1111 1111")
+	if result.Number != "4111111111111111" || result.Last4 != "1111" {
+		t.Errorf("CreditCard number = %q, last4 = %q", result.Number, result.Last4)
+	}
+}
@@ -18,6 +18,13 @@ type EmailResult struct {
 	Domain string // The domain part of the email (if valid format)
 }
 
+// Result returns the outcome as a Result, for validator.Handler and
+// form.Check. EmailResult predates Result, so it keeps its own Valid and
+// Error fields instead of embedding one.
+func (r EmailResult) Result() Result {
+	return Result{Valid: r.Valid, Error: r.Error}
+}
+
 // Email validates an email address.
 // If checkMX is true, it verifies the domain has MX records.
 func Email(value string, checkMX bool) EmailResult {
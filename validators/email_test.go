@@ -46,6 +46,10 @@ func TestEmail(t *testing.T) {
 			if !tt.valid && result.Error != tt.errMsg {
 				t.Errorf("Email(%q) error = %q, want %q", tt.email, result.Error, tt.errMsg)
 			}
+
+			if got, want := result.Result(), (Result{Valid: result.Valid, Error: result.Error}); got != want {
+				t.Errorf("Email(%q).Result() = %+v, want %+v", tt.email, got, want)
+			}
 		})
 	}
 }
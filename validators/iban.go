@@ -0,0 +1,77 @@
+package validators
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ibanRegex matches the electronic format of an IBAN: a country code,
+// two check digits and up to 30 letters and digits.
+var ibanRegex = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]{1,30}$`)
+
+// ibanLengths are the IBAN lengths of the countries in the SWIFT IBAN
+// registry.
+var ibanLengths = map[string]int{
+	"AD": 24, "AE": 23, "AL": 28, "AT": 20, "AZ": 28, "BA": 20, "BE": 16, "BG": 22,
+	"BH": 22, "BR": 29, "BY": 28, "CH": 21, "CR": 22, "CY": 28, "CZ": 24, "DE": 22,
+	"DK": 18, "DO": 28, "EE": 20, "EG": 29, "ES": 24, "FI": 18, "FO": 18, "FR": 27,
+	"GB": 22, "GE": 22, "GI": 23, "GL": 18, "GR": 27, "GT": 28, "HR": 21, "HU": 28,
+	"IE": 22, "IL": 23, "IQ": 23, "IS": 26, "IT": 27, "JO": 30, "KW": 30, "KZ": 20,
+	"LB": 28, "LC": 32, "LI": 21, "LT": 20, "LU": 20, "LV": 21, "MC": 27, "MD": 24,
+	"ME": 22, "MK": 19, "MR": 27, "MT": 31, "MU": 30, "NL": 18, "NO": 15, "PK": 24,
+	"PL": 28, "PS": 29, "PT": 25, "QA": 29, "RO": 24, "RS": 22, "SA": 24, "SC": 31,
+	"SE": 24, "SI": 19, "SK": 24, "SM": 27, "ST": 25, "SV": 28, "TL": 23, "TN": 24,
+	"TR": 26, "UA": 29, "VA": 22, "VG": 24, "XK": 20,
+}
+
+// IBANResult holds the result of IBAN validation.
+type IBANResult struct {
+	Result
+	IBAN    string // The IBAN in electronic format, e.g. "BE71096123456769"
+	Country string // The ISO 3166 country code, e.g. "BE"
+}
+
+// IBAN validates an International Bank Account Number: its country, its
+// length for that country and its mod-97 check digits. Spaces are allowed
+// and letters may be lower case.
+func IBAN(value string) IBANResult {
+	if value == "" {
+		return IBANResult{Result: valid}
+	}
+
+	iban := strings.ToUpper(strings.Join(strings.Fields(value), ""))
+	if !ibanRegex.MatchString(iban) {
+		return IBANResult{Result: invalid("Invalid IBAN format")}
+	}
+	country := iban[:2]
+	length, ok := ibanLengths[country]
+	if !ok {
+		return IBANResult{Result: invalid("Unknown IBAN country code")}
+	}
+	if len(iban) != length {
+		return IBANResult{Result: invalid("Invalid IBAN length")}
+	}
+	if mod97(iban[4:]+iban[:4]) != 1 {
+		return IBANResult{Result: invalid("Invalid IBAN check digits")}
+	}
+
+	return IBANResult{
+		Result:  valid,
+		IBAN:    iban,
+		Country: country,
+	}
+}
+
+// mod97 returns s modulo 97, reading letters as the numbers 10 to 35 as
+// ISO 7064 prescribes. It works digit by digit, so s may be of any length.
+func mod97(s string) int {
+	r := 0
+	for _, c := range s {
+		if c >= 'A' {
+			r = (r*100 + int(c-'A'+10)) % 97
+		} else {
+			r = (r*10 + int(c-'0')) % 97
+		}
+	}
+	return r
+}
@@ -0,0 +1,44 @@
+package validators
+
+import "testing"
+
+func TestIBAN(t *testing.T) {
+	tests := []struct {
+		name    string
+		iban    string
+		valid   bool
+		errMsg  string
+		want    string
+		country string
+	}{
+		{name: "belgium", iban: "BE71096123456769", valid: true, want: "BE71096123456769", country: "BE"},
+		{name: "grouped", iban: "GB82 WEST 1234 5698 7654 32", valid: true, want: "GB82WEST12345698765432", country: "GB"},
+		{name: "lower case", iban: "de89 3704 0044 0532 0130 00", valid: true, want: "DE89370400440532013000", country: "DE"},
+		{name: "letters in bban", iban: "FR14 2004 1010 0505 0001 3M02 606", valid: true, want: "FR1420041010050500013M02606", country: "FR"},
+		{name: "netherlands", iban: "NL91ABNA0417164300", valid: true, want: "NL91ABNA0417164300", country: "NL"},
+		{name: "empty is valid", iban: "", valid: true},
+
+		{name: "wrong check digits", iban: "BE71096123456768", errMsg: "Invalid IBAN check digits"},
+		{name: "swapped digits", iban: "BE71096123456796", errMsg: "Invalid IBAN check digits"},
+		{name: "too short for country", iban: "BE7109612345676", errMsg: "Invalid IBAN length"},
+		{name: "unknown country", iban: "ZZ71096123456769", errMsg: "Unknown IBAN country code"},
+		{name: "no check digits", iban: "BEXX096123456769", errMsg: "Invalid IBAN format"},
+		{name: "symbols", iban: "BE71-0961-2345-6769", errMsg: "Invalid IBAN format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := IBAN(tt.iban)
+
+			if result.Valid != tt.valid {
+				t.Errorf("IBAN(%q) valid = %v, want %v", tt.iban, result.Valid, tt.valid)
+			}
+			if !tt.valid && result.Error != tt.errMsg {
+				t.Errorf("IBAN(%q) error = %q, want %q", tt.iban, result.Error, tt.errMsg)
+			}
+			if result.IBAN != tt.want || result.Country != tt.country {
+				t.Errorf("IBAN(%q) = %q (%q), want %q (%q)", tt.iban, result.IBAN, result.Country, tt.want, tt.country)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package validators
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PasswordScore rates password strength from PasswordVeryWeak to
+// PasswordVeryStrong.
+type PasswordScore int
+
+const (
+	PasswordVeryWeak PasswordScore = iota
+	PasswordWeak
+	PasswordFair
+	PasswordStrong
+	PasswordVeryStrong
+)
+
+// String returns the score as a label for strength meters.
+func (s PasswordScore) String() string {
+	switch s {
+	case PasswordVeryWeak:
+		return "Very weak"
+	case PasswordWeak:
+		return "Weak"
+	case PasswordFair:
+		return "Fair"
+	case PasswordStrong:
+		return "Strong"
+	default:
+		return "Very strong"
+	}
+}
+
+// commonPasswords are passwords at the top of every breach list. A password
+// that is one of them, ignoring case and trailing digits and symbols,
+// scores PasswordVeryWeak.
+var commonPasswords = map[string]bool{
+	"password": true, "passw0rd": true, "qwerty": true, "qwertyuiop": true, "azerty": true,
+	"letmein": true, "welcome": true, "admin": true, "administrator": true, "login": true,
+	"iloveyou": true, "monkey": true, "dragon": true, "master": true, "sunshine": true,
+	"princess": true, "football": true, "baseball": true, "shadow": true, "superman": true,
+	"trustno1": true, "abc": true, "abcdef": true, "secret": true, "changeme": true,
+	"default": true, "test": true, "guest": true, "user": true, "root": true,
+}
+
+// PasswordOptions configures password validation.
+type PasswordOptions struct {
+	// MinLength is the minimum number of characters. Defaults to 8.
+	MinLength int
+	// MinScore is the minimum score for a valid password. Defaults to
+	// PasswordFair.
+	MinScore PasswordScore
+	// Avoid are words the password must not contain, such as the user's
+	// name or email address. Matching ignores case.
+	Avoid []string
+}
+
+func resolvePasswordOptions(opts []PasswordOptions) PasswordOptions {
+	var o PasswordOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if o.MinLength <= 0 {
+		o.MinLength = 8
+	}
+	if o.MinScore == PasswordVeryWeak {
+		o.MinScore = PasswordFair
+	}
+	return o
+}
+
+// PasswordResult holds the result of password validation.
+type PasswordResult struct {
+	Result
+	Score PasswordScore
+	// Suggestions are hints for a stronger password, for display below
+	// the field.
+	Suggestions []string
+}
+
+// Password scores the strength of a password by its estimated entropy: its
+// length and the kinds of characters it uses, with penalties for repeated
+// characters, sequences like "abcd" or "1234", common passwords and the
+// words in PasswordOptions.Avoid. It is valid when it is long enough and
+// scores at least PasswordOptions.MinScore.
+func Password(value string, opts ...PasswordOptions) PasswordResult {
+	if value == "" {
+		return PasswordResult{Result: valid}
+	}
+	o := resolvePasswordOptions(opts)
+
+	score, suggestions := scorePassword(value, o.Avoid)
+	result := PasswordResult{Result: valid, Score: score, Suggestions: suggestions}
+	switch {
+	case utf8.RuneCountInString(value) < o.MinLength:
+		result.Result = invalid(fmt.Sprintf("Password must be at least %d characters", o.MinLength))
+	case score < o.MinScore:
+		result.Result = invalid("Password is too weak")
+	}
+	return result
+}
+
+// scorePassword returns the score of password and how to improve it.
+func scorePassword(password string, avoid []string) (PasswordScore, []string) {
+	var suggestions []string
+	lower := strings.ToLower(password)
+
+	if commonPasswords[strings.TrimRightFunc(lower, func(r rune) bool { return !unicode.IsLetter(r) })] {
+		return PasswordVeryWeak, []string{"Avoid common passwords"}
+	}
+	for _, word := range avoid {
+		if word = strings.ToLower(strings.TrimSpace(word)); len(word) >= 3 && strings.Contains(lower, word) {
+			return PasswordVeryWeak, []string{"Avoid your name and email address"}
+		}
+	}
+
+	var hasLower, hasUpper, hasDigit, hasSymbol, hasOther bool
+	for _, r := range password {
+		switch {
+		case r >= 'a' && r <= 'z':
+			hasLower = true
+		case r >= 'A' && r <= 'Z':
+			hasUpper = true
+		case r >= '0' && r <= '9':
+			hasDigit = true
+		case r < utf8.RuneSelf:
+			hasSymbol = true
+		default:
+			hasOther = true
+		}
+	}
+	pool := 0
+	for _, class := range []struct {
+		present bool
+		size    int
+		hint    string
+	}{
+		{hasLower, 26, "Add lower case letters"},
+		{hasUpper, 26, "Add upper case letters"},
+		{hasDigit, 10, "Add numbers"},
+		{hasSymbol, 33, "Add symbols"},
+		{hasOther, 100, ""},
+	} {
+		if class.present {
+			pool += class.size
+		} else if class.hint != "" {
+			suggestions = append(suggestions, class.hint)
+		}
+	}
+
+	// Characters that repeat or continue a sequence add almost nothing.
+	effective := 0.0
+	var prev rune = -1
+	runes := 0
+	for _, r := range strings.ToLower(password) {
+		if r == prev || r == prev+1 || r == prev-1 {
+			effective += 0.25
+		} else {
+			effective++
+		}
+		prev = r
+		runes++
+	}
+	if effective < float64(runes)*0.75 {
+		suggestions = append(suggestions, "Avoid repeated characters and sequences")
+	}
+
+	bits := effective * math.Log2(float64(pool))
+	var score PasswordScore
+	switch {
+	case bits < 28:
+		score = PasswordVeryWeak
+	case bits < 36:
+		score = PasswordWeak
+	case bits < 60:
+		score = PasswordFair
+	case bits < 80:
+		score = PasswordStrong
+	default:
+		score = PasswordVeryStrong
+	}
+	if score < PasswordStrong && runes < 12 {
+		suggestions = append(suggestions, "Use a longer password")
+	}
+	if score >= PasswordStrong {
+		suggestions = nil
+	}
+	return score, suggestions
+}
@@ -0,0 +1,71 @@
+package validators
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPassword(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		opts     PasswordOptions
+		valid    bool
+		errMsg   string
+		score    PasswordScore
+	}{
+		{name: "passphrase", password: "correct horse battery staple", valid: true, score: PasswordVeryStrong},
+		{name: "mixed", password: "Tr0ub4dor&3", valid: true, score: PasswordStrong},
+		{name: "fair", password: "Summer2024", valid: true, score: PasswordFair},
+		{name: "empty is valid", password: "", valid: true},
+
+		{name: "too short", password: "Ab1!", errMsg: "Password must be at least 8 characters", score: PasswordVeryWeak},
+		{name: "custom length", password: "Tr0ub4dor&3", opts: PasswordOptions{MinLength: 12}, errMsg: "Password must be at least 12 characters", score: PasswordStrong},
+		{name: "common", password: "Password123!", errMsg: "Password is too weak", score: PasswordVeryWeak},
+		{name: "sequence", password: "abcdefgh1234", errMsg: "Password is too weak", score: PasswordVeryWeak},
+		{name: "repeated", password: "aaaaaaaaaaaa", errMsg: "Password is too weak", score: PasswordVeryWeak},
+		{name: "weak", password: "hello123", errMsg: "Password is too weak", score: PasswordWeak},
+		{name: "higher minimum", password: "Summer2024", opts: PasswordOptions{MinScore: PasswordStrong}, errMsg: "Password is too weak", score: PasswordFair},
+		{name: "contains name", password: "PascalRocks!2024", opts: PasswordOptions{Avoid: []string{"pascal"}}, errMsg: "Password is too weak", score: PasswordVeryWeak},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Password(tt.password, tt.opts)
+
+			if result.Valid != tt.valid {
+				t.Errorf("Password(%q) valid = %v, want %v", tt.password, result.Valid, tt.valid)
+			}
+			if !tt.valid && result.Error != tt.errMsg {
+				t.Errorf("Password(%q) error = %q, want %q", tt.password, result.Error, tt.errMsg)
+			}
+			if result.Score != tt.score {
+				t.Errorf("Password(%q) score = %v, want %v", tt.password, result.Score, tt.score)
+			}
+		})
+	}
+}
+
+func TestPassword_Suggestions(t *testing.T) {
+	tests := []struct {
+		password string
+		want     string
+	}{
+		{"hello123", "Add upper case letters"},
+		{"HELLOWORLD", "Add lower case letters"},
+		{"helloWorld", "Add numbers"},
+		{"Hello123", "Add symbols"},
+		{"abcdefgh1234", "Avoid repeated characters and sequences"},
+		{"password1", "Avoid common passwords"},
+		{"hello123", "Use a longer password"},
+	}
+	for _, tt := range tests {
+		if got := Password(tt.password).Suggestions; !slices.Contains(got, tt.want) {
+			t.Errorf("Password(%q) suggestions = %q, want %q among them", tt.password, got, tt.want)
+		}
+	}
+
+	if got := Password("correct horse battery staple").Suggestions; got != nil {
+		t.Errorf("strong password suggestions = %q, want none", got)
+	}
+}
@@ -0,0 +1,69 @@
+package validators
+
+import (
+	"regexp"
+	"strings"
+)
+
+// e164Regex matches a phone number in E.164 format: a plus sign and up to
+// 15 digits, the first of which is never 0.
+var e164Regex = regexp.MustCompile(`^\+[1-9]\d{6,14}$`)
+
+// phoneSeparators are the characters people type between digit groups.
+var phoneSeparators = strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "", "\u00a0", "")
+
+// twoDigitCallingCodes are the country calling codes of two digits. Codes
+// starting with 1 or 7 have one digit, and all others have three.
+var twoDigitCallingCodes = map[string]bool{
+	"20": true, "27": true, "30": true, "31": true, "32": true, "33": true, "34": true,
+	"36": true, "39": true, "40": true, "41": true, "43": true, "44": true, "45": true,
+	"46": true, "47": true, "48": true, "49": true, "51": true, "52": true, "53": true,
+	"54": true, "55": true, "56": true, "57": true, "58": true, "60": true, "61": true,
+	"62": true, "63": true, "64": true, "65": true, "66": true, "81": true, "82": true,
+	"84": true, "86": true, "90": true, "91": true, "92": true, "93": true, "94": true,
+	"95": true, "98": true,
+}
+
+// PhoneResult holds the result of phone number validation.
+type PhoneResult struct {
+	Result
+	E164        string // The number without separators, e.g. "+32470123456"
+	CallingCode string // The country calling code, e.g. "32"
+}
+
+// Phone validates an international phone number in E.164 format. Spaces,
+// hyphens, dots and parentheses between the digits are allowed and removed.
+func Phone(value string) PhoneResult {
+	if value == "" {
+		return PhoneResult{Result: valid}
+	}
+
+	number := phoneSeparators.Replace(strings.TrimSpace(value))
+	if strings.HasPrefix(number, "00") {
+		return PhoneResult{Result: invalid("Start the number with + instead of 00")}
+	}
+	if !strings.HasPrefix(number, "+") {
+		return PhoneResult{Result: invalid("Include the country code, e.g. +32")}
+	}
+	if !e164Regex.MatchString(number) {
+		return PhoneResult{Result: invalid("Invalid phone number")}
+	}
+
+	return PhoneResult{
+		Result:      valid,
+		E164:        number,
+		CallingCode: callingCode(number[1:]),
+	}
+}
+
+// callingCode returns the country calling code at the start of digits.
+func callingCode(digits string) string {
+	switch {
+	case digits[0] == '1' || digits[0] == '7':
+		return digits[:1]
+	case twoDigitCallingCodes[digits[:2]]:
+		return digits[:2]
+	default:
+		return digits[:3]
+	}
+}
@@ -0,0 +1,44 @@
+package validators
+
+import "testing"
+
+func TestPhone(t *testing.T) {
+	tests := []struct {
+		name        string
+		phone       string
+		valid       bool
+		errMsg      string
+		e164        string
+		callingCode string
+	}{
+		{name: "belgian mobile", phone: "+32470123456", valid: true, e164: "+32470123456", callingCode: "32"},
+		{name: "with separators", phone: "+1 (415) 555-2671", valid: true, e164: "+14155552671", callingCode: "1"},
+		{name: "with dots", phone: "+44.20.7946.0958", valid: true, e164: "+442079460958", callingCode: "44"},
+		{name: "three digit code", phone: "+352 621 123 456", valid: true, e164: "+352621123456", callingCode: "352"},
+		{name: "russia", phone: "+7 912 345 67 89", valid: true, e164: "+79123456789", callingCode: "7"},
+		{name: "empty is valid", phone: "", valid: true},
+
+		{name: "no country code", phone: "0470 12 34 56", errMsg: "Include the country code, e.g. +32"},
+		{name: "00 prefix", phone: "0032470123456", errMsg: "Start the number with + instead of 00"},
+		{name: "too long", phone: "+1234567890123456", errMsg: "Invalid phone number"},
+		{name: "too short", phone: "+3212", errMsg: "Invalid phone number"},
+		{name: "leading zero", phone: "+0470123456", errMsg: "Invalid phone number"},
+		{name: "letters", phone: "+32 470 CALL ME", errMsg: "Invalid phone number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Phone(tt.phone)
+
+			if result.Valid != tt.valid {
+				t.Errorf("Phone(%q) valid = %v, want %v", tt.phone, result.Valid, tt.valid)
+			}
+			if !tt.valid && result.Error != tt.errMsg {
+				t.Errorf("Phone(%q) error = %q, want %q", tt.phone, result.Error, tt.errMsg)
+			}
+			if result.E164 != tt.e164 || result.CallingCode != tt.callingCode {
+				t.Errorf("Phone(%q) = %q (code %q), want %q (code %q)", tt.phone, result.E164, result.CallingCode, tt.e164, tt.callingCode)
+			}
+		})
+	}
+}
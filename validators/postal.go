@@ -0,0 +1,90 @@
+package validators
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// postalCodeRegexes are the postal code formats by ISO 3166 country code,
+// matched against the upper-cased code with single spaces.
+var postalCodeRegexes = map[string]*regexp.Regexp{
+	"AT": regexp.MustCompile(`^[1-9]\d{3}$`),
+	"AU": regexp.MustCompile(`^\d{4}$`),
+	"BE": regexp.MustCompile(`^[1-9]\d{3}$`),
+	"BG": regexp.MustCompile(`^\d{4}$`),
+	"BR": regexp.MustCompile(`^\d{5}-?\d{3}$`),
+	"CA": regexp.MustCompile(`^[ABCEGHJ-NPRSTVXY]\d[ABCEGHJ-NPRSTV-Z] ?\d[ABCEGHJ-NPRSTV-Z]\d$`),
+	"CH": regexp.MustCompile(`^[1-9]\d{3}$`),
+	"CY": regexp.MustCompile(`^\d{4}$`),
+	"CZ": regexp.MustCompile(`^\d{3} ?\d{2}$`),
+	"DE": regexp.MustCompile(`^\d{5}$`),
+	"DK": regexp.MustCompile(`^\d{4}$`),
+	"EE": regexp.MustCompile(`^\d{5}$`),
+	"ES": regexp.MustCompile(`^(?:0[1-9]|[1-4]\d|5[0-2])\d{3}$`),
+	"FI": regexp.MustCompile(`^\d{5}$`),
+	"FR": regexp.MustCompile(`^\d{5}$`),
+	"GB": regexp.MustCompile(`^(?:GIR 0AA|[A-Z]{1,2}\d[A-Z\d]? ?\d[A-Z]{2})$`),
+	"GR": regexp.MustCompile(`^\d{3} ?\d{2}$`),
+	"HR": regexp.MustCompile(`^\d{5}$`),
+	"HU": regexp.MustCompile(`^[1-9]\d{3}$`),
+	"IE": regexp.MustCompile(`^[AC-FHKNPRTV-Y][0-9W][0-9W] ?[0-9AC-FHKNPRTV-Y]{4}$`),
+	"IN": regexp.MustCompile(`^[1-9]\d{2} ?\d{3}$`),
+	"IT": regexp.MustCompile(`^\d{5}$`),
+	"JP": regexp.MustCompile(`^\d{3}-?\d{4}$`),
+	"LT": regexp.MustCompile(`^(?:LT-)?\d{5}$`),
+	"LU": regexp.MustCompile(`^(?:L-)?\d{4}$`),
+	"LV": regexp.MustCompile(`^(?:LV-)?\d{4}$`),
+	"MT": regexp.MustCompile(`^[A-Z]{3} ?\d{4}$`),
+	"MX": regexp.MustCompile(`^\d{5}$`),
+	"NL": regexp.MustCompile(`^[1-9]\d{3} ?(?:[A-RT-Z][A-Z]|S[BCE-RT-Z])$`),
+	"NO": regexp.MustCompile(`^\d{4}$`),
+	"NZ": regexp.MustCompile(`^\d{4}$`),
+	"PL": regexp.MustCompile(`^\d{2}-\d{3}$`),
+	"PT": regexp.MustCompile(`^\d{4}-\d{3}$`),
+	"RO": regexp.MustCompile(`^\d{6}$`),
+	"SE": regexp.MustCompile(`^[1-9]\d{2} ?\d{2}$`),
+	"SI": regexp.MustCompile(`^(?:SI-)?\d{4}$`),
+	"SK": regexp.MustCompile(`^\d{3} ?\d{2}$`),
+	"US": regexp.MustCompile(`^\d{5}(?:-\d{4})?$`),
+}
+
+// PostalCodeCountries returns the country codes PostalCode supports,
+// sorted.
+func PostalCodeCountries() []string {
+	countries := make([]string, 0, len(postalCodeRegexes))
+	for c := range postalCodeRegexes {
+		countries = append(countries, c)
+	}
+	slices.Sort(countries)
+	return countries
+}
+
+// PostalCodeResult holds the result of postal code validation.
+type PostalCodeResult struct {
+	Result
+	PostalCode string // The code upper-cased with single spaces, e.g. "1012 AB"
+}
+
+// PostalCode validates a postal code for the ISO 3166 alpha-2 country
+// code, such as "BE" or "US". Countries without a known format, see
+// PostalCodeCountries, are reported as unsupported.
+func PostalCode(value, country string) PostalCodeResult {
+	if value == "" {
+		return PostalCodeResult{Result: valid}
+	}
+
+	re, ok := postalCodeRegexes[strings.ToUpper(country)]
+	if !ok {
+		return PostalCodeResult{Result: invalid("Postal codes are not supported for this country")}
+	}
+	code := strings.ToUpper(strings.Join(strings.Fields(value), " "))
+	if !re.MatchString(code) {
+		return PostalCodeResult{Result: invalid("Invalid postal code")}
+	}
+
+	return PostalCodeResult{
+		Result:     valid,
+		PostalCode: code,
+	}
+}
@@ -0,0 +1,59 @@
+package validators
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPostalCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    string
+		country string
+		valid   bool
+		errMsg  string
+		want    string
+	}{
+		{name: "belgium", code: "1000", country: "BE", valid: true, want: "1000"},
+		{name: "netherlands", code: "1012ab", country: "nl", valid: true, want: "1012AB"},
+		{name: "netherlands spaced", code: " 1012  AB ", country: "NL", valid: true, want: "1012 AB"},
+		{name: "us zip+4", code: "94105-1804", country: "US", valid: true, want: "94105-1804"},
+		{name: "uk", code: "SW1A 1AA", country: "GB", valid: true, want: "SW1A 1AA"},
+		{name: "uk compact", code: "m11ae", country: "GB", valid: true, want: "M11AE"},
+		{name: "canada", code: "K1A 0B1", country: "CA", valid: true, want: "K1A 0B1"},
+		{name: "ireland", code: "D02 X285", country: "IE", valid: true, want: "D02 X285"},
+		{name: "poland", code: "00-950", country: "PL", valid: true, want: "00-950"},
+		{name: "japan", code: "100-0001", country: "JP", valid: true, want: "100-0001"},
+		{name: "empty is valid", code: "", country: "BE", valid: true},
+
+		{name: "belgium leading zero", code: "0999", country: "BE", errMsg: "Invalid postal code"},
+		{name: "germany too short", code: "1011", country: "DE", errMsg: "Invalid postal code"},
+		{name: "netherlands SS", code: "1012 SS", country: "NL", errMsg: "Invalid postal code"},
+		{name: "canada bad letter", code: "D1A 0B1", country: "CA", errMsg: "Invalid postal code"},
+		{name: "us letters", code: "9410A", country: "US", errMsg: "Invalid postal code"},
+		{name: "unsupported country", code: "12345", country: "ZZ", errMsg: "Postal codes are not supported for this country"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := PostalCode(tt.code, tt.country)
+
+			if result.Valid != tt.valid {
+				t.Errorf("PostalCode(%q, %q) valid = %v, want %v", tt.code, tt.country, result.Valid, tt.valid)
+			}
+			if !tt.valid && result.Error != tt.errMsg {
+				t.Errorf("PostalCode(%q, %q) error = %q, want %q", tt.code, tt.country, result.Error, tt.errMsg)
+			}
+			if result.PostalCode != tt.want {
+				t.Errorf("PostalCode(%q, %q) = %q, want %q", tt.code, tt.country, result.PostalCode, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostalCodeCountries(t *testing.T) {
+	countries := PostalCodeCountries()
+	if !slices.IsSorted(countries) || !slices.Contains(countries, "BE") || !slices.Contains(countries, "US") {
+		t.Errorf("PostalCodeCountries() = %v", countries)
+	}
+}
@@ -0,0 +1,34 @@
+// Package validators checks common field types: email addresses, phone
+// numbers, URLs, IBANs, card numbers, postal codes, EU VAT numbers,
+// passwords, slugs and usernames.
+//
+// Every validator treats the empty string as valid, so optional fields
+// need no special case; use the HTML required attribute for mandatory
+// ones. Each returns a Result, on its own or embedded in a type carrying
+// what was learned from the value, such as the card brand; Email's result
+// converts with its Result method. A Result plugs straight into
+// validator.Handler and form.Check:
+//
+//	r.Get("/api/validate/iban", validator.Handler(func(v string) validator.Result {
+//	    return validators.IBAN(v).Result
+//	}))
+//
+//	errs = append(errs, form.Check("iban_error", validators.IBAN(signals.IBAN).Result)...)
+//
+// Validate secrets such as passwords and card numbers over POST, so they
+// stay out of URLs and access logs: set validator.InputProps.Post and
+// mount the handler with r.Post.
+package validators
+
+// Result is the outcome of a validation.
+type Result struct {
+	Valid bool
+	// Error is the message to show the user when Valid is false.
+	Error string
+}
+
+var valid = Result{Valid: true}
+
+func invalid(message string) Result {
+	return Result{Error: message}
+}
@@ -0,0 +1,44 @@
+package validators
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// slugRegex matches lower-case words of letters and digits joined by
+// single hyphens.
+var slugRegex = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+// SlugOptions configures slug validation.
+type SlugOptions struct {
+	// MaxLength is the maximum number of characters. Defaults to 100.
+	MaxLength int
+}
+
+func resolveSlugOptions(opts []SlugOptions) SlugOptions {
+	var o SlugOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if o.MaxLength <= 0 {
+		o.MaxLength = 100
+	}
+	return o
+}
+
+// Slug validates a URL slug such as "my-first-post": lower-case letters
+// and digits, with single hyphens between words.
+func Slug(value string, opts ...SlugOptions) Result {
+	if value == "" {
+		return valid
+	}
+	o := resolveSlugOptions(opts)
+
+	if len(value) > o.MaxLength {
+		return invalid(fmt.Sprintf("Slug must be at most %d characters", o.MaxLength))
+	}
+	if !slugRegex.MatchString(value) {
+		return invalid("Use lower-case letters, digits and single hyphens between words")
+	}
+	return valid
+}
@@ -0,0 +1,39 @@
+package validators
+
+import "testing"
+
+func TestSlug(t *testing.T) {
+	tests := []struct {
+		name   string
+		slug   string
+		opts   SlugOptions
+		valid  bool
+		errMsg string
+	}{
+		{name: "words", slug: "my-first-post", valid: true},
+		{name: "digits", slug: "2024-recap", valid: true},
+		{name: "single word", slug: "about", valid: true},
+		{name: "empty is valid", slug: "", valid: true},
+
+		{name: "upper case", slug: "My-Post", errMsg: "Use lower-case letters, digits and single hyphens between words"},
+		{name: "double hyphen", slug: "my--post", errMsg: "Use lower-case letters, digits and single hyphens between words"},
+		{name: "leading hyphen", slug: "-post", errMsg: "Use lower-case letters, digits and single hyphens between words"},
+		{name: "trailing hyphen", slug: "post-", errMsg: "Use lower-case letters, digits and single hyphens between words"},
+		{name: "underscore", slug: "my_post", errMsg: "Use lower-case letters, digits and single hyphens between words"},
+		{name: "accents", slug: "café", errMsg: "Use lower-case letters, digits and single hyphens between words"},
+		{name: "too long", slug: "a-very-long-slug", opts: SlugOptions{MaxLength: 10}, errMsg: "Slug must be at most 10 characters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Slug(tt.slug, tt.opts)
+
+			if result.Valid != tt.valid {
+				t.Errorf("Slug(%q) valid = %v, want %v", tt.slug, result.Valid, tt.valid)
+			}
+			if !tt.valid && result.Error != tt.errMsg {
+				t.Errorf("Slug(%q) error = %q, want %q", tt.slug, result.Error, tt.errMsg)
+			}
+		})
+	}
+}
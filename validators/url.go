@@ -0,0 +1,72 @@
+package validators
+
+import (
+	"net"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// hostRegex validates a host name requiring a TLD, like the domain of an
+// email address.
+var hostRegex = regexp.MustCompile(`^[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*\.[a-zA-Z]{2,63}$`)
+
+// URLOptions configures URL validation.
+type URLOptions struct {
+	// Schemes are the accepted schemes. Defaults to http and https.
+	Schemes []string
+	// AllowIP accepts IP addresses as the host.
+	AllowIP bool
+}
+
+func resolveURLOptions(opts []URLOptions) URLOptions {
+	var o URLOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if len(o.Schemes) == 0 {
+		o.Schemes = []string{"http", "https"}
+	}
+	return o
+}
+
+// URLResult holds the result of URL validation.
+type URLResult struct {
+	Result
+	Host string // The host name, without port (if valid)
+}
+
+// URL validates an absolute URL. The host must be a domain name with a
+// TLD, or an IP address when URLOptions.AllowIP is set.
+func URL(value string, opts ...URLOptions) URLResult {
+	if value == "" {
+		return URLResult{Result: valid}
+	}
+	o := resolveURLOptions(opts)
+
+	u, err := url.Parse(strings.TrimSpace(value))
+	if err != nil || u.Scheme == "" || u.Opaque != "" {
+		return URLResult{Result: invalid("Invalid URL")}
+	}
+	if !slices.Contains(o.Schemes, strings.ToLower(u.Scheme)) {
+		return URLResult{Result: invalid("URL must start with " + strings.Join(o.Schemes, ":// or ") + "://")}
+	}
+
+	host := u.Hostname()
+	switch {
+	case host == "":
+		return URLResult{Result: invalid("Invalid URL")}
+	case net.ParseIP(host) != nil:
+		if !o.AllowIP {
+			return URLResult{Result: invalid("URL must use a domain name")}
+		}
+	case !hostRegex.MatchString(host):
+		return URLResult{Result: invalid("Invalid domain in URL")}
+	}
+
+	return URLResult{
+		Result: valid,
+		Host:   host,
+	}
+}
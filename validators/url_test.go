@@ -0,0 +1,47 @@
+package validators
+
+import "testing"
+
+func TestURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		opts   URLOptions
+		valid  bool
+		errMsg string
+		host   string
+	}{
+		{name: "https", url: "https://example.com", valid: true, host: "example.com"},
+		{name: "path and query", url: "http://www.example.com:8080/a/b?c=d#e", valid: true, host: "www.example.com"},
+		{name: "upper case scheme", url: "HTTPS://Example.COM", valid: true, host: "Example.COM"},
+		{name: "custom scheme", url: "ftp://files.example.org/pub", opts: URLOptions{Schemes: []string{"ftp"}}, valid: true, host: "files.example.org"},
+		{name: "ip allowed", url: "http://192.168.1.10/admin", opts: URLOptions{AllowIP: true}, valid: true, host: "192.168.1.10"},
+		{name: "ipv6 allowed", url: "http://[::1]:3000", opts: URLOptions{AllowIP: true}, valid: true, host: "::1"},
+		{name: "empty is valid", url: "", valid: true},
+
+		{name: "no scheme", url: "example.com", errMsg: "Invalid URL"},
+		{name: "mailto", url: "mailto:test@example.com", errMsg: "Invalid URL"},
+		{name: "javascript", url: "javascript://example.com/%0Aalert(1)", errMsg: "URL must start with http:// or https://"},
+		{name: "no host", url: "https:///path", errMsg: "Invalid URL"},
+		{name: "no TLD", url: "http://localhost:8080", errMsg: "Invalid domain in URL"},
+		{name: "bad label", url: "https://-example.com", errMsg: "Invalid domain in URL"},
+		{name: "ip not allowed", url: "http://10.0.0.1", errMsg: "URL must use a domain name"},
+		{name: "space", url: "https://exa mple.com", errMsg: "Invalid URL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := URL(tt.url, tt.opts)
+
+			if result.Valid != tt.valid {
+				t.Errorf("URL(%q) valid = %v, want %v", tt.url, result.Valid, tt.valid)
+			}
+			if !tt.valid && result.Error != tt.errMsg {
+				t.Errorf("URL(%q) error = %q, want %q", tt.url, result.Error, tt.errMsg)
+			}
+			if result.Host != tt.host {
+				t.Errorf("URL(%q) host = %q, want %q", tt.url, result.Host, tt.host)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package validators
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// usernameRegex matches letters and digits, optionally separated by single
+// dots, hyphens or underscores.
+var usernameRegex = regexp.MustCompile(`^[a-zA-Z0-9]+(?:[._-][a-zA-Z0-9]+)*$`)
+
+// ReservedUsernames are names taken by the system or easily mistaken for
+// it, rejected by Username unless UsernameOptions.Reserved replaces them.
+var ReservedUsernames = []string{
+	"admin", "administrator", "api", "help", "info", "mod", "moderator",
+	"no-reply", "noreply", "null", "root", "security", "support", "system",
+	"undefined", "webmaster", "www",
+}
+
+// UsernameOptions configures username validation.
+type UsernameOptions struct {
+	// MinLength is the minimum number of characters. Defaults to 3.
+	MinLength int
+	// MaxLength is the maximum number of characters. Defaults to 30.
+	MaxLength int
+	// Reserved are names that are not available, compared ignoring case.
+	// Defaults to ReservedUsernames; set an empty non-nil slice to allow
+	// every name.
+	Reserved []string
+}
+
+func resolveUsernameOptions(opts []UsernameOptions) UsernameOptions {
+	var o UsernameOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if o.MinLength <= 0 {
+		o.MinLength = 3
+	}
+	if o.MaxLength <= 0 {
+		o.MaxLength = 30
+	}
+	if o.Reserved == nil {
+		o.Reserved = ReservedUsernames
+	}
+	return o
+}
+
+// Username validates a username: letters and digits, optionally separated
+// by single dots, hyphens or underscores, within the length limits and not
+// reserved.
+func Username(value string, opts ...UsernameOptions) Result {
+	if value == "" {
+		return valid
+	}
+	o := resolveUsernameOptions(opts)
+
+	switch {
+	case len(value) < o.MinLength:
+		return invalid(fmt.Sprintf("Username must be at least %d characters", o.MinLength))
+	case len(value) > o.MaxLength:
+		return invalid(fmt.Sprintf("Username must be at most %d characters", o.MaxLength))
+	case !usernameRegex.MatchString(value):
+		return invalid("Use letters and digits, separated by single dots, hyphens or underscores")
+	case slices.ContainsFunc(o.Reserved, func(r string) bool { return strings.EqualFold(r, value) }):
+		return invalid("This username is not available")
+	}
+	return valid
+}
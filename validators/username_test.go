@@ -0,0 +1,41 @@
+package validators
+
+import "testing"
+
+func TestUsername(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		opts     UsernameOptions
+		valid    bool
+		errMsg   string
+	}{
+		{name: "simple", username: "pascal", valid: true},
+		{name: "separators", username: "jane.doe_42-x", valid: true},
+		{name: "mixed case", username: "JaneDoe", valid: true},
+		{name: "reserved allowed", username: "admin", opts: UsernameOptions{Reserved: []string{}}, valid: true},
+		{name: "empty is valid", username: "", valid: true},
+
+		{name: "too short", username: "jo", errMsg: "Username must be at least 3 characters"},
+		{name: "too long", username: "abcdefghijklmnopqrstuvwxyz12345", errMsg: "Username must be at most 30 characters"},
+		{name: "custom max", username: "janedoe", opts: UsernameOptions{MaxLength: 5}, errMsg: "Username must be at most 5 characters"},
+		{name: "leading dot", username: ".jane", errMsg: "Use letters and digits, separated by single dots, hyphens or underscores"},
+		{name: "double separator", username: "jane__doe", errMsg: "Use letters and digits, separated by single dots, hyphens or underscores"},
+		{name: "space", username: "jane doe", errMsg: "Use letters and digits, separated by single dots, hyphens or underscores"},
+		{name: "reserved", username: "Admin", errMsg: "This username is not available"},
+		{name: "custom reserved", username: "webx", opts: UsernameOptions{Reserved: []string{"webx"}}, errMsg: "This username is not available"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Username(tt.username, tt.opts)
+
+			if result.Valid != tt.valid {
+				t.Errorf("Username(%q) valid = %v, want %v", tt.username, result.Valid, tt.valid)
+			}
+			if !tt.valid && result.Error != tt.errMsg {
+				t.Errorf("Username(%q) error = %q, want %q", tt.username, result.Error, tt.errMsg)
+			}
+		})
+	}
+}
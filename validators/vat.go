@@ -0,0 +1,82 @@
+package validators
+
+import (
+	"regexp"
+	"strings"
+)
+
+// vatRegexes are the VAT number formats of the EU member states, keyed by
+// the prefix of the number. Greece uses EL rather than its ISO code, and
+// XI is Northern Ireland.
+var vatRegexes = map[string]*regexp.Regexp{
+	"AT": regexp.MustCompile(`^U\d{8}$`),
+	"BE": regexp.MustCompile(`^[01]\d{9}$`),
+	"BG": regexp.MustCompile(`^\d{9,10}$`),
+	"CY": regexp.MustCompile(`^\d{8}[A-Z]$`),
+	"CZ": regexp.MustCompile(`^\d{8,10}$`),
+	"DE": regexp.MustCompile(`^\d{9}$`),
+	"DK": regexp.MustCompile(`^\d{8}$`),
+	"EE": regexp.MustCompile(`^\d{9}$`),
+	"EL": regexp.MustCompile(`^\d{9}$`),
+	"ES": regexp.MustCompile(`^[A-Z0-9]\d{7}[A-Z0-9]$`),
+	"FI": regexp.MustCompile(`^\d{8}$`),
+	"FR": regexp.MustCompile(`^[A-HJ-NP-Z0-9]{2}\d{9}$`),
+	"HR": regexp.MustCompile(`^\d{11}$`),
+	"HU": regexp.MustCompile(`^\d{8}$`),
+	"IE": regexp.MustCompile(`^(?:\d{7}[A-W][A-I]?|\d[A-Z+*]\d{5}[A-W])$`),
+	"IT": regexp.MustCompile(`^\d{11}$`),
+	"LT": regexp.MustCompile(`^(?:\d{9}|\d{12})$`),
+	"LU": regexp.MustCompile(`^\d{8}$`),
+	"LV": regexp.MustCompile(`^\d{11}$`),
+	"MT": regexp.MustCompile(`^\d{8}$`),
+	"NL": regexp.MustCompile(`^\d{9}B\d{2}$`),
+	"PL": regexp.MustCompile(`^\d{10}$`),
+	"PT": regexp.MustCompile(`^\d{9}$`),
+	"RO": regexp.MustCompile(`^[1-9]\d{1,9}$`),
+	"SE": regexp.MustCompile(`^\d{10}01$`),
+	"SI": regexp.MustCompile(`^\d{8}$`),
+	"SK": regexp.MustCompile(`^\d{10}$`),
+	"XI": regexp.MustCompile(`^(?:\d{9}|\d{12}|GD\d{3}|HA\d{3})$`),
+}
+
+// vatSeparators are the characters allowed between the parts of a VAT
+// number.
+var vatSeparators = strings.NewReplacer(" ", "", ".", "", "-", "")
+
+// VATResult holds the result of VAT number validation.
+type VATResult struct {
+	Result
+	VAT     string // The number without separators, e.g. "BE0123456789"
+	Country string // The country prefix, e.g. "BE" (EL for Greece)
+}
+
+// VAT validates the format of an EU VAT identification number, including
+// its country prefix. It does not check that the number is registered;
+// use the VIES service for that.
+func VAT(value string) VATResult {
+	if value == "" {
+		return VATResult{Result: valid}
+	}
+
+	vat := strings.ToUpper(vatSeparators.Replace(strings.TrimSpace(value)))
+	if len(vat) < 3 {
+		return VATResult{Result: invalid("Invalid VAT number")}
+	}
+	country := vat[:2]
+	if country == "GR" {
+		return VATResult{Result: invalid("Greek VAT numbers start with EL")}
+	}
+	re, ok := vatRegexes[country]
+	if !ok {
+		return VATResult{Result: invalid("VAT number must start with an EU country code")}
+	}
+	if !re.MatchString(vat[2:]) {
+		return VATResult{Result: invalid("Invalid VAT number for " + country)}
+	}
+
+	return VATResult{
+		Result:  valid,
+		VAT:     vat,
+		Country: country,
+	}
+}
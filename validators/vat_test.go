@@ -0,0 +1,48 @@
+package validators
+
+import "testing"
+
+func TestVAT(t *testing.T) {
+	tests := []struct {
+		name    string
+		vat     string
+		valid   bool
+		errMsg  string
+		want    string
+		country string
+	}{
+		{name: "belgium", vat: "BE0123456789", valid: true, want: "BE0123456789", country: "BE"},
+		{name: "belgium formatted", vat: "be 0123.456.789", valid: true, want: "BE0123456789", country: "BE"},
+		{name: "netherlands", vat: "NL123456789B01", valid: true, want: "NL123456789B01", country: "NL"},
+		{name: "germany", vat: "DE123456789", valid: true, want: "DE123456789", country: "DE"},
+		{name: "austria", vat: "ATU12345678", valid: true, want: "ATU12345678", country: "AT"},
+		{name: "france", vat: "FR40 303265045", valid: true, want: "FR40303265045", country: "FR"},
+		{name: "greece", vat: "EL123456789", valid: true, want: "EL123456789", country: "EL"},
+		{name: "ireland", vat: "IE6388047V", valid: true, want: "IE6388047V", country: "IE"},
+		{name: "northern ireland", vat: "XIGD123", valid: true, want: "XIGD123", country: "XI"},
+		{name: "empty is valid", vat: "", valid: true},
+
+		{name: "no prefix", vat: "0123456789", errMsg: "VAT number must start with an EU country code"},
+		{name: "non-eu", vat: "GB123456789", errMsg: "VAT number must start with an EU country code"},
+		{name: "greek iso code", vat: "GR123456789", errMsg: "Greek VAT numbers start with EL"},
+		{name: "belgium too short", vat: "BE123456789", errMsg: "Invalid VAT number for BE"},
+		{name: "netherlands missing B", vat: "NL12345678901", errMsg: "Invalid VAT number for NL"},
+		{name: "too short", vat: "BE", errMsg: "Invalid VAT number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := VAT(tt.vat)
+
+			if result.Valid != tt.valid {
+				t.Errorf("VAT(%q) valid = %v, want %v", tt.vat, result.Valid, tt.valid)
+			}
+			if !tt.valid && result.Error != tt.errMsg {
+				t.Errorf("VAT(%q) error = %q, want %q", tt.vat, result.Error, tt.errMsg)
+			}
+			if result.VAT != tt.want || result.Country != tt.country {
+				t.Errorf("VAT(%q) = %q (%q), want %q (%q)", tt.vat, result.VAT, result.Country, tt.want, tt.country)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package webxtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/plaenen/webx"
+	"github.com/starfederation/datastar-go/datastar"
+)
+
+// Event is a decoded Datastar SSE event: PatchElements or PatchSignals.
+type Event interface {
+	eventType() datastar.EventType
+}
+
+// PatchElements is a datastar-patch-elements event.
+type PatchElements struct {
+	// Selector is empty when the elements are matched by their IDs.
+	Selector string
+	// Mode defaults to datastar.ElementPatchModeOuter, as on the client.
+	Mode              datastar.ElementPatchMode
+	Namespace         datastar.Namespace
+	UseViewTransition bool
+	Elements          string
+}
+
+// PatchSignals is a datastar-patch-signals event.
+type PatchSignals struct {
+	// Signals is the decoded patch. Numbers decode as float64.
+	Signals       map[string]any
+	OnlyIfMissing bool
+}
+
+func (PatchElements) eventType() datastar.EventType { return datastar.EventTypePatchElements }
+func (PatchSignals) eventType() datastar.EventType  { return datastar.EventTypePatchSignals }
+
+// Response is a handler's recorded response.
+type Response struct {
+	Code   int
+	Header http.Header
+	Body   string
+	// Events holds the decoded SSE events, in order, when the response is
+	// an event stream.
+	Events []Event
+}
+
+// Elements returns the element patches, in order.
+func (r *Response) Elements() []PatchElements {
+	var out []PatchElements
+	for _, e := range r.Events {
+		if p, ok := e.(PatchElements); ok {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// SignalPatches returns the signal patches, in order.
+func (r *Response) SignalPatches() []PatchSignals {
+	var out []PatchSignals
+	for _, e := range r.Events {
+		if p, ok := e.(PatchSignals); ok {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// Signals applies every signal patch in order to an empty signal tree,
+// the way the client would, and returns the result. Nested objects are
+// merged and null removes a signal.
+func (r *Response) Signals() map[string]any {
+	tree := map[string]any{}
+	for _, p := range r.SignalPatches() {
+		mergePatch(tree, p.Signals, p.OnlyIfMissing)
+	}
+	return tree
+}
+
+// ComponentSignals returns the merged signals patched into the namespace of
+// component id, or nil if there are none.
+func (r *Response) ComponentSignals(id string) map[string]any {
+	m, _ := r.Signals()[webx.SignalID(id)].(map[string]any)
+	return m
+}
+
+// DecodeSignals decodes the merged signals of component id into dest.
+func (r *Response) DecodeSignals(id string, dest any) error {
+	data, err := json.Marshal(r.ComponentSignals(id))
+	if err != nil {
+		return fmt.Errorf("encoding signals of %q: %w", id, err)
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		return fmt.Errorf("decoding signals of %q: %w", id, err)
+	}
+	return nil
+}
+
+func mergePatch(tree, patch map[string]any, onlyIfMissing bool) {
+	for k, v := range patch {
+		switch v := v.(type) {
+		case nil:
+			if !onlyIfMissing {
+				delete(tree, k)
+			}
+		case map[string]any:
+			sub, ok := tree[k].(map[string]any)
+			if !ok {
+				if _, exists := tree[k]; exists && onlyIfMissing {
+					continue
+				}
+				sub = map[string]any{}
+				tree[k] = sub
+			}
+			mergePatch(sub, v, onlyIfMissing)
+		default:
+			if _, exists := tree[k]; exists && onlyIfMissing {
+				continue
+			}
+			tree[k] = v
+		}
+	}
+}
+
+func isEventStream(h http.Header) bool {
+	mt, _, _ := mime.ParseMediaType(h.Get("Content-Type"))
+	return mt == "text/event-stream"
+}
+
+// ParseEvents decodes a Datastar SSE stream. It fails on event types and
+// data lines Datastar does not define.
+func ParseEvents(stream string) ([]Event, error) {
+	var events []Event
+	for _, block := range strings.Split(strings.ReplaceAll(stream, "\r\n", "\n"), "\n\n") {
+		if strings.TrimSpace(block) == "" {
+			continue
+		}
+		var typ string
+		var data []string
+		for _, line := range strings.Split(block, "\n") {
+			field, value, _ := strings.Cut(line, ":")
+			value = strings.TrimPrefix(value, " ")
+			switch field {
+			case "event":
+				typ = value
+			case "data":
+				data = append(data, value)
+			case "", "id", "retry":
+				// Comments and reconnection hints carry no patch.
+			default:
+				return nil, fmt.Errorf("unexpected SSE field %q", field)
+			}
+		}
+		if typ == "" && data == nil {
+			continue
+		}
+		ev, err := parseEvent(datastar.EventType(typ), data)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, ev)
+	}
+	return events, nil
+}
+
+func parseEvent(typ datastar.EventType, data []string) (Event, error) {
+	switch typ {
+	case datastar.EventTypePatchElements:
+		p := PatchElements{Mode: datastar.ElementPatchModeOuter}
+		var elements []string
+		for _, line := range data {
+			key, value, _ := strings.Cut(line, " ")
+			switch key + " " {
+			case datastar.SelectorDatalineLiteral:
+				p.Selector = value
+			case datastar.ModeDatalineLiteral:
+				p.Mode = datastar.ElementPatchMode(value)
+			case datastar.NamespaceDatalineLiteral:
+				p.Namespace = datastar.Namespace(value)
+			case datastar.UseViewTransitionDatalineLiteral:
+				p.UseViewTransition = value == "true"
+			case datastar.ElementsDatalineLiteral:
+				elements = append(elements, value)
+			default:
+				return nil, fmt.Errorf("unexpected %s data line %q", typ, line)
+			}
+		}
+		p.Elements = strings.Join(elements, "\n")
+		return p, nil
+	case datastar.EventTypePatchSignals:
+		p := PatchSignals{}
+		var signals []string
+		for _, line := range data {
+			key, value, _ := strings.Cut(line, " ")
+			switch key + " " {
+			case datastar.SignalsDatalineLiteral:
+				signals = append(signals, value)
+			case datastar.OnlyIfMissingDatalineLiteral:
+				p.OnlyIfMissing = value == "true"
+			default:
+				return nil, fmt.Errorf("unexpected %s data line %q", typ, line)
+			}
+		}
+		if err := json.Unmarshal([]byte(strings.Join(signals, "\n")), &p.Signals); err != nil {
+			return nil, fmt.Errorf("decoding signals patch: %w", err)
+		}
+		return p, nil
+	default:
+		return nil, fmt.Errorf("unknown event type %q", typ)
+	}
+}
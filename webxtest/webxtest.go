@@ -0,0 +1,244 @@
+// Package webxtest runs webx SSE handlers in-process, without a browser.
+//
+// It builds the requests Datastar would send, with namespaced signals and,
+// through a Session, the session cookie and CSRF token, then runs a handler
+// and decodes the SSE stream it writes into typed events:
+//
+//	func TestNavigate(t *testing.T) {
+//	    res := webxtest.Serve(t, calendar.NavigateHandler("cal", calendar.ModeSingle), webxtest.Request{
+//	        Method:  http.MethodPost,
+//	        ID:      "cal",
+//	        Signals: map[string]any{"year": 2025, "month": 12, "direction": 1},
+//	    })
+//	    if got := res.ComponentSignals("cal")["year"]; got != 2026.0 {
+//	        t.Errorf("year = %v", got)
+//	    }
+//	}
+package webxtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/plaenen/webx"
+	"github.com/plaenen/webx/session"
+	"github.com/starfederation/datastar-go/datastar"
+)
+
+// File is a file attached to a multipart request.
+type File struct {
+	// Field is the form field name. Defaults to "files".
+	Field       string
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// Request describes a Datastar request.
+type Request struct {
+	// Method defaults to GET.
+	Method string
+	// Target is the request path and query. Defaults to "/".
+	Target string
+	// ID is the component ID. It is added as the "id" query parameter and
+	// Signals are namespaced under it, as the component would send them.
+	ID string
+	// Signals are sent in the "datastar" query parameter for GET requests
+	// and as the JSON body otherwise. With an ID they are wrapped as
+	// {webx.SignalID(ID): Signals}; without one they are sent as is.
+	Signals any
+	// Files turns the request into a multipart form upload. Signals are
+	// not sent with files, matching Datastar form submissions.
+	Files []File
+	// Header is added to the request after the defaults, so it can
+	// override them.
+	Header http.Header
+}
+
+// NewRequest builds the *http.Request for req.
+func NewRequest(t testing.TB, req Request) *http.Request {
+	t.Helper()
+	method := req.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	target := req.Target
+	if target == "" {
+		target = "/"
+	}
+	u, err := url.Parse(target)
+	if err != nil {
+		t.Fatalf("webxtest: parsing target %q: %v", target, err)
+	}
+	q := u.Query()
+	if req.ID != "" {
+		q.Set("id", req.ID)
+	}
+
+	var body io.Reader
+	contentType := ""
+	switch {
+	case len(req.Files) > 0:
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		for _, f := range req.Files {
+			writeFile(t, mw, f)
+		}
+		if err := mw.Close(); err != nil {
+			t.Fatalf("webxtest: closing multipart body: %v", err)
+		}
+		body, contentType = &buf, mw.FormDataContentType()
+	case req.Signals != nil:
+		signals := req.Signals
+		if req.ID != "" {
+			signals = map[string]any{webx.SignalID(req.ID): signals}
+		}
+		data, err := json.Marshal(signals)
+		if err != nil {
+			t.Fatalf("webxtest: encoding signals: %v", err)
+		}
+		if method == http.MethodGet {
+			q.Set(datastar.DatastarKey, string(data))
+		} else {
+			body, contentType = bytes.NewReader(data), "application/json"
+		}
+	}
+	u.RawQuery = q.Encode()
+
+	r := httptest.NewRequest(method, u.String(), body)
+	r.Header.Set("Datastar-Request", "true")
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+	for k, vs := range req.Header {
+		r.Header[http.CanonicalHeaderKey(k)] = vs
+	}
+	return r
+}
+
+func writeFile(t testing.TB, mw *multipart.Writer, f File) {
+	t.Helper()
+	field := f.Field
+	if field == "" {
+		field = "files"
+	}
+	h := make(map[string][]string)
+	h["Content-Disposition"] = []string{`form-data; name="` + field + `"; filename="` + f.Name + `"`}
+	if f.ContentType != "" {
+		h["Content-Type"] = []string{f.ContentType}
+	}
+	part, err := mw.CreatePart(h)
+	if err != nil {
+		t.Fatalf("webxtest: creating part for %q: %v", f.Name, err)
+	}
+	if _, err := part.Write(f.Data); err != nil {
+		t.Fatalf("webxtest: writing part for %q: %v", f.Name, err)
+	}
+}
+
+// Serve runs h for req and returns the decoded response. The handler sees
+// an empty WebXContext; use a Session for handlers that need one.
+func Serve(t testing.TB, h http.Handler, req Request) *Response {
+	t.Helper()
+	return do(t, h, NewRequest(t, req))
+}
+
+func do(t testing.TB, h http.Handler, r *http.Request) *Response {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	res := &Response{
+		Code:   rec.Code,
+		Header: rec.Header(),
+		Body:   rec.Body.String(),
+	}
+	if isEventStream(res.Header) {
+		events, err := ParseEvents(res.Body)
+		if err != nil {
+			t.Fatalf("webxtest: %v\n%s", err, res.Body)
+		}
+		res.Events = events
+	}
+	return res
+}
+
+// Session is a browser session backed by an in-memory store. Requests
+// served through it pass webx.SessionMiddleware with the session cookie,
+// the CSRF token and same-origin Origin and Sec-Fetch-Site headers, like a
+// page rendered by the app would send them.
+type Session struct {
+	// Store holds the session data; tests may read or seed it directly.
+	Store *session.MemoryStore
+	// ID is the session ID, as seen in WebXContext.SessionID.
+	ID string
+	// CSRFToken is the session's token, sent as X-CSRF-Token.
+	CSRFToken string
+
+	opts    webx.SessionOptions
+	cookies map[string]*http.Cookie
+}
+
+// NewSession starts a session by sending a first request through
+// webx.SessionMiddleware configured with opts.
+func NewSession(t testing.TB, opts ...webx.SessionOptions) *Session {
+	t.Helper()
+	s := &Session{
+		Store:   session.NewMemoryStore(session.MemoryStoreOptions{SweepInterval: -1}),
+		cookies: map[string]*http.Cookie{},
+	}
+	if len(opts) > 0 {
+		s.opts = opts[0]
+	}
+	t.Cleanup(func() { s.Store.Close() })
+
+	res := s.Serve(t, http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}), Request{})
+	if res.Code != http.StatusOK || s.ID == "" {
+		t.Fatalf("webxtest: starting session: status %d: %s", res.Code, res.Body)
+	}
+	return s
+}
+
+// Serve runs h behind webx.SessionMiddleware for req in this session and
+// returns the decoded response. Cookies set by the response, such as a
+// rotated session ID, are kept for later requests.
+func (s *Session) Serve(t testing.TB, h http.Handler, req Request) *Response {
+	t.Helper()
+	r := NewRequest(t, req)
+	for _, c := range s.cookies {
+		r.AddCookie(c)
+	}
+	setDefault(r.Header, "X-CSRF-Token", s.CSRFToken)
+	setDefault(r.Header, "Origin", "http://"+r.Host)
+	setDefault(r.Header, "Sec-Fetch-Site", "same-origin")
+
+	var wctx *webx.WebXContext
+	capture := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		wctx = webx.FromContext(r.Context())
+		h.ServeHTTP(w, r)
+	})
+	res := do(t, webx.SessionMiddleware(s.Store, s.opts)(capture), r)
+
+	for _, c := range (&http.Response{Header: res.Header}).Cookies() {
+		if c.MaxAge < 0 {
+			delete(s.cookies, c.Name)
+			continue
+		}
+		s.cookies[c.Name] = c
+	}
+	if wctx != nil && wctx.SessionID != "" {
+		s.ID, s.CSRFToken = wctx.SessionID, wctx.CSRFToken
+	}
+	return res
+}
+
+func setDefault(h http.Header, key, value string) {
+	if h.Get(key) == "" && value != "" {
+		h.Set(key, value)
+	}
+}
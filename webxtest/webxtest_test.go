@@ -0,0 +1,138 @@
+package webxtest_test
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/plaenen/webx/ui/calendar"
+	"github.com/plaenen/webx/ui/fileupload"
+	"github.com/plaenen/webx/webxtest"
+	"github.com/starfederation/datastar-go/datastar"
+)
+
+func TestServe_CalendarNavigate(t *testing.T) {
+	h := calendar.NavigateHandler("due-date", calendar.ModeSingle)
+	res := webxtest.Serve(t, h, webxtest.Request{
+		Method:  http.MethodPost,
+		ID:      "due-date",
+		Signals: map[string]any{"year": 2025, "month": 12, "direction": 1},
+	})
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", res.Code, res.Body)
+	}
+
+	var got struct {
+		Year  int `json:"year"`
+		Month int `json:"month"`
+	}
+	if err := res.DecodeSignals("due-date", &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Year != 2026 || got.Month != 1 {
+		t.Errorf("signals = %+v, want January 2026", got)
+	}
+
+	els := res.Elements()
+	if len(els) != 1 || els[0].Mode != datastar.ElementPatchModeOuter || !strings.Contains(els[0].Elements, `id="due-date"`) {
+		t.Errorf("elements = %+v", els)
+	}
+
+	res = webxtest.Serve(t, h, webxtest.Request{ID: "due-date", Signals: map[string]any{}, Target: "/?x=1"})
+	if res.Code != http.StatusOK {
+		t.Errorf("GET with signals in the query: status = %d: %s", res.Code, res.Body)
+	}
+	if res = webxtest.Serve(t, h, webxtest.Request{Method: http.MethodPost}); res.Code != http.StatusBadRequest || res.Events != nil {
+		t.Errorf("missing signals: status = %d, events = %v", res.Code, res.Events)
+	}
+}
+
+func TestSession_FileUpload(t *testing.T) {
+	store := fileupload.NewStore()
+	upload := fileupload.UploadHandler(store, fileupload.WithAllowedTypes("image/"))
+	sess := webxtest.NewSession(t)
+
+	req := webxtest.Request{
+		Method: http.MethodPost,
+		Target: "/api/upload",
+		ID:     "avatar",
+		Files: []webxtest.File{
+			{Name: "me.png", ContentType: "image/png", Data: []byte("png")},
+			{Name: "notes.txt", ContentType: "text/plain", Data: []byte("txt")},
+		},
+	}
+	res := sess.Serve(t, upload, req)
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", res.Code, res.Body)
+	}
+	els := res.Elements()
+	if len(els) != 2 {
+		t.Fatalf("got %d element patches, want 2", len(els))
+	}
+	if els[0].Selector != "#avatar-list" || els[0].Mode != datastar.ElementPatchModeInner || !strings.Contains(els[0].Elements, "me.png") {
+		t.Errorf("list patch = %+v", els[0])
+	}
+	if !strings.Contains(els[1].Elements, "notes.txt: type text/plain not allowed") {
+		t.Errorf("errors patch = %+v", els[1])
+	}
+
+	// The files belong to this session only.
+	other := webxtest.NewSession(t)
+	req.Files = req.Files[:1]
+	if res := other.Serve(t, upload, req); strings.Count(res.Elements()[0].Elements, "me.png") != 1 {
+		t.Error("another session saw this session's files")
+	}
+	if res := sess.Serve(t, upload, req); strings.Count(res.Elements()[0].Elements, "me.png") != 2 {
+		t.Error("session did not keep its files across requests")
+	}
+
+	req.Header = http.Header{"X-Csrf-Token": {"forged"}}
+	if res := sess.Serve(t, upload, req); res.Code != http.StatusForbidden {
+		t.Errorf("forged CSRF token: status = %d, want 403", res.Code)
+	}
+}
+
+func TestParseEvents(t *testing.T) {
+	stream := "event: datastar-patch-elements\n" +
+		"data: selector #list\n" +
+		"data: mode append\n" +
+		"data: useViewTransition true\n" +
+		"data: elements <li>one</li>\n" +
+		"data: elements <li>two</li>\n\n" +
+		": keep-alive\n\n" +
+		"event: datastar-patch-signals\n" +
+		"data: signals {\"form\": {\"error\": \"bad\", \"count\": 1}}\n\n" +
+		"event: datastar-patch-signals\n" +
+		"data: signals {\"form\": {\"error\": null, \"count\": 5, \"new\": true}}\n" +
+		"data: onlyIfMissing true\n\n" +
+		"event: datastar-patch-signals\n" +
+		"data: signals {\"form\": {\"count\": 2}}\n\n"
+
+	events, err := webxtest.ParseEvents(stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := &webxtest.Response{Events: events}
+
+	want := webxtest.PatchElements{
+		Selector:          "#list",
+		Mode:              datastar.ElementPatchModeAppend,
+		UseViewTransition: true,
+		Elements:          "<li>one</li>\n<li>two</li>",
+	}
+	if els := res.Elements(); len(els) != 1 || els[0] != want {
+		t.Errorf("elements = %+v, want %+v", els, want)
+	}
+	if n := len(res.SignalPatches()); n != 3 {
+		t.Errorf("got %d signal patches, want 3", n)
+	}
+	wantSignals := map[string]any{"error": "bad", "count": 2.0, "new": true}
+	if got := res.ComponentSignals("form"); !reflect.DeepEqual(got, wantSignals) {
+		t.Errorf("merged signals = %v, want %v", got, wantSignals)
+	}
+
+	if _, err := webxtest.ParseEvents("event: datastar-execute-script\ndata: script x\n\n"); err == nil {
+		t.Error("unknown event type accepted")
+	}
+}